@@ -3,14 +3,25 @@ package main
 import (
 	"checkoutProject/pkg/bootstrap"
 	"log"
+	"os"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		err := runMigrateCommand(os.Args[2:])
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		return
+	}
+
 	err := bootstrap.Initialize()
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	r := bootstrap.SetupRouter()
-	r.Run("0.0.0.0:8080")
+	err = bootstrap.Serve(bootstrap.NewServer(bootstrap.SetupRouter()))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/database"
+	"fmt"
+	"strconv"
+)
+
+const migrateUsage = "usage: migrate up | down [N] | status"
+
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf(migrateUsage)
+	}
+
+	err := bootstrap.InitializeWithoutMigrations()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp()
+		for _, migration := range applied {
+			fmt.Printf("applied %s\n", migration)
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("number of migrations to revert must be a positive number")
+			}
+		}
+
+		reverted, err := database.MigrateDown(steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %s\n", migration)
+		}
+		return err
+	case "status":
+		status, err := database.GetMigrationStatus()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("version: %d, dirty: %t\n", status.Version, status.Dirty)
+		for _, migration := range status.Applied {
+			fmt.Printf("applied %s\n", migration)
+		}
+		for _, migration := range status.Pending {
+			fmt.Printf("pending %s\n", migration)
+		}
+		return nil
+	default:
+		return fmt.Errorf(migrateUsage)
+	}
+}
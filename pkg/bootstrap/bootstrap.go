@@ -1,21 +1,51 @@
 package bootstrap
 
 import (
+	"checkoutProject/pkg/common/auth"
 	"checkoutProject/pkg/common/database"
 	"checkoutProject/pkg/common/env"
 	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/metrics"
+	"checkoutProject/pkg/common/routing"
 	"checkoutProject/pkg/handlers/cart"
+	"checkoutProject/pkg/handlers/catalog"
+	"checkoutProject/pkg/handlers/health"
+	"checkoutProject/pkg/handlers/idempotency"
 	"checkoutProject/pkg/handlers/item"
+	"checkoutProject/pkg/handlers/order"
 	"github.com/gin-gonic/gin"
 )
 
+// Initialize connects to the database and applies the pending migrations.
 func Initialize() error {
-	log, err := logger.Initialize()
+	err := InitializeWithoutMigrations()
+	if err != nil {
+		return err
+	}
+	log := logger.GetInstance()
+
+	applied, err := database.MigrateUp()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range applied {
+		log.Infof("applied migration %s", migration)
+	}
+	log.Info("database schema is up to date")
+
+	return nil
+}
+
+// InitializeWithoutMigrations connects to the database without touching the schema, it is used by the migrate command.
+func InitializeWithoutMigrations() error {
+	// the environment is read first since the log format and level come from it
+	err := env.Load()
 	if err != nil {
 		return err
 	}
 
-	err = env.Load()
+	log, err := logger.Initialize()
 	if err != nil {
 		return err
 	}
@@ -30,15 +60,32 @@ func Initialize() error {
 	return nil
 }
 
-func RegisterRouters(r *gin.Engine) {
-	apiRouter := r.Group("/api/cart")
+// RegisterRouters registers the health endpoints without authentication and the cart and order endpoints behind the
+// authenticator, the managers of those endpoints only see the carts of the authenticated user. Carts and their orders
+// can be used by guests in their guest session before they log in. The admin endpoints can only be used by the users
+// in ADMIN_USER_IDS.
+func RegisterRouters(r *gin.Engine, authenticator auth.Authenticator) {
+	health.NewDefaultHealthRouter().Register(r.Group("/"))
+
+	apiRouter := r.Group("/api/carts/:cart_id")
+	apiRouter.Use(auth.GuestMiddleware(authenticator), idempotency.NewDefaultMiddleware())
 	item.NewDefaultItemRouter().Register(apiRouter)
 	item.NewDefaultVasItemRouter().Register(apiRouter)
 	cart.NewDefaultCartRouter().Register(apiRouter)
+
+	orderRouter := r.Group("/api")
+	orderRouter.Use(auth.GuestMiddleware(authenticator))
+	order.NewDefaultOrderRouter().Register(orderRouter)
+
+	adminRouter := r.Group("/api/admin")
+	adminRouter.Use(auth.AdminMiddleware(authenticator, env.ADMIN_USER_IDS))
+	catalog.NewDefaultCatalogRouter().Register(adminRouter)
 }
 
 func SetupRouter() *gin.Engine {
-	r := gin.Default()
-	RegisterRouters(r)
+	r := gin.New()
+	r.Use(gin.Recovery(), logger.Middleware(), metrics.Middleware(), routing.Timeout(env.SERVER.RequestTimeout))
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	RegisterRouters(r, auth.NewDefaultJWTAuthenticator())
 	return r
 }
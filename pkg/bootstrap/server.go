@@ -0,0 +1,68 @@
+package bootstrap
+
+import (
+	"checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"errors"
+	"net/http"
+	"os/signal"
+	"syscall"
+)
+
+func NewServer(handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              env.SERVER.Address,
+		Handler:           handler,
+		ReadTimeout:       env.SERVER.ReadTimeout,
+		ReadHeaderTimeout: env.SERVER.ReadHeaderTimeout,
+		WriteTimeout:      env.SERVER.WriteTimeout,
+		IdleTimeout:       env.SERVER.IdleTimeout,
+	}
+}
+
+// Serve runs the server until SIGINT or SIGTERM is received, then it stops accepting new connections, waits for the
+// active requests up to the shutdown timeout and closes the database pool. The pool is closed on every return, also
+// when the server cannot start.
+func Serve(server *http.Server) (err error) {
+	log := logger.GetInstance()
+
+	defer func() {
+		if closeErr := database.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("error while closing the database connections")
+			if err == nil {
+				err = closeErr
+			}
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Infof("listening on %s", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	case <-ctx.Done():
+		log.Info("shutting down, waiting for the active requests")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), env.SERVER.ShutdownTimeout)
+	defer cancel()
+
+	err = server.Shutdown(shutdownCtx)
+	if err != nil {
+		log.WithError(err).Error("error while shutting down the server")
+	}
+	log.Info("server stopped")
+
+	return err
+}
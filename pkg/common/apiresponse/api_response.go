@@ -3,6 +3,7 @@ package apiresponse
 import (
 	errs "checkoutProject/pkg/common/errors"
 	"errors"
+	"gorm.io/gorm"
 	"net/http"
 )
 
@@ -30,5 +31,19 @@ func Failed(err error) (int, interface{}) {
 		responseCode = errs.RecordNotFoundErrCode
 	}
 
+	if errors.Is(err, errs.UnauthorizedErr) {
+		responseCode = errs.UnauthorizedErrCode
+	}
+
+	if errors.Is(err, errs.ForbiddenErr) {
+		responseCode = errs.ForbiddenErrCode
+	}
+
+	// a row that breaks a unique index of the database, the message does not leak the name of the index
+	if errors.Is(err, errs.ConflictErr) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		responseCode = errs.ConflictErrCode
+		genericResponse.Message = errs.ConflictErr.Error()
+	}
+
 	return responseCode, genericResponse
 }
@@ -0,0 +1,41 @@
+package apiresponse
+
+import (
+	errs "checkoutProject/pkg/common/errors"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+func TestFailed(t *testing.T) {
+	Convey("TEST errors of the request are bad requests", t, func() {
+		code, response := Failed(fmt.Errorf("total price of cart cannot be over 500000.00"))
+		So(code, ShouldEqual, http.StatusBadRequest)
+		So(response, ShouldResemble, GenericResponse{Result: false, Message: "total price of cart cannot be over 500000.00"})
+	})
+
+	Convey("TEST known errors have their own codes", t, func() {
+		code, _ := Failed(errs.InternalServerErr)
+		So(code, ShouldEqual, http.StatusInternalServerError)
+
+		code, _ = Failed(errs.RecordNotFoundErr)
+		So(code, ShouldEqual, http.StatusNotFound)
+
+		code, _ = Failed(errs.UnauthorizedErr)
+		So(code, ShouldEqual, http.StatusUnauthorized)
+
+		code, _ = Failed(errs.ForbiddenErr)
+		So(code, ShouldEqual, http.StatusForbidden)
+	})
+
+	Convey("TEST unique violations are conflicts", t, func() {
+		code, response := Failed(fmt.Errorf("error while creating item: %w", gorm.ErrDuplicatedKey))
+		So(code, ShouldEqual, http.StatusConflict)
+		So(response, ShouldResemble, GenericResponse{Result: false, Message: "record already exists"})
+
+		code, _ = Failed(errs.ConflictErr)
+		So(code, ShouldEqual, http.StatusConflict)
+	})
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"checkoutProject/pkg/common/apiresponse"
+	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GUEST_USER_ID is the user of the requests sent without credentials, guests own the carts of their guest session.
+const GUEST_USER_ID = 0
+
+type userIDKey struct{}
+
+// NoCredentialsErr is returned by the authenticators when the request does not have any credentials.
+var NoCredentialsErr = errors.New("no credentials")
+
+// Authenticator returns the ID of the user who sent the request, or an error when the request cannot be authenticated.
+type Authenticator interface {
+	Authenticate(r *http.Request) (uint, error)
+}
+
+// Middleware rejects the requests the authenticator cannot authenticate with 401 and keeps the user ID of the others
+// in the request context, the managers read it from there to limit the queries to the carts of the user.
+func Middleware(authenticator Authenticator) gin.HandlerFunc {
+	return authenticate(authenticator, false)
+}
+
+// GuestMiddleware works like Middleware but lets the requests without credentials in as the guest user, requests
+// with invalid credentials are still rejected. The guests without a guest token get a new one in the response.
+func GuestMiddleware(authenticator Authenticator) gin.HandlerFunc {
+	return authenticate(authenticator, true)
+}
+
+// AdminMiddleware works like Middleware and also rejects the users who are not admins with 403.
+func AdminMiddleware(authenticator Authenticator, adminUserIDs []uint) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := authenticateRequest(c, authenticator, false)
+		if !ok {
+			return
+		}
+
+		for _, adminUserID := range adminUserIDs {
+			if userID == adminUserID {
+				c.Next()
+				return
+			}
+		}
+
+		logger.FromContext(c.Request.Context()).Warn("user is not an admin")
+		c.AbortWithStatusJSON(apiresponse.Failed(errs.ForbiddenErr))
+	}
+}
+
+func authenticate(authenticator Authenticator, allowGuests bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := authenticateRequest(c, authenticator, allowGuests); ok {
+			c.Next()
+		}
+	}
+}
+
+// authenticateRequest keeps the user ID and the guest session in the request context, or aborts the request with 401
+// and returns false. Users can send a guest token too, to prove that they own a guest cart.
+func authenticateRequest(c *gin.Context, authenticator Authenticator, allowGuests bool) (uint, bool) {
+	ctx := c.Request.Context()
+
+	userID, err := authenticator.Authenticate(c.Request)
+	if allowGuests && errors.Is(err, NoCredentialsErr) {
+		userID, err = GUEST_USER_ID, nil
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("could not authenticate the request")
+		c.Header("WWW-Authenticate", "Bearer")
+		c.AbortWithStatusJSON(apiresponse.Failed(errs.UnauthorizedErr))
+		return 0, false
+	}
+
+	guestToken, hasGuestToken, err := guestTokenOfRequest(c.Request)
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).Warn("could not read the guest token of the request")
+		c.AbortWithStatusJSON(apiresponse.Failed(errs.UnauthorizedErr))
+		return 0, false
+	}
+
+	// every guest gets a session, the client keeps the token of the response and sends it with the next requests
+	if !hasGuestToken && userID == GUEST_USER_ID {
+		guestToken, err = NewGuestToken()
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).Error("error while creating the guest token")
+			c.AbortWithStatusJSON(apiresponse.Failed(errs.InternalServerErr))
+			return 0, false
+		}
+
+		c.Header(GUEST_TOKEN_HEADER, guestToken)
+		hasGuestToken = true
+	}
+
+	entry := logger.FromContext(ctx).WithField("user_id", userID)
+	ctx = logger.NewContext(NewContext(ctx, userID), entry)
+	if hasGuestToken {
+		ctx = NewGuestContext(ctx, GuestSession(guestToken))
+	}
+	c.Request = c.Request.WithContext(ctx)
+
+	return userID, true
+}
+
+// NewContext returns a copy of the context that carries the user ID.
+func NewContext(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFromContext returns the ID of the authenticated user or GUEST_USER_ID, it returns false outside of the middleware.
+func UserIDFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+
+	userID, ok := ctx.Value(userIDKey{}).(uint)
+	return userID, ok
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"checkoutProject/pkg/common/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newRequest(token string) *http.Request {
+	request := httptest.NewRequest(http.MethodGet, "/api/carts/1", nil)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	return request
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	authenticator := NewJWTAuthenticator(testKey)
+
+	Convey("TEST valid token returns the user ID", t, func() {
+		token, err := NewToken(testKey, 42, time.Hour)
+		So(err, ShouldBeNil)
+
+		userID, err := authenticator.Authenticate(newRequest(token))
+		So(err, ShouldBeNil)
+		So(userID, ShouldEqual, 42)
+	})
+
+	Convey("TEST missing token error", t, func() {
+		_, err := authenticator.Authenticate(newRequest(""))
+		So(err, ShouldEqual, NoCredentialsErr)
+	})
+
+	Convey("TEST authorization header without bearer token error", t, func() {
+		request := newRequest("")
+		request.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+		_, err := authenticator.Authenticate(request)
+		So(err, ShouldNotBeNil)
+		So(err, ShouldNotEqual, NoCredentialsErr)
+	})
+
+	Convey("TEST token signed with another key error", t, func() {
+		token, err := NewToken([]byte("another key of the same length 32"), 42, time.Hour)
+		So(err, ShouldBeNil)
+
+		_, err = authenticator.Authenticate(newRequest(token))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST expired token error", t, func() {
+		token, err := NewToken(testKey, 42, -time.Hour)
+		So(err, ShouldBeNil)
+
+		_, err = authenticator.Authenticate(newRequest(token))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST token without expiration error", t, func() {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{Subject: "42"}).SignedString(testKey)
+		So(err, ShouldBeNil)
+
+		_, err = authenticator.Authenticate(newRequest(token))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST token with another algorithm error", t, func() {
+		claims := jwt.RegisteredClaims{Subject: "42", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString(testKey)
+		So(err, ShouldBeNil)
+
+		_, err = authenticator.Authenticate(newRequest(token))
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST subject that is not a user ID error", t, func() {
+		claims := jwt.RegisteredClaims{Subject: "admin", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testKey)
+		So(err, ShouldBeNil)
+
+		_, err = authenticator.Authenticate(newRequest(token))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	_, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(NewJWTAuthenticator(testKey)))
+
+	var userIDOfContext uint
+	var isUserInContext bool
+	r.GET("/api/carts/:cart_id", func(c *gin.Context) {
+		userIDOfContext, isUserInContext = UserIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	Convey("TEST authenticated user is kept in the request context", t, func() {
+		token, err := NewToken(testKey, 7, time.Hour)
+		So(err, ShouldBeNil)
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, newRequest(token))
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(isUserInContext, ShouldBeTrue)
+		So(userIDOfContext, ShouldEqual, 7)
+	})
+
+	Convey("TEST request without a valid token is rejected", t, func() {
+		isUserInContext = false
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, newRequest("not-a-token"))
+		So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+		So(recorder.Header().Get("WWW-Authenticate"), ShouldEqual, "Bearer")
+		So(recorder.Body.String(), ShouldEqual, `{"result":false,"message":"unauthorized"}`)
+		So(isUserInContext, ShouldBeFalse)
+	})
+}
+
+func TestGuestMiddleware(t *testing.T) {
+	_, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(GuestMiddleware(NewJWTAuthenticator(testKey)))
+
+	var userIDOfContext uint
+	var isUserInContext bool
+	var guestSessionOfContext string
+	var isGuestSessionInContext bool
+	r.GET("/api/carts/:cart_id", func(c *gin.Context) {
+		userIDOfContext, isUserInContext = UserIDFromContext(c.Request.Context())
+		guestSessionOfContext, isGuestSessionInContext = GuestSessionFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	guestToken, err := NewGuestToken()
+	if err != nil {
+		t.Fatalf("error while creating the guest token: %v", err)
+	}
+
+	Convey("TEST request without credentials is handled as the guest user of a new guest session", t, func() {
+		userIDOfContext = 7
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, newRequest(""))
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(isUserInContext, ShouldBeTrue)
+		So(userIDOfContext, ShouldEqual, GUEST_USER_ID)
+
+		newGuestToken := recorder.Header().Get(GUEST_TOKEN_HEADER)
+		So(newGuestToken, ShouldHaveLength, 2*GUEST_TOKEN_LENGTH)
+		So(isGuestSessionInContext, ShouldBeTrue)
+		So(guestSessionOfContext, ShouldEqual, GuestSession(newGuestToken))
+	})
+
+	Convey("TEST guest keeps the guest session of its guest token", t, func() {
+		request := newRequest("")
+		request.Header.Set(GUEST_TOKEN_HEADER, guestToken)
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(recorder.Header().Get(GUEST_TOKEN_HEADER), ShouldBeEmpty)
+		So(isGuestSessionInContext, ShouldBeTrue)
+		So(guestSessionOfContext, ShouldEqual, GuestSession(guestToken))
+		So(guestSessionOfContext, ShouldNotEqual, guestToken)
+	})
+
+	Convey("TEST user keeps the guest session of the guest token it sends", t, func() {
+		token, err := NewToken(testKey, 7, time.Hour)
+		So(err, ShouldBeNil)
+
+		request := newRequest(token)
+		request.Header.Set(GUEST_TOKEN_HEADER, guestToken)
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(userIDOfContext, ShouldEqual, 7)
+		So(isGuestSessionInContext, ShouldBeTrue)
+		So(guestSessionOfContext, ShouldEqual, GuestSession(guestToken))
+	})
+
+	Convey("TEST user without a guest token has no guest session", t, func() {
+		token, err := NewToken(testKey, 7, time.Hour)
+		So(err, ShouldBeNil)
+
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, newRequest(token))
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(recorder.Header().Get(GUEST_TOKEN_HEADER), ShouldBeEmpty)
+		So(isGuestSessionInContext, ShouldBeFalse)
+	})
+
+	Convey("TEST request with an invalid token is still rejected", t, func() {
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, newRequest("not-a-token"))
+		So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+	})
+
+	Convey("TEST request with an invalid guest token is rejected", t, func() {
+		request := newRequest("")
+		request.Header.Set(GUEST_TOKEN_HEADER, "cart-30")
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, request)
+		So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+	})
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	_, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AdminMiddleware(NewJWTAuthenticator(testKey), []uint{3, 9}))
+
+	isHandled := false
+	r.GET("/api/carts/:cart_id", func(c *gin.Context) {
+		isHandled = true
+		c.Status(http.StatusOK)
+	})
+
+	Convey("TEST request of an admin is handled", t, func() {
+		isHandled = false
+		token, err := NewToken(testKey, 9, time.Hour)
+		So(err, ShouldBeNil)
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, newRequest(token))
+		So(recorder.Code, ShouldEqual, http.StatusOK)
+		So(isHandled, ShouldBeTrue)
+	})
+
+	Convey("TEST request of another user is forbidden", t, func() {
+		isHandled = false
+		token, err := NewToken(testKey, 4, time.Hour)
+		So(err, ShouldBeNil)
+
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, newRequest(token))
+		So(recorder.Code, ShouldEqual, http.StatusForbidden)
+		So(isHandled, ShouldBeFalse)
+	})
+
+	Convey("TEST request without credentials is rejected", t, func() {
+		isHandled = false
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, newRequest(""))
+		So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+		So(isHandled, ShouldBeFalse)
+	})
+}
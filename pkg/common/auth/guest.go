@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+// GUEST_TOKEN_HEADER carries the secret of the guest session, it is sent back to the guests that do not have one yet.
+const GUEST_TOKEN_HEADER = "Guest-Token"
+
+// GUEST_TOKEN_LENGTH is the number of random bytes of a guest token, the header carries them hex encoded.
+const GUEST_TOKEN_LENGTH = 32
+
+type guestSessionKey struct{}
+
+// NewGuestToken returns a random guest token.
+func NewGuestToken() (string, error) {
+	token := make([]byte, GUEST_TOKEN_LENGTH)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(token), nil
+}
+
+// GuestSession returns the guest session of the token, the carts keep the session instead of the token so the tokens
+// cannot be read from the database.
+func GuestSession(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// guestTokenOfRequest returns the guest token of the request, or false when the request does not have one.
+func guestTokenOfRequest(r *http.Request) (string, bool, error) {
+	token := r.Header.Get(GUEST_TOKEN_HEADER)
+	if token == "" {
+		return "", false, nil
+	}
+
+	if decoded, err := hex.DecodeString(token); err != nil || len(decoded) != GUEST_TOKEN_LENGTH {
+		return "", false, fmt.Errorf("guest token is not %d hex encoded bytes", GUEST_TOKEN_LENGTH)
+	}
+
+	return token, true, nil
+}
+
+// NewGuestContext returns a copy of the context that carries the guest session.
+func NewGuestContext(ctx context.Context, guestSession string) context.Context {
+	return context.WithValue(ctx, guestSessionKey{}, guestSession)
+}
+
+// GuestSessionFromContext returns the guest session of the request, it returns false when the request did not send a
+// guest token and is not sent by a guest.
+func GuestSessionFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+
+	guestSession, ok := ctx.Value(guestSessionKey{}).(string)
+	return guestSession, ok
+}
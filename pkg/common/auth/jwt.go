@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"checkoutProject/pkg/common/env"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LEEWAY is the clock skew allowed between the token issuer and the server.
+const LEEWAY = 30 * time.Second
+
+type jwtAuthenticator struct {
+	key []byte
+}
+
+// NewJWTAuthenticator authenticates the requests with HS256 signed JWT bearer tokens, the subject claim of the token
+// is the user ID and the expiration claim is required.
+func NewJWTAuthenticator(key []byte) Authenticator {
+	return jwtAuthenticator{key: key}
+}
+
+func NewDefaultJWTAuthenticator() Authenticator {
+	return NewJWTAuthenticator([]byte(env.JWT_SECRET))
+}
+
+func (a jwtAuthenticator) Authenticate(r *http.Request) (uint, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return 0, NoCredentialsErr
+	}
+
+	tokenString, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || tokenString == "" {
+		return 0, fmt.Errorf("authorization header is not a bearer token")
+	}
+
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
+		return a.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired(), jwt.WithLeeway(LEEWAY))
+	if err != nil {
+		return 0, err
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 0)
+	if err != nil || userID == 0 {
+		return 0, fmt.Errorf("invalid subject %q, it must be a user ID", claims.Subject)
+	}
+
+	return uint(userID), nil
+}
+
+// NewToken signs a token for the user that expires after the given duration, it is used by the tests.
+func NewToken(key []byte, userID uint, expiresIn time.Duration) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.FormatUint(uint64(userID), 10),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+	})
+
+	return token.SignedString(key)
+}
@@ -2,6 +2,8 @@ package database
 
 import (
 	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/metrics"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -14,13 +16,20 @@ var db *gorm.DB
 
 func Initialize() error {
 	var err error
+	// unique violations are returned as gorm.ErrDuplicatedKey, see apiresponse.Failed
 	db, err = gorm.Open(postgres.Open(env.DB_URL), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
 	})
 	if err != nil {
 		return fmt.Errorf("cannot connect to the database. Error: %s", err.Error())
 	}
 
+	err = metrics.InstrumentDatabase(db)
+	if err != nil {
+		return fmt.Errorf("cannot instrument the database. Error: %s", err.Error())
+	}
+
 	return nil
 }
 
@@ -32,8 +41,22 @@ func GetInstance() *gorm.DB {
 	return db
 }
 
-func NewTransaction() *gorm.DB {
-	return GetInstance().Begin()
+// Close closes the connection pool, it is called once the server stopped serving requests.
+func Close() error {
+	if db == nil {
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// NewTransaction begins a transaction bound to the context, it is rolled back when the context is cancelled.
+func NewTransaction(ctx context.Context) *gorm.DB {
+	return GetInstance().WithContext(ctx).Begin()
 }
 
 func CommitTransaction(tx *gorm.DB) error {
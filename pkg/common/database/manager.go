@@ -1,6 +1,10 @@
 package database
 
-import "gorm.io/gorm"
+import (
+	"checkoutProject/pkg/common/auth"
+	"context"
+	"gorm.io/gorm"
+)
 
 type BaseManager struct {
 	DB *gorm.DB
@@ -14,6 +18,12 @@ func (m BaseManager) WithTx(tx *gorm.DB) BaseManager {
 	return m
 }
 
+// WithContext makes the queries of the manager use the context, so they are cancelled with the request.
+func (m BaseManager) WithContext(ctx context.Context) BaseManager {
+	m.DB = m.DB.WithContext(ctx)
+	return m
+}
+
 func NewBaseManager(tx *gorm.DB) BaseManager {
 	m := BaseManager{
 		DB: GetInstance(),
@@ -25,3 +35,23 @@ func NewBaseManager(tx *gorm.DB) BaseManager {
 
 	return m
 }
+
+// OwnedCarts limits the query to the rows of the carts owned by the user in the context of the query, see
+// auth.Middleware. Guests see the carts of their guest session, queries without a user do not match any row.
+func OwnedCarts(q *gorm.DB, table string) *gorm.DB {
+	userID, ok := auth.UserIDFromContext(q.Statement.Context)
+	if !ok {
+		return q.Where("FALSE")
+	}
+
+	if userID == auth.GUEST_USER_ID {
+		guestSession, ok := auth.GuestSessionFromContext(q.Statement.Context)
+		if !ok {
+			return q.Where("FALSE")
+		}
+
+		return q.Where(table+".cart_id IN (SELECT carts.id FROM carts WHERE carts.deleted_at IS NULL AND carts.user_id = ? AND carts.guest_session = ?)", userID, guestSession)
+	}
+
+	return q.Where(table+".cart_id IN (SELECT carts.id FROM carts WHERE carts.deleted_at IS NULL AND carts.user_id = ?)", userID)
+}
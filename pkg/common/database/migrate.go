@@ -0,0 +1,268 @@
+package database
+
+import (
+	"checkoutProject/pkg/common/database/migrations"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// migrationLockKey is the key of the advisory lock taken while migrating, so only one instance migrates at a time.
+const migrationLockKey = 8275104963
+
+// schema_migrations is the same table the migrate CLI uses, databases migrated with the CLI keep working.
+const createSchemaMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT NOT NULL PRIMARY KEY,
+    dirty BOOLEAN NOT NULL
+    )`
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%06d_%s", m.Version, m.Name)
+}
+
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Applied []Migration
+	Pending []Migration
+}
+
+// Migrations returns the embedded migrations ordered by version.
+func Migrations() ([]Migration, error) {
+	files, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	migrationsByVersion := make(map[uint]*Migration)
+	for _, file := range files {
+		match := migrationFileName.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", file.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s", file.Name())
+		}
+
+		content, err := fs.ReadFile(migrations.FS, file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := migrationsByVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			migrationsByVersion[uint(version)] = migration
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(migrationsByVersion))
+	for _, migration := range migrationsByVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s must have both up and down files", migration)
+		}
+		result = append(result, *migration)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+	return result, nil
+}
+
+// LatestMigrationVersion returns the version the database has after every embedded migration is applied.
+func LatestMigrationVersion() (uint, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// MigrateUp applies the pending migrations, each one in its own transaction, and returns the applied ones.
+func MigrateUp() ([]Migration, error) {
+	applied := make([]Migration, 0)
+
+	err := withMigrationLock(func(conn *gorm.DB) error {
+		migrations, err := Migrations()
+		if err != nil {
+			return err
+		}
+
+		version, err := currentMigrationVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if migration.Version <= version {
+				continue
+			}
+
+			err = runMigration(conn, migration.Up, migration.Version)
+			if err != nil {
+				return fmt.Errorf("cannot apply migration %s: %w", migration, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// MigrateDown reverts the given number of applied migrations, the latest one first, and returns the reverted ones.
+func MigrateDown(steps int) ([]Migration, error) {
+	reverted := make([]Migration, 0)
+
+	err := withMigrationLock(func(conn *gorm.DB) error {
+		migrations, err := Migrations()
+		if err != nil {
+			return err
+		}
+
+		version, err := currentMigrationVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := migrations[i]
+			if migration.Version > version {
+				continue
+			}
+
+			var previousVersion uint
+			if i > 0 {
+				previousVersion = migrations[i-1].Version
+			}
+
+			err = runMigration(conn, migration.Down, previousVersion)
+			if err != nil {
+				return fmt.Errorf("cannot revert migration %s: %w", migration, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	return reverted, err
+}
+
+func GetMigrationStatus() (MigrationStatus, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	version, dirty, err := GetMigrationVersion(GetInstance())
+	if err != nil {
+		return MigrationStatus{}, err
+	}
+
+	status := MigrationStatus{Version: version, Dirty: dirty, Applied: make([]Migration, 0), Pending: make([]Migration, 0)}
+	for _, migration := range migrations {
+		if migration.Version <= version {
+			status.Applied = append(status.Applied, migration)
+		} else {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+func withMigrationLock(migrate func(conn *gorm.DB) error) error {
+	// advisory locks belong to the session, so the lock, the migrations and the unlock use the same connection
+	return GetInstance().Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockKey).Error; err != nil {
+			return fmt.Errorf("cannot take the migration lock: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey)
+
+		if err := conn.Exec(createSchemaMigrationsTable).Error; err != nil {
+			return fmt.Errorf("cannot create the schema_migrations table: %w", err)
+		}
+
+		return migrate(conn)
+	})
+}
+
+func currentMigrationVersion(conn *gorm.DB) (uint, error) {
+	version, dirty, err := GetMigrationVersion(conn)
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		return 0, fmt.Errorf("database is dirty at version %d, fix the failed migration by hand before migrating", version)
+	}
+	return version, nil
+}
+
+// GetMigrationVersion returns the version of the database and whether the last migration failed.
+func GetMigrationVersion(conn *gorm.DB) (uint, bool, error) {
+	var isTableExists bool
+	err := conn.Raw("SELECT to_regclass('schema_migrations') IS NOT NULL").Scan(&isTableExists).Error
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !isTableExists {
+		return 0, false, nil
+	}
+
+	var versions []struct {
+		Version uint
+		Dirty   bool
+	}
+	err = conn.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&versions).Error
+	if err != nil {
+		return 0, false, err
+	}
+
+	if len(versions) == 0 {
+		return 0, false, nil
+	}
+	return versions[0].Version, versions[0].Dirty, nil
+}
+
+func runMigration(conn *gorm.DB, sql string, version uint) error {
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(sql).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM schema_migrations").Error; err != nil {
+			return err
+		}
+
+		if version == 0 {
+			return nil
+		}
+		return tx.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, FALSE)", version).Error
+	})
+}
@@ -0,0 +1,30 @@
+package database
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	Convey("TEST embedded migrations are ordered and complete", t, func() {
+		migrations, err := Migrations()
+		So(err, ShouldBeNil)
+		So(migrations, ShouldNotBeEmpty)
+
+		for i, migration := range migrations {
+			So(migration.Version, ShouldEqual, i+1)
+			So(migration.Up, ShouldNotBeBlank)
+			So(migration.Down, ShouldNotBeBlank)
+		}
+		So(migrations[0].String(), ShouldEqual, "000001_init")
+	})
+
+	Convey("TEST latest migration version is the version of the last migration", t, func() {
+		migrations, err := Migrations()
+		So(err, ShouldBeNil)
+
+		version, err := LatestMigrationVersion()
+		So(err, ShouldBeNil)
+		So(version, ShouldEqual, migrations[len(migrations)-1].Version)
+	})
+}
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS item_vas_items_cart_id_idx;
+DROP INDEX IF EXISTS vas_items_cart_id_idx;
+DROP INDEX IF EXISTS items_cart_id_idx;
+
+ALTER TABLE IF EXISTS item_vas_items
+    DROP COLUMN IF EXISTS cart_id;
+
+ALTER TABLE IF EXISTS vas_items
+    DROP COLUMN IF EXISTS cart_id;
+
+ALTER TABLE IF EXISTS items
+    DROP COLUMN IF EXISTS cart_id;
@@ -0,0 +1,17 @@
+ALTER TABLE IF EXISTS items
+    ADD COLUMN IF NOT EXISTS cart_id INT;
+
+ALTER TABLE IF EXISTS vas_items
+    ADD COLUMN IF NOT EXISTS cart_id INT;
+
+ALTER TABLE IF EXISTS item_vas_items
+    ADD COLUMN IF NOT EXISTS cart_id INT;
+
+-- rows created before carts existed belonged to the single global cart, keep them reachable as cart 1
+UPDATE items SET cart_id = 1 WHERE cart_id IS NULL;
+UPDATE vas_items SET cart_id = 1 WHERE cart_id IS NULL;
+UPDATE item_vas_items SET cart_id = 1 WHERE cart_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS items_cart_id_idx ON items (cart_id);
+CREATE INDEX IF NOT EXISTS vas_items_cart_id_idx ON vas_items (cart_id);
+CREATE INDEX IF NOT EXISTS item_vas_items_cart_id_idx ON item_vas_items (cart_id);
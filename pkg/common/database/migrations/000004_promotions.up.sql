@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    promotion_id INT,
+    type VARCHAR(64),
+    percentage DECIMAL(5, 2),
+    category_id INT,
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ
+    );
+
+CREATE TABLE IF NOT EXISTS promotion_tiers (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    promotion_id INT,
+    min_total_price DECIMAL(10, 2),
+    discount DECIMAL(10, 2)
+    );
+
+-- the promotions that were hardcoded before
+INSERT INTO promotions (created_at, updated_at, promotion_id, type, percentage, category_id) VALUES
+    (NOW(), NOW(), 9909, 'same_seller_percentage', 10, NULL),
+    (NOW(), NOW(), 5676, 'category_percentage', 5, 3003),
+    (NOW(), NOW(), 1232, 'tiered_fixed_amount', NULL, NULL);
+
+INSERT INTO promotion_tiers (created_at, updated_at, promotion_id, min_total_price, discount) VALUES
+    (NOW(), NOW(), 1232, 0, 250),
+    (NOW(), NOW(), 1232, 5000, 500),
+    (NOW(), NOW(), 1232, 10000, 1000),
+    (NOW(), NOW(), 1232, 50000, 2000);
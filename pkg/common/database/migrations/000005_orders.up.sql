@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    cart_id INT,
+    applied_promotion_id INT,
+    total_price DECIMAL(10, 2),
+    total_discount DECIMAL(10, 2)
+    );
+
+CREATE TABLE IF NOT EXISTS order_lines (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    order_id INT,
+    item_id INT,
+    vas_item_id INT,
+    category_id INT,
+    seller_id INT,
+    price DECIMAL(10, 2),
+    quantity INT
+    );
+
+CREATE INDEX IF NOT EXISTS order_lines_order_id_idx ON order_lines (order_id);
@@ -0,0 +1,17 @@
+ALTER TABLE IF EXISTS orders
+    ADD COLUMN IF NOT EXISTS applied_promotion_id INT;
+
+-- only one promotion can be kept, the one with the biggest discount
+UPDATE orders SET applied_promotion_id = (
+    SELECT order_promotions.promotion_id
+    FROM order_promotions
+    WHERE order_promotions.order_id = orders.id AND order_promotions.deleted_at IS NULL
+    ORDER BY order_promotions.discount DESC, order_promotions.id
+    LIMIT 1
+);
+
+DROP TABLE IF EXISTS order_promotions;
+
+ALTER TABLE IF EXISTS promotions
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS stackable;
@@ -0,0 +1,24 @@
+ALTER TABLE IF EXISTS promotions
+    ADD COLUMN IF NOT EXISTS stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS priority INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS order_promotions (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    order_id INT,
+    promotion_id INT,
+    discount DECIMAL(10, 2)
+    );
+
+CREATE INDEX IF NOT EXISTS order_promotions_order_id_idx ON order_promotions (order_id);
+
+-- orders had a single promotion that gave the whole discount
+INSERT INTO order_promotions (created_at, updated_at, order_id, promotion_id, discount)
+SELECT created_at, updated_at, id, applied_promotion_id, total_discount
+FROM orders
+WHERE applied_promotion_id IS NOT NULL AND applied_promotion_id <> 0;
+
+ALTER TABLE IF EXISTS orders
+    DROP COLUMN IF EXISTS applied_promotion_id;
@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS order_promotions
+    DROP COLUMN IF EXISTS coupon_code;
+
+DROP TABLE IF EXISTS cart_coupons;
+DROP TABLE IF EXISTS coupons;
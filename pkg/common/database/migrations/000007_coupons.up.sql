@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS coupons (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    code VARCHAR(64),
+    type VARCHAR(64),
+    percentage DECIMAL(5, 2),
+    amount DECIMAL(10, 2),
+    min_total_price DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    usage_limit INT NOT NULL DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ
+    );
+
+CREATE INDEX IF NOT EXISTS coupons_code_idx ON coupons (code);
+
+CREATE TABLE IF NOT EXISTS cart_coupons (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    cart_id INT,
+    code VARCHAR(64)
+    );
+
+CREATE INDEX IF NOT EXISTS cart_coupons_cart_id_idx ON cart_coupons (cart_id);
+
+ALTER TABLE IF EXISTS order_promotions
+    ADD COLUMN IF NOT EXISTS coupon_code VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idempotency_keys_user_id_key_idx;
+
+-- keys of different users can be the same, they are short lived so they are dropped instead of merged
+DELETE FROM idempotency_keys;
+
+ALTER TABLE IF EXISTS idempotency_keys
+    DROP COLUMN IF EXISTS user_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idempotency_keys_key_idx ON idempotency_keys (key);
+
+DROP TABLE IF EXISTS carts;
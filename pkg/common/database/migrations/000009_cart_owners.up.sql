@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS carts (
+    id INT PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    user_id INT NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS carts_user_id_idx ON carts (user_id);
+
+ALTER TABLE IF EXISTS idempotency_keys
+    ADD COLUMN IF NOT EXISTS user_id INT NOT NULL DEFAULT 0;
+
+DROP INDEX IF EXISTS idempotency_keys_key_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idempotency_keys_user_id_key_idx ON idempotency_keys (user_id, key);
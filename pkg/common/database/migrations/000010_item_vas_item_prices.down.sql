@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS vas_items (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    vas_item_id INT,
+    category_id INT,
+    seller_id INT,
+    price DECIMAL(10, 2),
+    quantity INT,
+    cart_id INT
+    );
+
+CREATE INDEX IF NOT EXISTS vas_items_cart_id_idx ON vas_items (cart_id);
+
+-- a vas-item attached to several items keeps the price and the quantity of the first item it was added to
+INSERT INTO vas_items (created_at, updated_at, vas_item_id, category_id, seller_id, price, quantity, cart_id)
+SELECT DISTINCT ON (cart_id, vas_item_id) created_at, updated_at, vas_item_id, category_id, seller_id, price, quantity, cart_id
+FROM item_vas_items
+WHERE deleted_at IS NULL
+ORDER BY cart_id, vas_item_id, id;
+
+ALTER TABLE IF EXISTS item_vas_items
+    DROP COLUMN IF EXISTS category_id,
+    DROP COLUMN IF EXISTS seller_id,
+    DROP COLUMN IF EXISTS price,
+    DROP COLUMN IF EXISTS quantity;
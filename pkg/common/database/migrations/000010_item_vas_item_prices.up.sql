@@ -0,0 +1,21 @@
+ALTER TABLE IF EXISTS item_vas_items
+    ADD COLUMN IF NOT EXISTS category_id INT,
+    ADD COLUMN IF NOT EXISTS seller_id INT,
+    ADD COLUMN IF NOT EXISTS price DECIMAL(10, 2),
+    ADD COLUMN IF NOT EXISTS quantity INT;
+
+-- every item gets its own copy of the vas-item it was sharing with the other items of the cart
+UPDATE item_vas_items
+SET category_id = vas_items.category_id,
+    seller_id = vas_items.seller_id,
+    price = vas_items.price,
+    quantity = vas_items.quantity
+FROM vas_items
+WHERE vas_items.cart_id = item_vas_items.cart_id
+  AND vas_items.vas_item_id = item_vas_items.vas_item_id
+  AND vas_items.deleted_at IS NULL;
+
+-- links without a vas-item were neither displayed nor counted in the total price
+UPDATE item_vas_items SET deleted_at = NOW() WHERE price IS NULL AND deleted_at IS NULL;
+
+DROP TABLE IF EXISTS vas_items;
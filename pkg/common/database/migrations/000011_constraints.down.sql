@@ -0,0 +1,20 @@
+ALTER TABLE IF EXISTS order_lines
+    DROP CONSTRAINT IF EXISTS order_lines_price_check,
+    DROP CONSTRAINT IF EXISTS order_lines_quantity_check,
+    DROP CONSTRAINT IF EXISTS order_lines_order_id_fkey;
+
+ALTER TABLE IF EXISTS order_promotions
+    DROP CONSTRAINT IF EXISTS order_promotions_order_id_fkey;
+
+ALTER TABLE IF EXISTS item_vas_items
+    DROP CONSTRAINT IF EXISTS item_vas_items_price_check,
+    DROP CONSTRAINT IF EXISTS item_vas_items_quantity_check,
+    DROP CONSTRAINT IF EXISTS item_vas_items_pkey;
+
+ALTER TABLE IF EXISTS items
+    DROP CONSTRAINT IF EXISTS items_price_check,
+    DROP CONSTRAINT IF EXISTS items_quantity_check;
+
+DROP INDEX IF EXISTS item_vas_items_cart_id_item_id_vas_item_id_active_idx;
+
+DROP INDEX IF EXISTS items_cart_id_item_id_active_idx;
@@ -0,0 +1,48 @@
+-- the keys of the cart tables are unique only among the rows that are not soft-deleted, so they are partial indexes
+
+-- an item added twice to the same cart is merged into its first line
+UPDATE items SET quantity = duplicates.quantity
+FROM (
+    SELECT MIN(id) AS id, SUM(quantity) AS quantity
+    FROM items
+    WHERE deleted_at IS NULL
+    GROUP BY cart_id, item_id
+    HAVING COUNT(*) > 1
+    ) AS duplicates
+WHERE items.id = duplicates.id;
+
+UPDATE items SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND id NOT IN (SELECT MIN(id) FROM items WHERE deleted_at IS NULL GROUP BY cart_id, item_id);
+
+UPDATE item_vas_items SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND id NOT IN (SELECT MIN(id) FROM item_vas_items WHERE deleted_at IS NULL GROUP BY cart_id, item_id, vas_item_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS items_cart_id_item_id_active_idx ON items (cart_id, item_id) WHERE deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS item_vas_items_cart_id_item_id_vas_item_id_active_idx
+    ON item_vas_items (cart_id, item_id, vas_item_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE IF EXISTS item_vas_items
+    ADD CONSTRAINT item_vas_items_pkey PRIMARY KEY (id);
+
+-- orders are only soft-deleted, their lines and promotions are removed with them when an order is deleted for real
+ALTER TABLE IF EXISTS order_lines
+    ADD CONSTRAINT order_lines_order_id_fkey FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE;
+
+ALTER TABLE IF EXISTS order_promotions
+    ADD CONSTRAINT order_promotions_order_id_fkey FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE;
+
+-- rows written before the checks are not validated, soft-deleted lines can have values the api never accepted
+ALTER TABLE IF EXISTS items
+    ADD CONSTRAINT items_price_check CHECK (price >= 0) NOT VALID,
+    ADD CONSTRAINT items_quantity_check CHECK (quantity > 0) NOT VALID;
+
+ALTER TABLE IF EXISTS item_vas_items
+    ADD CONSTRAINT item_vas_items_price_check CHECK (price >= 0) NOT VALID,
+    ADD CONSTRAINT item_vas_items_quantity_check CHECK (quantity > 0) NOT VALID;
+
+ALTER TABLE IF EXISTS order_lines
+    ADD CONSTRAINT order_lines_price_check CHECK (price >= 0) NOT VALID,
+    ADD CONSTRAINT order_lines_quantity_check CHECK (quantity > 0) NOT VALID;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS products (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ,
+    product_id INT NOT NULL,
+    category_id INT NOT NULL,
+    seller_id INT NOT NULL,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0)
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS products_product_id_active_idx ON products (product_id) WHERE deleted_at IS NULL;
@@ -0,0 +1 @@
+UPDATE promotions SET stackable = FALSE, priority = 0 WHERE promotion_id IN (5676, 1232) AND deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- the seeded category promotion is applied first and the tiered promotion on the total price it leaves
+UPDATE promotions SET stackable = TRUE, priority = 1 WHERE promotion_id = 5676 AND deleted_at IS NULL;
+UPDATE promotions SET stackable = TRUE, priority = 2 WHERE promotion_id = 1232 AND deleted_at IS NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE IF EXISTS cart_coupons
+    DROP CONSTRAINT IF EXISTS cart_coupons_code_fkey,
+    DROP COLUMN IF EXISTS is_active;
+
+ALTER TABLE IF EXISTS coupons
+    DROP CONSTRAINT IF EXISTS coupons_code_is_active_key,
+    DROP COLUMN IF EXISTS is_active;
+
+ALTER TABLE IF EXISTS item_vas_items
+    DROP CONSTRAINT IF EXISTS item_vas_items_cart_id_item_id_fkey,
+    DROP COLUMN IF EXISTS is_active;
+
+CREATE UNIQUE INDEX IF NOT EXISTS items_cart_id_item_id_active_idx ON items (cart_id, item_id) WHERE deleted_at IS NULL;
+
+ALTER TABLE IF EXISTS items
+    DROP CONSTRAINT IF EXISTS items_cart_id_item_id_is_active_key,
+    DROP COLUMN IF EXISTS is_active;
@@ -0,0 +1,55 @@
+-- a foreign key cannot reference the partial unique indexes of the soft-deleted tables. is_active is TRUE for the rows
+-- that are not soft-deleted and NULL for the others, so a unique key on it only applies to the active rows and the
+-- foreign keys on it are only checked for the active rows (MATCH SIMPLE skips the rows with a NULL column).
+--
+-- items are not referencing carts, a carts row is only written when the cart is claimed.
+
+ALTER TABLE IF EXISTS items
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN GENERATED ALWAYS AS (CASE WHEN deleted_at IS NULL THEN TRUE END) STORED;
+
+ALTER TABLE IF EXISTS items
+    ADD CONSTRAINT items_cart_id_item_id_is_active_key UNIQUE (cart_id, item_id, is_active);
+
+DROP INDEX IF EXISTS items_cart_id_item_id_active_idx;
+
+ALTER TABLE IF EXISTS item_vas_items
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN GENERATED ALWAYS AS (CASE WHEN deleted_at IS NULL THEN TRUE END) STORED;
+
+-- vas-items left on removed items were not shown anymore
+UPDATE item_vas_items SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM items
+    WHERE items.cart_id = item_vas_items.cart_id AND items.item_id = item_vas_items.item_id AND items.deleted_at IS NULL
+    );
+
+-- the vas-items of an item are soft-deleted before the item. the key is checked after every statement, because gorm
+-- drops the error of the commit of its own transaction. a transaction that soft-deletes the item first can defer it
+-- with SET CONSTRAINTS
+ALTER TABLE IF EXISTS item_vas_items
+    ADD CONSTRAINT item_vas_items_cart_id_item_id_fkey FOREIGN KEY (cart_id, item_id, is_active)
+        REFERENCES items (cart_id, item_id, is_active) DEFERRABLE INITIALLY IMMEDIATE;
+
+-- a code added twice is kept once, the first coupon with it is used
+UPDATE coupons SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND id NOT IN (SELECT MIN(id) FROM coupons WHERE deleted_at IS NULL GROUP BY code);
+
+ALTER TABLE IF EXISTS coupons
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN GENERATED ALWAYS AS (CASE WHEN deleted_at IS NULL THEN TRUE END) STORED;
+
+ALTER TABLE IF EXISTS coupons
+    ADD CONSTRAINT coupons_code_is_active_key UNIQUE (code, is_active);
+
+ALTER TABLE IF EXISTS cart_coupons
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN GENERATED ALWAYS AS (CASE WHEN deleted_at IS NULL THEN TRUE END) STORED;
+
+-- coupons of the carts whose coupon was removed were not applied anymore
+UPDATE cart_coupons SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM coupons WHERE coupons.code = cart_coupons.code AND coupons.deleted_at IS NULL);
+
+-- a coupon can only be removed after it is removed from the carts
+ALTER TABLE IF EXISTS cart_coupons
+    ADD CONSTRAINT cart_coupons_code_fkey FOREIGN KEY (code, is_active)
+        REFERENCES coupons (code, is_active) DEFERRABLE INITIALLY IMMEDIATE;
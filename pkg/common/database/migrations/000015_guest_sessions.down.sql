@@ -0,0 +1,7 @@
+-- the guest carts are shared by all the guests again
+DELETE FROM carts WHERE user_id = 0;
+
+DROP INDEX IF EXISTS carts_guest_session_idx;
+
+ALTER TABLE IF EXISTS carts
+    DROP COLUMN IF EXISTS guest_session;
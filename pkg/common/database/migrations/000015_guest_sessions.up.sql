@@ -0,0 +1,16 @@
+-- the carts of the guests belong to the guest session that claimed them, the users keep an empty guest session
+ALTER TABLE IF EXISTS carts
+    ADD COLUMN IF NOT EXISTS guest_session VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS carts_guest_session_idx ON carts (guest_session) WHERE guest_session <> '';
+
+-- the guest carts of before were shared by all the guests, they do not have a session so nobody can use them anymore
+INSERT INTO carts (id, created_at, updated_at, user_id)
+SELECT DISTINCT cart_id, NOW(), NOW(), 0
+FROM (
+    SELECT cart_id FROM items WHERE deleted_at IS NULL
+    UNION
+    SELECT cart_id FROM cart_coupons WHERE deleted_at IS NULL
+    ) AS guest_carts
+WHERE cart_id NOT IN (SELECT id FROM carts)
+ON CONFLICT DO NOTHING;
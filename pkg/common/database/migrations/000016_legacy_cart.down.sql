@@ -0,0 +1 @@
+-- the soft-deleted rows of cart 1 cannot be told apart from the rows removed by the users, they stay soft-deleted
@@ -0,0 +1,17 @@
+-- the rows of the single global cart of before the cart IDs were moved to cart 1, see 000003. Cart 1 has no owner, so
+-- the first user who claims it would get all of them. They are soft-deleted, unless cart 1 was claimed since then
+-- and they are the cart of its owner now. The vas-items go before the items and the coupons, see 000014.
+UPDATE item_vas_items SET deleted_at = NOW()
+WHERE cart_id = 1 AND deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM carts WHERE carts.id = 1 AND (carts.user_id <> 0 OR carts.guest_session <> ''));
+
+UPDATE items SET deleted_at = NOW()
+WHERE cart_id = 1 AND deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM carts WHERE carts.id = 1 AND (carts.user_id <> 0 OR carts.guest_session <> ''));
+
+UPDATE cart_coupons SET deleted_at = NOW()
+WHERE cart_id = 1 AND deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM carts WHERE carts.id = 1 AND (carts.user_id <> 0 OR carts.guest_session <> ''));
+
+-- 000015 locked cart 1 with the other carts without an owner, it is empty now so it can be claimed again
+DELETE FROM carts WHERE id = 1 AND user_id = 0 AND guest_session = '';
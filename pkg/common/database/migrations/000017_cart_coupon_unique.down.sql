@@ -0,0 +1 @@
+DROP INDEX IF EXISTS cart_coupons_cart_id_active_idx;
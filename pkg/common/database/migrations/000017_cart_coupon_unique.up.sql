@@ -0,0 +1,6 @@
+-- a cart has one coupon, concurrent applies could leave two of them. The last applied coupon is kept.
+UPDATE cart_coupons SET deleted_at = NOW()
+WHERE deleted_at IS NULL
+  AND id NOT IN (SELECT MAX(id) FROM cart_coupons WHERE deleted_at IS NULL GROUP BY cart_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS cart_coupons_cart_id_active_idx ON cart_coupons (cart_id) WHERE deleted_at IS NULL;
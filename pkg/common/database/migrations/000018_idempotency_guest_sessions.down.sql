@@ -0,0 +1,9 @@
+-- keys of different guest sessions can be the same, they are short lived so they are dropped instead of merged
+DELETE FROM idempotency_keys WHERE user_id = 0;
+
+DROP INDEX IF EXISTS idempotency_keys_user_id_guest_session_key_idx;
+
+ALTER TABLE IF EXISTS idempotency_keys
+    DROP COLUMN IF EXISTS guest_session;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idempotency_keys_user_id_key_idx ON idempotency_keys (user_id, key);
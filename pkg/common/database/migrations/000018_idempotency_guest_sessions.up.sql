@@ -0,0 +1,8 @@
+-- the guests share the user ID 0, their keys are kept per guest session so they do not see the responses of each other
+ALTER TABLE IF EXISTS idempotency_keys
+    ADD COLUMN IF NOT EXISTS guest_session VARCHAR(64) NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idempotency_keys_user_id_key_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idempotency_keys_user_id_guest_session_key_idx
+    ON idempotency_keys (user_id, guest_session, key);
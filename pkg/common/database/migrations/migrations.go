@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS holds the SQL migrations of the database, they are embedded so the binary can migrate the database by itself.
+//
+//go:embed *.sql
+var FS embed.FS
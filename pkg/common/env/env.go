@@ -3,10 +3,22 @@ package env
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
+// MIN_JWT_SECRET_LENGTH is the length of the SHA-256 output, shorter HS256 keys are easier to brute force.
+const MIN_JWT_SECRET_LENGTH = 32
+
 var (
-	DB_URL string
+	DB_URL     string
+	JWT_SECRET string
+	RULES      = DefaultRules()
+	SERVER     = DefaultServerConfig()
+	LOG        = DefaultLogConfig()
+
+	// ADMIN_USER_IDS are the users who can use the admin endpoints, nobody can use them when it is empty
+	ADMIN_USER_IDS []uint
 )
 
 func Load() error {
@@ -31,5 +43,44 @@ func Load() error {
 		DB_URL = testDBUrl
 	}
 
+	JWT_SECRET, ok = os.LookupEnv("JWT_SECRET")
+	if !ok {
+		return fmt.Errorf(errorMessage, "JWT_SECRET")
+	}
+
+	if len(JWT_SECRET) < MIN_JWT_SECRET_LENGTH {
+		return fmt.Errorf("JWT_SECRET must be at least %d characters", MIN_JWT_SECRET_LENGTH)
+	}
+
+	adminUserIDs := make([]uint, 0)
+	if value, ok := os.LookupEnv("ADMIN_USER_IDS"); ok && strings.TrimSpace(value) != "" {
+		for _, userID := range strings.Split(value, ",") {
+			parsed, err := strconv.ParseUint(strings.TrimSpace(userID), 10, 0)
+			if err != nil || parsed == 0 {
+				return fmt.Errorf("cannot parse ADMIN_USER_IDS, %q is not a user ID", userID)
+			}
+			adminUserIDs = append(adminUserIDs, uint(parsed))
+		}
+	}
+	ADMIN_USER_IDS = adminUserIDs
+
+	logConfig, err := LoadLogConfig(environment)
+	if err != nil {
+		return err
+	}
+	LOG = logConfig
+
+	rules, err := LoadRules()
+	if err != nil {
+		return err
+	}
+	RULES = rules
+
+	server, err := LoadServerConfig()
+	if err != nil {
+		return err
+	}
+	SERVER = server
+
 	return nil
 }
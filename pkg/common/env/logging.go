@@ -0,0 +1,46 @@
+package env
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+)
+
+const (
+	LOG_FORMAT_TEXT = "text"
+	LOG_FORMAT_JSON = "json"
+)
+
+type LogConfig struct {
+	Format string
+	Level  logrus.Level
+}
+
+func DefaultLogConfig() LogConfig {
+	return LogConfig{Format: LOG_FORMAT_TEXT, Level: logrus.InfoLevel}
+}
+
+// LoadLogConfig reads LOG_FORMAT (text or json) and LOG_LEVEL (e.g. debug, info, warn), production logs are json by default.
+func LoadLogConfig(environment string) (LogConfig, error) {
+	config := DefaultLogConfig()
+	if environment == "PRODUCTION" {
+		config.Format = LOG_FORMAT_JSON
+	}
+
+	if format, ok := os.LookupEnv("LOG_FORMAT"); ok {
+		if format != LOG_FORMAT_TEXT && format != LOG_FORMAT_JSON {
+			return LogConfig{}, fmt.Errorf("LOG_FORMAT must be %s or %s", LOG_FORMAT_TEXT, LOG_FORMAT_JSON)
+		}
+		config.Format = format
+	}
+
+	if level, ok := os.LookupEnv("LOG_LEVEL"); ok {
+		parsedLevel, err := logrus.ParseLevel(level)
+		if err != nil {
+			return LogConfig{}, fmt.Errorf("cannot parse LOG_LEVEL: %w", err)
+		}
+		config.Level = parsedLevel
+	}
+
+	return config, nil
+}
@@ -0,0 +1,45 @@
+package env
+
+import (
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLoadLogConfig(t *testing.T) {
+	Convey("TEST text logs are used by default outside of production", t, func() {
+		config, err := LoadLogConfig("TEST")
+		So(err, ShouldBeNil)
+		So(config, ShouldResemble, LogConfig{Format: LOG_FORMAT_TEXT, Level: logrus.InfoLevel})
+	})
+
+	Convey("TEST json logs are used by default in production", t, func() {
+		config, err := LoadLogConfig("PRODUCTION")
+		So(err, ShouldBeNil)
+		So(config.Format, ShouldEqual, LOG_FORMAT_JSON)
+	})
+
+	Convey("TEST environment variables override the defaults", t, func() {
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_LEVEL", "debug")
+
+		config, err := LoadLogConfig("TEST")
+		So(err, ShouldBeNil)
+		So(config, ShouldResemble, LogConfig{Format: LOG_FORMAT_JSON, Level: logrus.DebugLevel})
+	})
+
+	Convey("TEST invalid format error", t, func() {
+		t.Setenv("LOG_FORMAT", "xml")
+
+		_, err := LoadLogConfig("TEST")
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST invalid level error", t, func() {
+		t.Setenv("LOG_FORMAT", "text")
+		t.Setenv("LOG_LEVEL", "verbose")
+
+		_, err := LoadLogConfig("TEST")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,139 @@
+package env
+
+import (
+	"bytes"
+	"checkoutProject/pkg/common/money"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Rules are the business rules of the cart, they are read from the JSON file in RULES_FILE and from the environment
+// variables with the same names as the json fields in upper case, environment variables override the file.
+type Rules struct {
+	MaxDefaultItems            uint         `json:"max_default_items"`
+	MaxDigitalItems            uint         `json:"max_digital_items"`
+	MaxUniqueItems             uint         `json:"max_unique_items"`
+	MaxPriceOfCart             money.Amount `json:"max_price_of_cart"`
+	MaxVasItemOnSingleItem     uint         `json:"max_vas_item_on_single_item"`
+	VasItemSellerID            uint         `json:"vas_item_seller_id"`
+	VasItemEligibleCategoryIDs []uint       `json:"vas_item_eligible_category_ids"`
+}
+
+// DefaultRules returns the rules used when nothing is configured.
+func DefaultRules() Rules {
+	return Rules{
+		MaxDefaultItems:            30,
+		MaxDigitalItems:            5,
+		MaxUniqueItems:             10,
+		MaxPriceOfCart:             500000 * money.Unit,
+		MaxVasItemOnSingleItem:     3,
+		VasItemSellerID:            5003,
+		VasItemEligibleCategoryIDs: []uint{1001, 3004},
+	}
+}
+
+func LoadRules() (Rules, error) {
+	rules := DefaultRules()
+
+	if path, ok := os.LookupEnv("RULES_FILE"); ok {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return Rules{}, fmt.Errorf("cannot read rules file %s: %w", path, err)
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		if err = decoder.Decode(&rules); err != nil {
+			return Rules{}, fmt.Errorf("cannot parse rules file %s: %w", path, err)
+		}
+	}
+
+	err := rules.loadFromEnvironment()
+	if err != nil {
+		return Rules{}, err
+	}
+
+	return rules, rules.Validate()
+}
+
+func (r *Rules) loadFromEnvironment() error {
+	uintRules := map[string]*uint{
+		"MAX_DEFAULT_ITEMS":           &r.MaxDefaultItems,
+		"MAX_DIGITAL_ITEMS":           &r.MaxDigitalItems,
+		"MAX_UNIQUE_ITEMS":            &r.MaxUniqueItems,
+		"MAX_VAS_ITEM_ON_SINGLE_ITEM": &r.MaxVasItemOnSingleItem,
+		"VAS_ITEM_SELLER_ID":          &r.VasItemSellerID,
+	}
+
+	for name, rule := range uintRules {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		parsed, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s: %w", name, err)
+		}
+		*rule = uint(parsed)
+	}
+
+	if value, ok := os.LookupEnv("MAX_PRICE_OF_CART"); ok {
+		maxPriceOfCart, err := money.Parse(value)
+		if err != nil {
+			return fmt.Errorf("cannot parse MAX_PRICE_OF_CART: %w", err)
+		}
+		r.MaxPriceOfCart = maxPriceOfCart
+	}
+
+	if value, ok := os.LookupEnv("VAS_ITEM_ELIGIBLE_CATEGORY_IDS"); ok {
+		categoryIDs := make([]uint, 0)
+		for _, categoryID := range strings.Split(value, ",") {
+			parsed, err := strconv.ParseUint(strings.TrimSpace(categoryID), 10, 0)
+			if err != nil {
+				return fmt.Errorf("cannot parse VAS_ITEM_ELIGIBLE_CATEGORY_IDS: %w", err)
+			}
+			categoryIDs = append(categoryIDs, uint(parsed))
+		}
+		r.VasItemEligibleCategoryIDs = categoryIDs
+	}
+
+	return nil
+}
+
+func (r Rules) Validate() error {
+	errorMessage := "invalid rules, %s must be greater than 0"
+
+	if r.MaxDefaultItems == 0 {
+		return fmt.Errorf(errorMessage, "max_default_items")
+	}
+	if r.MaxDigitalItems == 0 {
+		return fmt.Errorf(errorMessage, "max_digital_items")
+	}
+	if r.MaxUniqueItems == 0 {
+		return fmt.Errorf(errorMessage, "max_unique_items")
+	}
+	if r.MaxPriceOfCart <= 0 {
+		return fmt.Errorf(errorMessage, "max_price_of_cart")
+	}
+	if r.MaxVasItemOnSingleItem == 0 {
+		return fmt.Errorf(errorMessage, "max_vas_item_on_single_item")
+	}
+	if r.VasItemSellerID == 0 {
+		return fmt.Errorf(errorMessage, "vas_item_seller_id")
+	}
+
+	for _, categoryID := range r.VasItemEligibleCategoryIDs {
+		if categoryID == 0 {
+			return fmt.Errorf(errorMessage, "vas_item_eligible_category_ids")
+		}
+	}
+
+	if r.MaxUniqueItems > r.MaxDefaultItems {
+		return fmt.Errorf("invalid rules, max_unique_items cannot be over max_default_items")
+	}
+	return nil
+}
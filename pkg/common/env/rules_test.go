@@ -0,0 +1,82 @@
+package env
+
+import (
+	"checkoutProject/pkg/common/money"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRules(t *testing.T) {
+	Convey("TEST default rules are used when nothing is configured", t, func() {
+		rules, err := LoadRules()
+		So(err, ShouldBeNil)
+		So(rules, ShouldResemble, DefaultRules())
+	})
+
+	Convey("TEST rules file overrides the defaults", t, func() {
+		path := filepath.Join(t.TempDir(), "rules.json")
+		err := os.WriteFile(path, []byte(`{"max_default_items": 40, "max_price_of_cart": 1000.5, "vas_item_eligible_category_ids": [1001]}`), 0600)
+		So(err, ShouldBeNil)
+		t.Setenv("RULES_FILE", path)
+
+		rules, err := LoadRules()
+		So(err, ShouldBeNil)
+		So(rules.MaxDefaultItems, ShouldEqual, 40)
+		So(rules.MaxPriceOfCart, ShouldEqual, money.FromFloat(1000.5))
+		So(rules.VasItemEligibleCategoryIDs, ShouldResemble, []uint{1001})
+		So(rules.MaxDigitalItems, ShouldEqual, DefaultRules().MaxDigitalItems)
+	})
+
+	Convey("TEST unknown field in rules file error", t, func() {
+		path := filepath.Join(t.TempDir(), "rules.json")
+		err := os.WriteFile(path, []byte(`{"max_items": 40}`), 0600)
+		So(err, ShouldBeNil)
+		t.Setenv("RULES_FILE", path)
+
+		_, err = LoadRules()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST environment variables override the defaults", t, func() {
+		os.Unsetenv("RULES_FILE")
+		t.Setenv("MAX_UNIQUE_ITEMS", "8")
+		t.Setenv("MAX_PRICE_OF_CART", "250000")
+		t.Setenv("VAS_ITEM_ELIGIBLE_CATEGORY_IDS", "1001, 3004, 4005")
+
+		rules, err := LoadRules()
+		So(err, ShouldBeNil)
+		So(rules.MaxUniqueItems, ShouldEqual, 8)
+		So(rules.MaxPriceOfCart, ShouldEqual, 250000*money.Unit)
+		So(rules.VasItemEligibleCategoryIDs, ShouldResemble, []uint{1001, 3004, 4005})
+	})
+
+	Convey("TEST invalid environment variable error", t, func() {
+		t.Setenv("MAX_DIGITAL_ITEMS", "five")
+
+		_, err := LoadRules()
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	Convey("TEST zero limit error", t, func() {
+		rules := DefaultRules()
+		rules.MaxDigitalItems = 0
+
+		So(rules.Validate(), ShouldEqual, fmt.Errorf("invalid rules, max_digital_items must be greater than 0"))
+	})
+
+	Convey("TEST max unique items over max default items error", t, func() {
+		rules := DefaultRules()
+		rules.MaxUniqueItems = rules.MaxDefaultItems + 1
+
+		So(rules.Validate(), ShouldEqual, fmt.Errorf("invalid rules, max_unique_items cannot be over max_default_items"))
+	})
+
+	Convey("TEST default rules are valid", t, func() {
+		So(DefaultRules().Validate(), ShouldBeNil)
+	})
+}
@@ -0,0 +1,73 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// ServerConfig is the configuration of the http server, durations are read from the environment in the time.Duration
+// format, e.g. "15s" or "1m".
+type ServerConfig struct {
+	Address           string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	RequestTimeout    time.Duration
+	IdempotencyKeyTTL time.Duration
+}
+
+func DefaultServerConfig() ServerConfig {
+	return ServerConfig{
+		Address:           "0.0.0.0:8080",
+		ReadTimeout:       10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		ShutdownTimeout:   15 * time.Second,
+		RequestTimeout:    10 * time.Second,
+		IdempotencyKeyTTL: 24 * time.Hour,
+	}
+}
+
+func LoadServerConfig() (ServerConfig, error) {
+	config := DefaultServerConfig()
+
+	if address, ok := os.LookupEnv("HTTP_ADDRESS"); ok {
+		config.Address = address
+	}
+
+	durations := []struct {
+		name     string
+		duration *time.Duration
+	}{
+		{"HTTP_READ_TIMEOUT", &config.ReadTimeout},
+		{"HTTP_READ_HEADER_TIMEOUT", &config.ReadHeaderTimeout},
+		{"HTTP_WRITE_TIMEOUT", &config.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", &config.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", &config.ShutdownTimeout},
+		{"REQUEST_TIMEOUT", &config.RequestTimeout},
+		{"IDEMPOTENCY_KEY_TTL", &config.IdempotencyKeyTTL},
+	}
+
+	for _, d := range durations {
+		value, ok := os.LookupEnv(d.name)
+		if !ok {
+			continue
+		}
+
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return ServerConfig{}, fmt.Errorf("cannot parse %s: %w", d.name, err)
+		}
+
+		if duration <= 0 {
+			return ServerConfig{}, fmt.Errorf("%s must be greater than 0", d.name)
+		}
+		*d.duration = duration
+	}
+
+	return config, nil
+}
@@ -0,0 +1,46 @@
+package env
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+	"time"
+)
+
+func TestLoadServerConfig(t *testing.T) {
+	Convey("TEST default server config is used when nothing is configured", t, func() {
+		config, err := LoadServerConfig()
+		So(err, ShouldBeNil)
+		So(config, ShouldResemble, DefaultServerConfig())
+	})
+
+	Convey("TEST environment variables override the defaults", t, func() {
+		t.Setenv("HTTP_ADDRESS", "127.0.0.1:9090")
+		t.Setenv("HTTP_WRITE_TIMEOUT", "1m")
+		t.Setenv("SHUTDOWN_TIMEOUT", "45s")
+		t.Setenv("REQUEST_TIMEOUT", "500ms")
+		t.Setenv("IDEMPOTENCY_KEY_TTL", "2h")
+
+		config, err := LoadServerConfig()
+		So(err, ShouldBeNil)
+		So(config.Address, ShouldEqual, "127.0.0.1:9090")
+		So(config.WriteTimeout, ShouldEqual, time.Minute)
+		So(config.ShutdownTimeout, ShouldEqual, 45*time.Second)
+		So(config.RequestTimeout, ShouldEqual, 500*time.Millisecond)
+		So(config.IdempotencyKeyTTL, ShouldEqual, 2*time.Hour)
+		So(config.ReadTimeout, ShouldEqual, DefaultServerConfig().ReadTimeout)
+	})
+
+	Convey("TEST invalid duration error", t, func() {
+		t.Setenv("HTTP_READ_TIMEOUT", "10")
+
+		_, err := LoadServerConfig()
+		So(err, ShouldNotBeNil)
+	})
+
+	Convey("TEST zero duration error", t, func() {
+		t.Setenv("HTTP_READ_TIMEOUT", "0s")
+
+		_, err := LoadServerConfig()
+		So(err, ShouldNotBeNil)
+	})
+}
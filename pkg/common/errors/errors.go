@@ -8,9 +8,15 @@ import (
 const (
 	RecordNotFoundErrCode = http.StatusNotFound
 	InternalServerErrCode = http.StatusInternalServerError
+	UnauthorizedErrCode   = http.StatusUnauthorized
+	ConflictErrCode       = http.StatusConflict
+	ForbiddenErrCode      = http.StatusForbidden
 )
 
 var (
 	InternalServerErr = errors.New("internal server error")
 	RecordNotFoundErr = errors.New("record not found")
+	UnauthorizedErr   = errors.New("unauthorized")
+	ConflictErr       = errors.New("record already exists")
+	ForbiddenErr      = errors.New("forbidden")
 )
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"checkoutProject/pkg/common/env"
 	"github.com/sirupsen/logrus"
 )
 
@@ -9,9 +10,14 @@ var logger *logrus.Logger
 func Initialize() (*logrus.Logger, error) {
 	logger = logrus.New()
 
-	logger.SetFormatter(&logrus.TextFormatter{
-		ForceColors: true,
-	})
+	if env.LOG.Format == env.LOG_FORMAT_JSON {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{
+			ForceColors: true,
+		})
+	}
+	logger.SetLevel(env.LOG.Level)
 
 	return logger, nil
 }
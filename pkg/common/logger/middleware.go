@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"time"
+)
+
+const REQUEST_ID_HEADER = "X-Request-ID"
+
+type entryKey struct{}
+
+// requestIDPattern limits the propagated request IDs, so a client cannot write anything into the logs.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9\-_.:]{1,128}$`)
+
+// Middleware reads the X-Request-ID header or generates a new ID, writes it back to the response and keeps a log entry
+// with the ID for the request, the request itself is logged with the same ID once it is handled.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(REQUEST_ID_HEADER)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(REQUEST_ID_HEADER, requestID)
+
+		entry := GetInstance().WithField("request_id", requestID)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), entry))
+
+		c.Next()
+
+		entry.WithFields(logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+		}).Info("request handled")
+	}
+}
+
+// NewContext returns a copy of the context that carries the log entry.
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// FromContext returns the log entry of the request, requests that did not pass the middleware get an entry without ID.
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(entryKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+
+	return logrus.NewEntry(GetInstance())
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().Format("20060102150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
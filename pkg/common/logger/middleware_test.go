@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	_, err := Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+
+	var requestIDOfEntry interface{}
+	r.GET("/test", func(c *gin.Context) {
+		requestIDOfEntry = FromContext(c.Request.Context()).Data["request_id"]
+		c.Status(http.StatusOK)
+	})
+
+	Convey("TEST request ID of the client is propagated", t, func() {
+		request := httptest.NewRequest(http.MethodGet, "/test", nil)
+		request.Header.Set(REQUEST_ID_HEADER, "mobile-5f2b.1")
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, request)
+		So(recorder.Header().Get(REQUEST_ID_HEADER), ShouldEqual, "mobile-5f2b.1")
+		So(requestIDOfEntry, ShouldEqual, "mobile-5f2b.1")
+	})
+
+	Convey("TEST request ID is generated when the client does not send one", t, func() {
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/test", nil))
+		So(recorder.Header().Get(REQUEST_ID_HEADER), ShouldHaveLength, 32)
+		So(requestIDOfEntry, ShouldEqual, recorder.Header().Get(REQUEST_ID_HEADER))
+	})
+
+	Convey("TEST invalid request ID is replaced", t, func() {
+		request := httptest.NewRequest(http.MethodGet, "/test", nil)
+		request.Header.Set(REQUEST_ID_HEADER, "id with spaces\n")
+		recorder := httptest.NewRecorder()
+
+		r.ServeHTTP(recorder, request)
+		So(recorder.Header().Get(REQUEST_ID_HEADER), ShouldHaveLength, 32)
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	_, err := Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	Convey("TEST entry without request ID is returned outside of the middleware", t, func() {
+		entry := FromContext(context.Background())
+		So(entry, ShouldNotBeNil)
+		So(entry.Data, ShouldNotContainKey, "request_id")
+	})
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"checkoutProject/pkg/common/money"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"strconv"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of http requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of the http requests by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Latency of the database queries by operation and table.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table", "status"})
+
+	itemsAddedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cart_items_added_total",
+		Help: "Quantity of items and vas-items added to the carts.",
+	}, []string{"type"})
+
+	itemAddRejectionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cart_item_add_rejections_total",
+		Help: "Number of item and vas-item additions rejected by a cart rule.",
+	}, []string{"rule"})
+
+	promotionsAppliedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cart_promotions_applied_total",
+		Help: "Number of promotions applied to checked out carts by promotion ID, coupons are counted as promotion 0.",
+	}, []string{"promotion_id"})
+
+	discountAmountTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cart_discount_amount_total",
+		Help: "Total discount given to checked out carts by promotion ID, in currency units.",
+	}, []string{"promotion_id"})
+)
+
+const (
+	ITEM_TYPE     = "item"
+	VAS_ITEM_TYPE = "vas_item"
+)
+
+func ItemsAdded(itemType string, quantity uint) {
+	itemsAddedTotal.WithLabelValues(itemType).Add(float64(quantity))
+}
+
+func ItemAddRejected(rule string) {
+	itemAddRejectionsTotal.WithLabelValues(rule).Inc()
+}
+
+func PromotionApplied(promotionID uint, discount money.Amount) {
+	label := strconv.FormatUint(uint64(promotionID), 10)
+	promotionsAppliedTotal.WithLabelValues(label).Inc()
+	discountAmountTotal.WithLabelValues(label).Add(discount.Float64())
+}
+
+// register registers the collector, registering the same collector twice is not an error.
+func register(collector prometheus.Collector) error {
+	err := prometheus.Register(collector)
+
+	var alreadyRegisteredErr prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegisteredErr) {
+		return nil
+	}
+	return err
+}
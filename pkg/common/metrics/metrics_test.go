@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"checkoutProject/pkg/common/money"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/api/carts/:cart_id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	Convey("TEST requests are counted by route template", t, func() {
+		before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, "/api/carts/:cart_id", "200"))
+
+		for _, path := range []string{"/api/carts/1", "/api/carts/2"} {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		after := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, "/api/carts/:cart_id", "200"))
+		So(after-before, ShouldEqual, 2)
+	})
+
+	Convey("TEST unknown paths are counted as unmatched", t, func() {
+		before := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, UNMATCHED_ROUTE, "404"))
+
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown/path", nil))
+
+		after := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, UNMATCHED_ROUTE, "404"))
+		So(after-before, ShouldEqual, 1)
+	})
+}
+
+func TestBusinessMetrics(t *testing.T) {
+	Convey("TEST added items are counted by quantity", t, func() {
+		before := testutil.ToFloat64(itemsAddedTotal.WithLabelValues(ITEM_TYPE))
+
+		ItemsAdded(ITEM_TYPE, 3)
+
+		So(testutil.ToFloat64(itemsAddedTotal.WithLabelValues(ITEM_TYPE))-before, ShouldEqual, 3)
+	})
+
+	Convey("TEST rejections are counted by rule", t, func() {
+		before := testutil.ToFloat64(itemAddRejectionsTotal.WithLabelValues("max_unique_items"))
+
+		ItemAddRejected("max_unique_items")
+
+		So(testutil.ToFloat64(itemAddRejectionsTotal.WithLabelValues("max_unique_items"))-before, ShouldEqual, 1)
+	})
+
+	Convey("TEST applied promotions and discounts are counted by promotion ID", t, func() {
+		beforeApplied := testutil.ToFloat64(promotionsAppliedTotal.WithLabelValues("1232"))
+		beforeDiscount := testutil.ToFloat64(discountAmountTotal.WithLabelValues("1232"))
+
+		PromotionApplied(1232, money.FromFloat(250.5))
+
+		So(testutil.ToFloat64(promotionsAppliedTotal.WithLabelValues("1232"))-beforeApplied, ShouldEqual, 1)
+		So(testutil.ToFloat64(discountAmountTotal.WithLabelValues("1232"))-beforeDiscount, ShouldEqual, 250.5)
+	})
+}
@@ -0,0 +1,246 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Amount is an amount of money in minor units (cents), it matches the DECIMAL(10, 2) columns.
+type Amount int64
+
+// Rate is a percentage in hundredths of a percent, 10.25% is stored as 1025. It matches the DECIMAL(5, 2) columns.
+type Rate int64
+
+const (
+	// Unit is one unit of the currency in minor units.
+	Unit Amount = 100
+	// Percent is one percent as a rate.
+	Percent Rate = 100
+
+	decimals    = 2
+	ratePercent = 100 * 100
+)
+
+// FromFloat converts a float to an amount, rounding to the nearest cent.
+func FromFloat(f float64) Amount {
+	return Amount(math.Round(f * float64(Unit)))
+}
+
+// Parse converts a decimal string like "20.45" to an amount, extra decimals are rounded to the nearest cent.
+func Parse(s string) (Amount, error) {
+	minorUnits, err := parseMinorUnits(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return Amount(minorUnits), nil
+}
+
+// Mul multiplies the amount by a quantity, e.g. unit price to line price.
+func (a Amount) Mul(quantity uint) Amount {
+	return a * Amount(quantity)
+}
+
+// ApplyRate returns the given percentage of the amount rounded to the nearest cent,
+// this is the only place where discounts are rounded.
+func (a Amount) ApplyRate(r Rate) Amount {
+	return Amount(divRound(int64(a)*int64(r), ratePercent))
+}
+
+// Allocate splits the amount proportionally to the weights. The cents left over after rounding the shares down go one
+// by one to the shares with the biggest remainders, ties go to the first one, so the shares always add up to the amount.
+func (a Amount) Allocate(weights []Amount) []Amount {
+	shares := make([]Amount, len(weights))
+
+	var totalWeight int64
+	for _, weight := range weights {
+		totalWeight += int64(weight)
+	}
+
+	if totalWeight <= 0 {
+		return shares
+	}
+
+	remainders := make([]int64, len(weights))
+	order := make([]int, len(weights))
+	var allocated Amount
+
+	for i, weight := range weights {
+		product := int64(a) * int64(weight)
+		shares[i] = Amount(product / totalWeight)
+		remainders[i] = product % totalWeight
+		order[i] = i
+		allocated += shares[i]
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return remainders[order[i]] > remainders[order[j]]
+	})
+
+	for i := 0; allocated < a; i++ {
+		shares[order[i%len(order)]]++
+		allocated++
+	}
+
+	return shares
+}
+
+func (a Amount) Float64() float64 {
+	return float64(a) / float64(Unit)
+}
+
+// String formats the amount with two decimals, e.g. "500000.00".
+func (a Amount) String() string {
+	return formatMinorUnits(int64(a), false)
+}
+
+// MarshalJSON writes the amount as a JSON number without trailing zeros, e.g. 30.5 or 100000.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(formatMinorUnits(int64(a), true)), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	minorUnits, err := parseMinorUnits(strings.Trim(string(data), `"`))
+	if err != nil {
+		return err
+	}
+
+	*a = Amount(minorUnits)
+	return nil
+}
+
+func (a *Amount) Scan(value interface{}) error {
+	minorUnits, err := scanMinorUnits(value)
+	if err != nil {
+		return err
+	}
+
+	*a = Amount(minorUnits)
+	return nil
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (r Rate) String() string {
+	return formatMinorUnits(int64(r), false)
+}
+
+func (r *Rate) Scan(value interface{}) error {
+	hundredths, err := scanMinorUnits(value)
+	if err != nil {
+		return err
+	}
+
+	*r = Rate(hundredths)
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func scanMinorUnits(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		return parseMinorUnits(string(v))
+	case string:
+		return parseMinorUnits(v)
+	case int64:
+		return v * int64(Unit), nil
+	case float64:
+		return int64(FromFloat(v)), nil
+	}
+
+	return 0, fmt.Errorf("cannot scan %T into money", value)
+}
+
+// parseMinorUnits accepts an optional sign followed by digits with an optional decimal point, e.g. 20.45, -.5 or +3.
+func parseMinorUnits(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("cannot parse empty string as money")
+	}
+
+	number := s
+	negative := false
+	if number[0] == '-' || number[0] == '+' {
+		negative = number[0] == '-'
+		number = number[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(number, ".")
+	if (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("cannot parse %q as money", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	// one more digit than needed is kept to round the rest
+	fraction = (fraction + strings.Repeat("0", decimals+1))[:decimals+1]
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q as money", s)
+	}
+
+	fractionUnits, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q as money", s)
+	}
+
+	minorUnits := divRound(units*int64(Unit)*10+fractionUnits, 10)
+	if negative {
+		minorUnits = -minorUnits
+	}
+
+	return minorUnits, nil
+}
+
+// isDigits returns true when s has only the digits 0-9, the sign of the number must be removed before.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func formatMinorUnits(minorUnits int64, trimZeros bool) string {
+	sign := ""
+	if minorUnits < 0 {
+		sign = "-"
+		minorUnits = -minorUnits
+	}
+
+	whole := strconv.FormatInt(minorUnits/int64(Unit), 10)
+	fraction := fmt.Sprintf("%0*d", decimals, minorUnits%int64(Unit))
+
+	if trimZeros {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+
+	if fraction == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + fraction
+}
+
+// divRound divides and rounds half away from zero.
+func divRound(n int64, d int64) int64 {
+	if (n < 0) != (d < 0) {
+		return (n - d/2) / d
+	}
+
+	return (n + d/2) / d
+}
@@ -0,0 +1,155 @@
+package money
+
+import (
+	"encoding/json"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	Convey("TEST parse decimals", t, func() {
+		amount, err := Parse("20.45")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(2045))
+
+		amount, err = Parse("30.5")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(3050))
+
+		amount, err = Parse("100000")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(10000000))
+
+		amount, err = Parse("-0.25")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(-25))
+	})
+
+	Convey("TEST parse rounds extra decimals half away from zero", t, func() {
+		amount, err := Parse("20.455")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(2046))
+
+		amount, err = Parse("20.454")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(2045))
+
+		amount, err = Parse("-20.455")
+		So(err, ShouldBeNil)
+		So(amount, ShouldEqual, Amount(-2046))
+	})
+
+	Convey("TEST parse signs and missing digits", t, func() {
+		tests := []struct {
+			input    string
+			expected Amount
+		}{
+			{input: "-.5", expected: Amount(-50)},
+			{input: ".5", expected: Amount(50)},
+			{input: "+3", expected: Amount(300)},
+			{input: "7.", expected: Amount(700)},
+			{input: " 1.25 ", expected: Amount(125)},
+		}
+
+		for _, tt := range tests {
+			amount, err := Parse(tt.input)
+			So(err, ShouldBeNil)
+			So(amount, ShouldEqual, tt.expected)
+		}
+	})
+
+	Convey("TEST parse fail", t, func() {
+		for _, input := range []string{"", "abc", "1.-5", "1.+5", "--1", "+-1", "-+1", "-", ".", "-.", "1.2.3", "1 .5", "1e3", "0x10"} {
+			_, err := Parse(input)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
+
+func TestApplyRate(t *testing.T) {
+	Convey("TEST percentage of an amount", t, func() {
+		So(FromFloat(4000).ApplyRate(10*Percent), ShouldEqual, FromFloat(400))
+		So(FromFloat(1650).ApplyRate(5*Percent), ShouldEqual, FromFloat(82.5))
+	})
+
+	Convey("TEST percentage is rounded to the nearest cent", t, func() {
+		So(FromFloat(0.10).ApplyRate(5*Percent), ShouldEqual, Amount(1))
+		So(FromFloat(0.09).ApplyRate(5*Percent), ShouldEqual, Amount(0))
+	})
+
+	Convey("TEST summing lines before applying the rate avoids cent drift", t, func() {
+		var total Amount
+		var sumOfDiscounts Amount
+		for i := 0; i < 10; i++ {
+			total += FromFloat(0.30)
+			sumOfDiscounts += FromFloat(0.30).ApplyRate(5 * Percent)
+		}
+
+		So(total.ApplyRate(5*Percent), ShouldEqual, FromFloat(0.15))
+		So(sumOfDiscounts, ShouldEqual, FromFloat(0.20))
+	})
+}
+
+func TestAllocate(t *testing.T) {
+	Convey("TEST shares are proportional to the weights", t, func() {
+		shares := FromFloat(30).Allocate([]Amount{FromFloat(100), FromFloat(200)})
+		So(shares, ShouldResemble, []Amount{FromFloat(10), FromFloat(20)})
+	})
+
+	Convey("TEST left over cents go to the biggest remainders and shares add up to the amount", t, func() {
+		shares := Amount(100).Allocate([]Amount{1, 1, 1})
+		So(shares, ShouldResemble, []Amount{34, 33, 33})
+
+		shares = Amount(10).Allocate([]Amount{10, 20, 40})
+		So(shares, ShouldResemble, []Amount{1, 3, 6})
+	})
+
+	Convey("TEST zero weights get nothing", t, func() {
+		shares := Amount(101).Allocate([]Amount{0, 3, 0, 3})
+		So(shares, ShouldResemble, []Amount{0, 51, 0, 50})
+
+		shares = Amount(101).Allocate([]Amount{0, 0})
+		So(shares, ShouldResemble, []Amount{0, 0})
+	})
+}
+
+func TestFormat(t *testing.T) {
+	Convey("TEST string has two decimals", t, func() {
+		So(FromFloat(500000).String(), ShouldEqual, "500000.00")
+		So(FromFloat(20.5).String(), ShouldEqual, "20.50")
+		So(FromFloat(-0.05).String(), ShouldEqual, "-0.05")
+	})
+
+	Convey("TEST json is a number without trailing zeros", t, func() {
+		bytes, err := json.Marshal([]Amount{FromFloat(198448.35), FromFloat(30.5), FromFloat(100000), 0})
+		So(err, ShouldBeNil)
+		So(string(bytes), ShouldEqual, "[198448.35,30.5,100000,0]")
+
+		var amounts []Amount
+		err = json.Unmarshal(bytes, &amounts)
+		So(err, ShouldBeNil)
+		So(amounts, ShouldResemble, []Amount{FromFloat(198448.35), FromFloat(30.5), FromFloat(100000), 0})
+	})
+}
+
+func TestScan(t *testing.T) {
+	Convey("TEST scan database values", t, func() {
+		var amount Amount
+
+		So(amount.Scan([]byte("198448.35")), ShouldBeNil)
+		So(amount, ShouldEqual, FromFloat(198448.35))
+
+		So(amount.Scan("0"), ShouldBeNil)
+		So(amount, ShouldEqual, Amount(0))
+
+		So(amount.Scan(nil), ShouldBeNil)
+		So(amount, ShouldEqual, Amount(0))
+
+		So(amount.Scan(int64(3)), ShouldBeNil)
+		So(amount, ShouldEqual, Amount(300))
+
+		var rate Rate
+		So(rate.Scan("10.00"), ShouldBeNil)
+		So(rate, ShouldEqual, 10*Percent)
+	})
+}
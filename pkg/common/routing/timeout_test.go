@@ -0,0 +1,37 @@
+package routing
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Timeout(20 * time.Millisecond))
+
+	var ctxErr error
+	var hasDeadline bool
+	r.GET("/slow", func(c *gin.Context) {
+		_, hasDeadline = c.Request.Context().Deadline()
+
+		select {
+		case <-c.Request.Context().Done():
+			ctxErr = c.Request.Context().Err()
+		case <-time.After(time.Second):
+		}
+		c.Status(http.StatusOK)
+	})
+
+	Convey("TEST context of the request is cancelled after the timeout", t, func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		So(hasDeadline, ShouldBeTrue)
+		So(ctxErr, ShouldEqual, context.DeadlineExceeded)
+	})
+}
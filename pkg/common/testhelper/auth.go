@@ -0,0 +1,39 @@
+package testhelper
+
+import (
+	"checkoutProject/pkg/common/auth"
+	"checkoutProject/pkg/common/env"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	"time"
+)
+
+// TEST_USER_ID owns the carts of the fixtures.
+const TEST_USER_ID = 1
+
+// TEST_GUEST_TOKEN is the guest token of the guest session that owns the guest cart 30 of the fixtures, the guest
+// cart 31 belongs to another guest session.
+const TEST_GUEST_TOKEN = "7a3f9c2e5b8d1f4a6c0e9b2d5f8a1c4e7b0d3f6a9c2e5b8d1f4a7c0e3b6d9f2a"
+
+// AuthorizationHeader returns the header of a request sent by the user, the token is signed with JWT_SECRET.
+func AuthorizationHeader(userID uint) gofight.H {
+	token, err := auth.NewToken([]byte(env.JWT_SECRET), userID, time.Hour)
+	if err != nil {
+		panic(fmt.Sprintf("cannot sign the test token: %v", err))
+	}
+
+	return gofight.H{"Authorization": "Bearer " + token}
+}
+
+// GuestTokenHeader returns the header of a request sent in the guest session of the guest token.
+func GuestTokenHeader(guestToken string) gofight.H {
+	return gofight.H{auth.GUEST_TOKEN_HEADER: guestToken}
+}
+
+// AuthorizationHeaderWithGuestToken returns the header of a request sent by the user with the guest token of its guest
+// session.
+func AuthorizationHeaderWithGuestToken(userID uint, guestToken string) gofight.H {
+	header := AuthorizationHeader(userID)
+	header[auth.GUEST_TOKEN_HEADER] = guestToken
+	return header
+}
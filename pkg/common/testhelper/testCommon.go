@@ -7,10 +7,13 @@ import (
 )
 
 var (
-	DefaultItemsFixturePath = "fixtures/defaultItems"
-	DigitalItemFixturesPath = "fixtures/digitalItems"
-	AddVasItemFixturesPath  = "fixtures/addVasItemFixtures"
-	DefaultPath             = "fixtures"
+	DefaultItemsFixturePath   = "fixtures/defaultItems"
+	DigitalItemFixturesPath   = "fixtures/digitalItems"
+	AddVasItemFixturesPath    = "fixtures/addVasItemFixtures"
+	RemoveVasItemFixturesPath = "fixtures/removeVasItemFixtures"
+	IdempotencyFixturesPath   = "fixtures/idempotencyFixtures"
+	StackablePromotionsPath   = "fixtures/stackablePromotions"
+	DefaultPath               = "fixtures"
 )
 
 func LoadFixtures(path string, t *testing.T, db *sql.DB) {
@@ -1,11 +1,18 @@
 package cart
 
 const (
-	SAME_SELLER_PROMOTION_ID             = 9909
-	CATEGORY_PROMOTION_ID                = 5676
-	TOTAL_PRICE_PROMOTION_ID             = 1232
-	CATEGORY_PROMOTION_APPLICABLE_CAT_ID = 3003
-	SAME_SELLER_PROMOTION_PERCENTAGE     = 0.10
-	CATEGORY_PROMOTION_PERCENTAGE        = 0.05
-	NUMBER_OF_PROMOTIONS                 = 3
+	SAME_SELLER_PERCENTAGE_PROMOTION = "same_seller_percentage"
+	CATEGORY_PERCENTAGE_PROMOTION    = "category_percentage"
+	TIERED_FIXED_AMOUNT_PROMOTION    = "tiered_fixed_amount"
+	PERCENTAGE_COUPON                = "percentage"
+	FIXED_AMOUNT_COUPON              = "fixed_amount"
+)
+
+// types of the notices of the cart
+const (
+	PRICE_INCREASED_NOTICE   = "price_increased"
+	PRICE_DECREASED_NOTICE   = "price_decreased"
+	NOT_IN_CATALOG_NOTICE    = "not_in_catalog"
+	MAX_PRICE_OF_CART_NOTICE = "max_price_of_cart"
+	VAS_ITEM_PRICE_NOTICE    = "vas_item_price_over_item_price"
 )
@@ -2,77 +2,109 @@ package cart
 
 import (
 	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/auth"
 	db "checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/env"
 	errs "checkoutProject/pkg/common/errors"
 	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/metrics"
+	"checkoutProject/pkg/handlers/catalog"
 	"checkoutProject/pkg/handlers/item"
+	"checkoutProject/pkg/handlers/order"
+	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"strings"
 )
 
 type CartController interface {
-	DisplayCart() (apiresponse.Responder, error)
-	ResetCart() (apiresponse.Responder, error)
+	DisplayCart(ctx context.Context, params DisplayCartParams) (apiresponse.Responder, error)
+	ResetCart(ctx context.Context, params ResetCartParams) (apiresponse.Responder, error)
+	Checkout(ctx context.Context, params CheckoutParams) (apiresponse.Responder, error)
+	ApplyCoupon(ctx context.Context, params ApplyCouponParams) (apiresponse.Responder, error)
+	RemoveCoupon(ctx context.Context, params RemoveCouponParams) (apiresponse.Responder, error)
+	MergeCart(ctx context.Context, params MergeCartParams) (apiresponse.Responder, error)
 }
 
 type cartController struct {
-	itemManager    item.ItemManager
-	vasItemManager item.VasItemManager
+	itemManager      item.ItemManager
+	vasItemManager   item.VasItemManager
+	promotionManager PromotionManager
+	orderManager     order.OrderManager
+	couponManager    CouponManager
+	productLookup    catalog.ProductLookup
+	rules            env.Rules
 }
 
-func NewCartController(itemManager item.ItemManager, vasItemManager item.VasItemManager) CartController {
+func NewCartController(itemManager item.ItemManager, vasItemManager item.VasItemManager, promotionManager PromotionManager, orderManager order.OrderManager, couponManager CouponManager, productLookup catalog.ProductLookup, rules env.Rules) CartController {
 	return cartController{
-		itemManager:    itemManager,
-		vasItemManager: vasItemManager,
+		itemManager:      itemManager,
+		vasItemManager:   vasItemManager,
+		promotionManager: promotionManager,
+		orderManager:     orderManager,
+		couponManager:    couponManager,
+		productLookup:    productLookup,
+		rules:            rules,
 	}
 }
 
 func NewDefaultCartController() CartController {
-	return NewCartController(item.NewDefaultItemManager(), item.NewDefaultVasItemManager())
+	return NewCartController(item.NewDefaultItemManager(), item.NewDefaultVasItemManager(), NewDefaultPromotionManager(), order.NewDefaultOrderManager(), NewDefaultCouponManager(), catalog.NewDefaultProductLookup(), env.RULES)
 }
 
 func (c cartController) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
 	return l.WithFields(logrus.Fields{"api_version": "1", "controller": "cart"})
 }
 
-func (c cartController) DisplayCart() (apiresponse.Responder, error) {
-	log := c.formattedLogger(logger.GetInstance()).WithFields(logrus.Fields{
+func (c cartController) DisplayCart(ctx context.Context, params DisplayCartParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
 		"location": "Display Cart",
 	})
 
-	itemManager := c.itemManager
-	vasItemManager := c.vasItemManager
-
-	itemsToDisplay, err := findItemsAndVasItems(itemManager, vasItemManager, log)
+	// the items are loaded once, the totals and the promotions are calculated from them
+	storedCart, err := findCartContent(c.itemManager.WithContext(ctx), log, params.CartID)
 	if err != nil {
 		return nil, err
 	}
 
-	totalPrice, err := itemManager.GetTotalPrice()
+	products, err := findCatalogProducts(ctx, c.productLookup, log, storedCart)
 	if err != nil {
-		log.WithError(err).Error("error while finding total price")
-		return nil, errs.InternalServerErr
+		return nil, err
 	}
 
-	discount, promotionID, err := ApplyPromotion(totalPrice, itemManager, log)
+	// the cart is displayed with the current prices of the catalog, the changes and broken rules are listed as notices
+	cart, notices := revalidateCart(storedCart, products, c.rules)
+
+	itemsToDisplay := newItemSerializers(cart)
+	markPriceChanges(itemsToDisplay, notices)
+	totalPrice := cart.TotalPrice()
+
+	discount, appliedPromotions, err := ApplyPromotion(totalPrice, cart, c.promotionManager.WithContext(ctx), c.couponManager.WithContext(ctx), log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
 
 	newPrice := totalPrice - discount
 
+	allocateDiscounts(itemsToDisplay, appliedPromotions)
+
 	resp := CartSerializer{Result: true, Message: CartMessageSerializer{
-		Items:              itemsToDisplay,
-		TotalPrice:         newPrice,
-		AppliedPromotionID: promotionID,
-		TotalDiscount:      discount,
+		Items:             itemsToDisplay,
+		TotalPrice:        newPrice,
+		AppliedPromotions: appliedPromotions,
+		TotalDiscount:     discount,
+		Notices:           notices,
 	}}
 
 	return resp, nil
 }
 
-func (c cartController) ResetCart() (apiresponse.Responder, error) {
-	log := c.formattedLogger(logger.GetInstance()).WithFields(logrus.Fields{
+func (c cartController) ResetCart(ctx context.Context, params ResetCartParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
 		"location": "Reset Cart",
 	})
 
-	tx := db.NewTransaction()
+	tx := db.NewTransaction(ctx)
 	defer func() {
 		if err := db.RollbackTransaction(tx); err != nil {
 			log.WithError(err).Error("error while rolling back transaction")
@@ -81,29 +113,335 @@ func (c cartController) ResetCart() (apiresponse.Responder, error) {
 
 	itemManager := c.itemManager.WithTx(tx)
 	vasItemManager := c.vasItemManager.WithTx(tx)
+	couponManager := c.couponManager.WithTx(tx)
 
-	err := vasItemManager.DeleteAllItemVasItems()
+	err := itemManager.LockCart(params.CartID)
 	if err != nil {
-		log.WithError(err).Error("error while deleting the item_vas_items")
+		log.WithError(err).Error("error while locking the cart")
 		return nil, errs.InternalServerErr
 	}
 
-	err = itemManager.DeleteAllItems()
+	err = emptyCart(itemManager, vasItemManager, couponManager, log, params.CartID)
 	if err != nil {
-		log.WithError(err).Error("error while deleting the items")
+		return nil, err
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
 		return nil, errs.InternalServerErr
 	}
 
-	err = vasItemManager.DeleteAllVasItems()
+	return apiresponse.GenericResponseSerializer{Result: true, Message: "cart emptied successfully"}, nil
+}
+
+// Checkout saves the cart as an order with the current prices of the catalog and the promotion applied now and empties
+// the cart. A cart that breaks a rule with the current prices cannot be checked out.
+func (c cartController) Checkout(ctx context.Context, params CheckoutParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Checkout",
+	})
+
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	itemManager := c.itemManager.WithTx(tx)
+	vasItemManager := c.vasItemManager.WithTx(tx)
+	promotionManager := c.promotionManager.WithTx(tx)
+	orderManager := c.orderManager.WithTx(tx)
+	couponManager := c.couponManager.WithTx(tx)
+
+	// items added while the order is created would be deleted with the cart without being ordered
+	err := itemManager.LockCart(params.CartID)
 	if err != nil {
-		log.WithError(err).Error("error while deleting the vas_items")
+		log.WithError(err).Error("error while locking the cart")
 		return nil, errs.InternalServerErr
 	}
 
+	storedCart, err := findCartContent(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(storedCart.Items) == 0 {
+		log.Error("cannot checkout an empty cart")
+		return nil, fmt.Errorf("cannot checkout an empty cart")
+	}
+
+	products, err := findCatalogProducts(ctx, c.productLookup, log, storedCart)
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is created with the current prices of the catalog, like the cart is displayed
+	cart, notices := revalidateCart(storedCart, products, c.rules)
+
+	err = checkoutNoticeChecks(log, notices)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsToOrder := newItemSerializers(cart)
+
+	totalPrice := cart.TotalPrice()
+
+	discount, appliedPromotions, err := ApplyPromotion(totalPrice, cart, promotionManager, couponManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkoutCouponUsageChecks(couponManager, log, appliedPromotions)
+	if err != nil {
+		return nil, err
+	}
+
+	newOrder, err := orderManager.Create(order.Order{
+		CartID:        params.CartID,
+		TotalPrice:    totalPrice - discount,
+		TotalDiscount: discount,
+		Lines:         newOrderLines(itemsToOrder),
+		Promotions:    newOrderPromotions(appliedPromotions),
+	})
+	if err != nil {
+		log.WithError(err).Error("error while creating the order")
+		return nil, errs.InternalServerErr
+	}
+
+	err = emptyCart(itemManager, vasItemManager, couponManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
 	if err = db.CommitTransaction(tx); err != nil {
 		log.WithError(err).Error("error while committing the transaction")
 		return nil, errs.InternalServerErr
 	}
 
-	return apiresponse.GenericResponseSerializer{Result: true, Message: "cart emptied successfully"}, nil
+	for _, appliedPromotion := range appliedPromotions {
+		metrics.PromotionApplied(appliedPromotion.PromotionID, appliedPromotion.Discount)
+	}
+
+	return order.OrderSerializer{Result: true, Message: order.OrderMessageSerializer{Order: newOrder}}, nil
+}
+
+// ApplyCoupon applies the coupon to the cart, replacing the coupon applied before. The minimum total price of the
+// coupon is checked with the current prices of the catalog, like the coupon is evaluated when the cart is displayed.
+func (c cartController) ApplyCoupon(ctx context.Context, params ApplyCouponParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Apply Coupon",
+	})
+
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	itemManager := c.itemManager.WithTx(tx)
+	couponManager := c.couponManager.WithTx(tx)
+
+	// concurrent applies would each delete the coupon of the cart and then add their own
+	err := lockCarts(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the coupon is kept for the cart, so the cart must belong to the user like when an item is added
+	err = claimCart(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon, err := applyCouponIsCouponExistsChecks(couponManager, log, strings.ToUpper(strings.TrimSpace(params.Code)))
+	if err != nil {
+		return nil, err
+	}
+
+	storedCart, err := findCartContent(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := findCatalogProducts(ctx, c.productLookup, log, storedCart)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, _ := revalidateCart(storedCart, products, c.rules)
+
+	err = couponUsabilityChecks(log, coupon, cart.TotalPrice())
+	if err != nil {
+		return nil, err
+	}
+
+	err = couponManager.DeleteCartCoupons(CartCouponFilter{CartID: params.CartID})
+	if err != nil {
+		log.WithError(err).Error("error while deleting the coupon of the cart")
+		return nil, errs.InternalServerErr
+	}
+
+	_, err = couponManager.CreateCartCoupon(CartCoupon{CartID: params.CartID, Code: coupon.Code})
+	if err != nil {
+		log.WithError(err).Error("error while applying the coupon to the cart")
+		return nil, errs.InternalServerErr
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	return apiresponse.GenericResponseSerializer{Result: true, Message: "coupon applied successfully"}, nil
+}
+
+// RemoveCoupon removes the coupon of the cart, it takes the lock of the cart like the other requests that change it.
+func (c cartController) RemoveCoupon(ctx context.Context, params RemoveCouponParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Remove Coupon",
+	})
+
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	itemManager := c.itemManager.WithTx(tx)
+	couponManager := c.couponManager.WithTx(tx)
+
+	err := lockCarts(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = claimCart(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = removeCouponIsCartCouponExistsChecks(couponManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = couponManager.DeleteCartCoupons(CartCouponFilter{CartID: params.CartID})
+	if err != nil {
+		log.WithError(err).Error("error while deleting the coupon of the cart")
+		return nil, errs.InternalServerErr
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	return apiresponse.GenericResponseSerializer{Result: true, Message: "coupon removed successfully"}, nil
+}
+
+// MergeCart merges the guest cart into the cart of the user with the rules of adding items, the lines that do not fit
+// are clamped or dropped and reported in the response. The guest cart is emptied, its coupon is not merged. The guest
+// cart must belong to the guest session of the guest token sent with the request.
+func (c cartController) MergeCart(ctx context.Context, params MergeCartParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Merge Cart",
+	})
+
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok || userID == auth.GUEST_USER_ID {
+		log.Error("guests cannot merge carts")
+		return nil, errs.UnauthorizedErr
+	}
+
+	if params.CartID == params.GuestCartID {
+		log.Error("cannot merge a cart into itself")
+		return nil, fmt.Errorf("cannot merge a cart into itself")
+	}
+
+	// the user proves that the guest cart is theirs with the guest token of the guest session
+	if _, ok := auth.GuestSessionFromContext(ctx); !ok {
+		log.Error("guest token is required to merge the guest cart")
+		return nil, errs.ForbiddenErr
+	}
+
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	itemManager := c.itemManager.WithTx(tx)
+	vasItemManager := c.vasItemManager.WithTx(tx)
+
+	// the guest cart is read and emptied as the guest of the guest token, it is not owned by the user
+	guestCtx := auth.NewContext(ctx, auth.GUEST_USER_ID)
+	guestItemManager := itemManager.WithContext(guestCtx)
+	guestVasItemManager := vasItemManager.WithContext(guestCtx)
+	guestCouponManager := c.couponManager.WithTx(tx).WithContext(guestCtx)
+
+	err := lockCarts(itemManager, log, params.CartID, params.GuestCartID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = claimCart(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	isGuestOwner, err := guestItemManager.ClaimCart(params.GuestCartID)
+	if err != nil {
+		log.WithError(err).Error("error while claiming the guest cart")
+		return nil, errs.InternalServerErr
+	}
+
+	if !isGuestOwner {
+		log.Errorf("guest cart %d belongs to another user or guest session", params.GuestCartID)
+		return nil, errs.RecordNotFoundErr
+	}
+
+	guestCart, err := findCartContent(guestItemManager, log, params.GuestCartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(guestCart.Items) == 0 {
+		log.Errorf("guest cart %d is empty", params.GuestCartID)
+		return nil, errs.RecordNotFoundErr
+	}
+
+	cart, err := findCartContent(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the lines are merged with the current catalog prices, like when they are added
+	products, err := findCatalogProducts(ctx, c.productLookup, log, cart, guestCart)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := item.PlanMerge(params.CartID, cart, guestCart, products, c.rules)
+
+	err = applyMergePlan(itemManager, vasItemManager, log, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	err = emptyCart(guestItemManager, guestVasItemManager, guestCouponManager, log, params.GuestCartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	return MergeCartSerializer{Result: true, Message: MergeCartMessageSerializer{Lines: plan.Results}}, nil
 }
@@ -0,0 +1,74 @@
+package cart
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"gorm.io/gorm"
+	"time"
+)
+
+type PromotionFilter struct {
+	ID          uint
+	PromotionID uint
+	Type        string
+	ActiveAt    time.Time
+}
+
+func (f PromotionFilter) ToQuery(q *gorm.DB) *gorm.DB {
+	q = q.Where(Promotion{
+		Model: gorm.Model{ID: f.ID},
+	})
+
+	if f.PromotionID != 0 {
+		q = q.Where("promotions.promotion_id = ?", f.PromotionID)
+	}
+
+	if f.Type != "" {
+		q = q.Where("promotions.type = ?", f.Type)
+	}
+
+	if !f.ActiveAt.IsZero() {
+		q = q.Where("(promotions.starts_at IS NULL OR promotions.starts_at <= ?)", f.ActiveAt).
+			Where("(promotions.ends_at IS NULL OR promotions.ends_at > ?)", f.ActiveAt)
+	}
+
+	return q
+}
+
+type CouponFilter struct {
+	ID   uint
+	Code string
+}
+
+func (f CouponFilter) ToQuery(q *gorm.DB) *gorm.DB {
+	q = q.Where(Coupon{
+		Model: gorm.Model{ID: f.ID},
+	})
+
+	if f.Code != "" {
+		q = q.Where("coupons.code = ?", f.Code)
+	}
+
+	return q
+}
+
+type CartCouponFilter struct {
+	ID     uint
+	CartID uint
+	Code   string
+}
+
+func (f CartCouponFilter) ToQuery(q *gorm.DB) *gorm.DB {
+	q = db.OwnedCarts(q, "cart_coupons").Where(CartCoupon{
+		Model: gorm.Model{ID: f.ID},
+	})
+
+	if f.CartID != 0 {
+		q = q.Where("cart_coupons.cart_id = ?", f.CartID)
+	}
+
+	if f.Code != "" {
+		q = q.Where("cart_coupons.code = ?", f.Code)
+	}
+
+	return q
+}
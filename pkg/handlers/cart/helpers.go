@@ -2,36 +2,341 @@ package cart
 
 import (
 	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
 	"checkoutProject/pkg/handlers/item"
+	"checkoutProject/pkg/handlers/order"
+	"context"
+	"errors"
+	"fmt"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+	"time"
 )
 
-func findItemsAndVasItems(itemManager item.ItemManager, vasItemManager item.VasItemManager, log *logrus.Entry) ([]item.ItemSerializer, error) {
-	items, err := itemManager.Find(item.ItemFilter{})
+// newItemSerializers lists the items of the cart with their vas-items.
+func newItemSerializers(cart item.CartContent) []item.ItemSerializer {
+	var itemsToDisplay []item.ItemSerializer
+
+	for _, itm := range cart.Items {
+		var vasItemsToDisplay []item.VasItemSerializer
+		for _, vasitm := range cart.VasItems[itm.ItemID] {
+			vasItemsToDisplay = append(vasItemsToDisplay, item.VasItemSerializer{VasItem: vasitm})
+		}
+
+		itemsToDisplay = append(itemsToDisplay, item.ItemSerializer{
+			Item:     itm,
+			VasItems: vasItemsToDisplay,
+		})
+	}
+
+	return itemsToDisplay
+}
+
+// markPriceChanges sets the price the lines were added with on the lines whose price changed.
+func markPriceChanges(items []item.ItemSerializer, notices []CartNotice) {
+	for _, notice := range notices {
+		if notice.Type != PRICE_INCREASED_NOTICE && notice.Type != PRICE_DECREASED_NOTICE {
+			continue
+		}
+
+		for i := range items {
+			if items[i].Item.ItemID != notice.ItemID {
+				continue
+			}
+
+			if notice.VasItemID == 0 {
+				items[i].PreviousPrice = notice.PreviousPrice
+			}
+
+			for j := range items[i].VasItems {
+				if notice.VasItemID != 0 && items[i].VasItems[j].VasItem.VasItemID == notice.VasItemID {
+					items[i].VasItems[j].PreviousPrice = notice.PreviousPrice
+				}
+			}
+		}
+	}
+}
+
+func findCatalogProducts(ctx context.Context, productLookup catalog.ProductLookup, log *logrus.Entry, carts ...item.CartContent) (map[uint]catalog.Product, error) {
+	var productIDs []uint
+	for _, cart := range carts {
+		productIDs = append(productIDs, cart.ProductIDs()...)
+	}
+
+	products, err := productLookup.LookupProducts(ctx, productIDs)
 	if err != nil {
-		log.WithError(err).Error("error while querying the items")
+		log.WithError(err).Error("error while querying the products of the cart")
 		return nil, errs.InternalServerErr
 	}
 
-	var itemsToDisplay []item.ItemSerializer
+	return products, nil
+}
 
-	for _, itm := range items {
-		vasItems, err := vasItemManager.GetVasItemsOfAnItem(item.ItemVasItemFilter{ItemID: itm.ItemID})
+// checkoutNoticeChecks rejects the checkout with the first notice that is not a price change.
+func checkoutNoticeChecks(log *logrus.Entry, notices []CartNotice) error {
+	for _, notice := range notices {
+		if notice.IsBlocking() {
+			log.Errorf("cannot checkout the cart, %s", notice.Message)
+			return fmt.Errorf("%s", notice.Message)
+		}
+	}
+	return nil
+}
+
+// lockCarts locks the carts in ascending order, so two requests locking the same carts cannot wait for each other.
+func lockCarts(itemManager item.ItemManager, log *logrus.Entry, cartIDs ...uint) error {
+	sortedCartIDs := append([]uint{}, cartIDs...)
+	sort.Slice(sortedCartIDs, func(i, j int) bool {
+		return sortedCartIDs[i] < sortedCartIDs[j]
+	})
+
+	for _, cartID := range sortedCartIDs {
+		err := itemManager.LockCart(cartID)
 		if err != nil {
-			log.WithError(err).Error("error while finding vas-items of an item")
-			return nil, errs.InternalServerErr
+			log.WithError(err).Error("error while locking the cart")
+			return errs.InternalServerErr
 		}
+	}
+	return nil
+}
 
-		var vasItemsToDisplay []item.VasItemSerializer
-		for _, vasitm := range vasItems {
-			vasItemsToDisplay = append(vasItemsToDisplay, item.VasItemSerializer{VasItem: vasitm})
+// claimCart makes the user of the request the owner of the cart, like adding an item does. A cart of another user is
+// not found.
+func claimCart(itemManager item.ItemManager, log *logrus.Entry, cartID uint) error {
+	isOwner, err := itemManager.ClaimCart(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while claiming the cart")
+		return errs.InternalServerErr
+	}
+
+	if !isOwner {
+		log.Errorf("cart %d belongs to another user", cartID)
+		return errs.RecordNotFoundErr
+	}
+	return nil
+}
+
+func findCartContent(itemManager item.ItemManager, log *logrus.Entry, cartID uint) (item.CartContent, error) {
+	content, err := itemManager.FindCartContent(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while querying the items and vas-items of the cart")
+		return item.CartContent{}, errs.InternalServerErr
+	}
+
+	return content, nil
+}
+
+func applyMergePlan(itemManager item.ItemManager, vasItemManager item.VasItemManager, log *logrus.Entry, plan item.MergePlan) error {
+	for _, itm := range plan.UpdatedItems {
+		err := itemManager.UpdateQuantity(item.ItemFilter{CartID: itm.CartID, ItemID: itm.ItemID}, itm.Quantity)
+		if err != nil {
+			log.WithError(err).Error("error while updating the quantity of the item")
+			return errs.InternalServerErr
 		}
+	}
 
-		itemsToDisplay = append(itemsToDisplay, item.ItemSerializer{
-			Item:     itm,
-			VasItems: vasItemsToDisplay,
+	for _, itm := range plan.NewItems {
+		_, err := itemManager.Create(itm)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			log.WithError(err).Error("item is already in the cart")
+			return err
+		}
+		if err != nil {
+			log.WithError(err).Error("error while creating item")
+			return errs.InternalServerErr
+		}
+	}
+
+	for _, itemVasItem := range plan.NewItemVasItems {
+		_, err := vasItemManager.CreateItemVasItem(itemVasItem)
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			log.WithError(err).Error("item already has the vas-item")
+			return err
+		}
+		if err != nil {
+			log.WithError(err).Error("error while creating the item_vas_item")
+			return errs.InternalServerErr
+		}
+	}
+
+	return nil
+}
+
+// allocateDiscounts spreads the discount of every applied promotion over the lines it is given for, proportionally to
+// what is left of the line totals after the promotions applied before it. Items and their vas-items are separate lines,
+// category promotions are spread only over the items of their category, proportionally to the line totals the
+// discount was calculated on, see getCategoryPromotionDiscount.
+func allocateDiscounts(items []item.ItemSerializer, appliedPromotions []AppliedPromotion) {
+	for _, promotion := range appliedPromotions {
+		var weights []money.Amount
+		for _, itm := range items {
+			var weight money.Amount
+			switch {
+			case promotion.CategoryID == 0:
+				weight = itm.Item.OrderPrice() - itm.Discount
+			case promotion.CategoryID == itm.Item.CategoryID:
+				weight = itm.Item.OrderPrice()
+			}
+			weights = append(weights, weight)
+
+			for _, vasItm := range itm.VasItems {
+				var weight money.Amount
+				if promotion.CategoryID == 0 {
+					weight = vasItm.VasItem.OrderPrice() - vasItm.Discount
+				}
+				weights = append(weights, weight)
+			}
+		}
+
+		shares := promotion.Discount.Allocate(weights)
+
+		line := 0
+		for i := range items {
+			items[i].Discount += shares[line]
+			line++
+
+			for j := range items[i].VasItems {
+				items[i].VasItems[j].Discount += shares[line]
+				line++
+			}
+		}
+	}
+}
+
+func couponUsabilityChecks(log *logrus.Entry, coupon Coupon, totalPrice money.Amount) error {
+	if coupon.isExpired(time.Now()) {
+		log.Errorf("coupon %s is expired", coupon.Code)
+		return fmt.Errorf("coupon code %s is expired", coupon.Code)
+	}
+
+	if coupon.isExhausted() {
+		log.Errorf("coupon %s has reached its usage limit", coupon.Code)
+		return fmt.Errorf("coupon code %s has reached its usage limit", coupon.Code)
+	}
+
+	if totalPrice < coupon.MinTotalPrice {
+		log.Errorf("total price of the cart is below the minimum of coupon %s", coupon.Code)
+		return fmt.Errorf("total price of the cart must be at least %s to use coupon code %s", coupon.MinTotalPrice, coupon.Code)
+	}
+
+	return nil
+}
+
+func applyCouponIsCouponExistsChecks(couponManager CouponManager, log *logrus.Entry, code string) (Coupon, error) {
+	coupon, err := couponManager.Get(CouponFilter{Code: code})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Errorf("coupon %s does not exist", code)
+		return Coupon{}, fmt.Errorf("coupon code %s is not valid", code)
+	}
+
+	if err != nil {
+		log.WithError(err).Error("error while querying the coupon")
+		return Coupon{}, errs.InternalServerErr
+	}
+
+	return coupon, nil
+}
+
+func removeCouponIsCartCouponExistsChecks(couponManager CouponManager, log *logrus.Entry, cartID uint) error {
+	isCartCouponExists, err := couponManager.IsCartCouponExists(CartCouponFilter{CartID: cartID})
+	if err != nil {
+		log.WithError(err).Error("error while querying the coupon of the cart")
+		return errs.InternalServerErr
+	}
+
+	if !isCartCouponExists {
+		log.Error("record not found")
+		return errs.RecordNotFoundErr
+	}
+
+	return nil
+}
+
+// checkoutCouponUsageChecks counts the use of the coupon when it is applied, the coupon may have been used up by other
+// carts after it was applied to this one.
+func checkoutCouponUsageChecks(couponManager CouponManager, log *logrus.Entry, appliedPromotions []AppliedPromotion) error {
+	for _, promotion := range appliedPromotions {
+		if promotion.CouponCode == "" {
+			continue
+		}
+
+		isUsed, err := couponManager.IncreaseUsage(CouponFilter{Code: promotion.CouponCode})
+		if err != nil {
+			log.WithError(err).Error("error while increasing the usage of the coupon")
+			return errs.InternalServerErr
+		}
+
+		if !isUsed {
+			log.Errorf("coupon %s has reached its usage limit", promotion.CouponCode)
+			return fmt.Errorf("coupon code %s has reached its usage limit", promotion.CouponCode)
+		}
+	}
+
+	return nil
+}
+
+func emptyCart(itemManager item.ItemManager, vasItemManager item.VasItemManager, couponManager CouponManager, log *logrus.Entry, cartID uint) error {
+	err := couponManager.DeleteCartCoupons(CartCouponFilter{CartID: cartID})
+	if err != nil {
+		log.WithError(err).Error("error while deleting the coupon of the cart")
+		return errs.InternalServerErr
+	}
+
+	err = vasItemManager.DeleteAllItemVasItems(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while deleting the item_vas_items")
+		return errs.InternalServerErr
+	}
+
+	err = itemManager.DeleteAllItems(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while deleting the items")
+		return errs.InternalServerErr
+	}
+
+	return nil
+}
+
+// every item becomes a line followed by the lines of its vas-items
+func newOrderLines(items []item.ItemSerializer) []order.OrderLine {
+	var lines []order.OrderLine
+
+	for _, itm := range items {
+		lines = append(lines, order.OrderLine{
+			ItemID:     itm.Item.ItemID,
+			CategoryID: itm.Item.CategoryID,
+			SellerID:   itm.Item.SellerID,
+			Price:      itm.Item.Price,
+			Quantity:   itm.Item.Quantity,
+		})
+
+		for _, vasItm := range itm.VasItems {
+			lines = append(lines, order.OrderLine{
+				ItemID:     itm.Item.ItemID,
+				VasItemID:  vasItm.VasItem.VasItemID,
+				CategoryID: vasItm.VasItem.CategoryID,
+				SellerID:   vasItm.VasItem.SellerID,
+				Price:      vasItm.VasItem.Price,
+				Quantity:   vasItm.VasItem.Quantity,
+			})
+		}
+	}
+
+	return lines
+}
+
+func newOrderPromotions(appliedPromotions []AppliedPromotion) []order.OrderPromotion {
+	var orderPromotions []order.OrderPromotion
+
+	for _, promotion := range appliedPromotions {
+		orderPromotions = append(orderPromotions, order.OrderPromotion{
+			PromotionID: promotion.PromotionID,
+			CouponCode:  promotion.CouponCode,
+			Discount:    promotion.Discount,
 		})
 	}
 
-	return itemsToDisplay, nil
+	return orderPromotions
 }
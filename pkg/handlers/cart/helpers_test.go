@@ -3,72 +3,613 @@ package cart
 import (
 	errs "checkoutProject/pkg/common/errors"
 	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
 	"checkoutProject/pkg/handlers/item"
+	"checkoutProject/pkg/handlers/order"
+	"context"
+	"fmt"
 	"github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
-func TestFindItemsAndVasItems(t *testing.T) {
+func TestNewItemSerializers(t *testing.T) {
+	Convey("TEST items are listed with their vas-items", t, func() {
+		cart := item.CartContent{
+			Items:    []item.Item{{ItemID: 1}, {ItemID: 2}},
+			VasItems: map[uint][]item.ItemVasItem{1: {{VasItemID: 2}, {VasItemID: 3}}},
+		}
+
+		res := newItemSerializers(cart)
+		So(res, ShouldHaveLength, 2)
+		So(res[0].Item.ItemID, ShouldEqual, 1)
+		So(res[0].VasItems[0].VasItem.VasItemID, ShouldEqual, 2)
+		So(res[0].VasItems[1].VasItem.VasItemID, ShouldEqual, 3)
+		So(res[1].Item.ItemID, ShouldEqual, 2)
+		So(res[1].VasItems, ShouldBeEmpty)
+	})
+
+	Convey("TEST empty cart", t, func() {
+		So(newItemSerializers(item.CartContent{}), ShouldBeEmpty)
+	})
+}
+
+func TestEmptyCart(t *testing.T) {
 	log, err := logger.Initialize()
 	if err != nil {
 		t.Fail()
 	}
 	mockItemManager := item.NewMockItemManager()
 	mockVasItemManager := item.NewMockVasItemManager()
+	mockCouponManager := NewMockCouponManager()
+	mockCouponManager.MDeleteCartCoupons = func(filter CartCouponFilter) error {
+		return nil
+	}
 
-	Convey("TEST getVasItemsOfAnItem fail", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{
-				{ItemID: 1},
-			}, nil
+	Convey("TEST deleteCartCoupons fail", t, func() {
+		mockCouponManager := NewMockCouponManager()
+		mockCouponManager.MDeleteCartCoupons = func(filter CartCouponFilter) error {
+			return errs.InternalServerErr
 		}
-		mockVasItemManager.MGetVasItemsOfAnItem = func(filter item.ItemVasItemFilter) ([]item.VasItem, error) {
-			return nil, errs.InternalServerErr
+
+		err := emptyCart(mockItemManager, mockVasItemManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST deleteAllItemVasItems fail", t, func() {
+		mockVasItemManager.MDeleteAllItemVasItems = func(cartID uint) error {
+			return errs.InternalServerErr
 		}
 
-		_, err := findItemsAndVasItems(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}))
+		err := emptyCart(mockItemManager, mockVasItemManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
-	Convey("TEST find items fail", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{}, errs.InternalServerErr
+
+	Convey("TEST deleteAllItems fail", t, func() {
+		mockVasItemManager.MDeleteAllItemVasItems = func(cartID uint) error {
+			return nil
 		}
-		mockVasItemManager.MGetVasItemsOfAnItem = func(filter item.ItemVasItemFilter) ([]item.VasItem, error) {
-			return []item.VasItem{}, nil
+		mockItemManager.MDeleteAllItems = func(cartID uint) error {
+			return errs.InternalServerErr
 		}
 
-		_, err := findItemsAndVasItems(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}))
+		err := emptyCart(mockItemManager, mockVasItemManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
+
 	Convey("TEST success", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{
-				{
-					ItemID: 1,
-				},
-				{
-					ItemID: 2,
-				},
-			}, nil
+		var deletedCartIDs []uint
+		mockVasItemManager.MDeleteAllItemVasItems = func(cartID uint) error {
+			deletedCartIDs = append(deletedCartIDs, cartID)
+			return nil
+		}
+		mockItemManager.MDeleteAllItems = func(cartID uint) error {
+			deletedCartIDs = append(deletedCartIDs, cartID)
+			return nil
+		}
+
+		err := emptyCart(mockItemManager, mockVasItemManager, mockCouponManager, log.WithFields(logrus.Fields{}), 2)
+		So(err, ShouldBeNil)
+		So(deletedCartIDs, ShouldResemble, []uint{2, 2})
+	})
+}
+
+func TestLockCarts(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := item.NewMockItemManager()
+
+	Convey("TEST lockCart fail", t, func() {
+		mockItemManager.MLockCart = func(cartID uint) error {
+			return gorm.ErrInvalidTransaction
+		}
+
+		err := lockCarts(mockItemManager, log.WithFields(logrus.Fields{}), 2, 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST carts are locked in ascending order", t, func() {
+		var lockedCartIDs []uint
+		mockItemManager.MLockCart = func(cartID uint) error {
+			lockedCartIDs = append(lockedCartIDs, cartID)
+			return nil
+		}
+
+		err := lockCarts(mockItemManager, log.WithFields(logrus.Fields{}), 9, 3)
+		So(err, ShouldBeNil)
+		So(lockedCartIDs, ShouldResemble, []uint{3, 9})
+	})
+}
+
+func TestClaimCart(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := item.NewMockItemManager()
+
+	Convey("TEST itemManager.ClaimCart fail", t, func() {
+		mockItemManager.MClaimCart = func(cartID uint) (bool, error) {
+			return false, gorm.ErrInvalidTransaction
+		}
+
+		err := claimCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST cart of another user error", t, func() {
+		mockItemManager.MClaimCart = func(cartID uint) (bool, error) {
+			return false, nil
+		}
+
+		err := claimCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.RecordNotFoundErr)
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockItemManager.MClaimCart = func(cartID uint) (bool, error) {
+			return true, nil
+		}
+
+		err := claimCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestFindCartContent(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := item.NewMockItemManager()
+
+	Convey("TEST findCartContent fail", t, func() {
+		mockItemManager.MFindCartContent = func(cartID uint) (item.CartContent, error) {
+			return item.CartContent{}, gorm.ErrInvalidTransaction
+		}
+
+		_, err := findCartContent(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST success", t, func() {
+		var foundCartID uint
+		mockItemManager.MFindCartContent = func(cartID uint) (item.CartContent, error) {
+			foundCartID = cartID
+			return item.CartContent{Items: []item.Item{{ItemID: 1}}}, nil
+		}
+
+		content, err := findCartContent(mockItemManager, log.WithFields(logrus.Fields{}), 4)
+		So(err, ShouldBeNil)
+		So(foundCartID, ShouldEqual, 4)
+		So(content.Items, ShouldHaveLength, 1)
+	})
+}
+
+func TestApplyMergePlan(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := item.NewMockItemManager()
+	mockVasItemManager := item.NewMockVasItemManager()
+
+	plan := item.MergePlan{
+		UpdatedItems:    []item.Item{{CartID: 1, ItemID: 1, Quantity: 3}},
+		NewItems:        []item.Item{{CartID: 1, ItemID: 2, Quantity: 1}},
+		NewItemVasItems: []item.ItemVasItem{{CartID: 1, ItemID: 2, VasItemID: 10, Quantity: 1}},
+	}
+
+	Convey("TEST updateQuantity fail", t, func() {
+		mockItemManager.MUpdateQuantity = func(filter item.ItemFilter, quantity uint) error {
+			return gorm.ErrInvalidTransaction
+		}
+
+		err := applyMergePlan(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}), plan)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST create of an item already in the cart", t, func() {
+		mockItemManager.MUpdateQuantity = func(filter item.ItemFilter, quantity uint) error {
+			return nil
+		}
+		mockItemManager.MCreate = func(itm item.Item) (item.Item, error) {
+			return item.Item{}, gorm.ErrDuplicatedKey
+		}
+
+		err := applyMergePlan(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}), plan)
+		So(err, ShouldEqual, gorm.ErrDuplicatedKey)
+	})
+
+	Convey("TEST createItemVasItem fail", t, func() {
+		mockItemManager.MUpdateQuantity = func(filter item.ItemFilter, quantity uint) error {
+			return nil
 		}
-		mockVasItemManager.MGetVasItemsOfAnItem = func(filter item.ItemVasItemFilter) ([]item.VasItem, error) {
-			return []item.VasItem{
-				{
-					VasItemID: 2,
+		mockItemManager.MCreate = func(itm item.Item) (item.Item, error) {
+			return itm, nil
+		}
+		mockVasItemManager.MCreateItemVasItem = func(itemVasItem item.ItemVasItem) (item.ItemVasItem, error) {
+			return item.ItemVasItem{}, gorm.ErrInvalidTransaction
+		}
+
+		err := applyMergePlan(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}), plan)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST success", t, func() {
+		var updatedQuantity uint
+		var createdItems []item.Item
+		var createdItemVasItems []item.ItemVasItem
+		mockItemManager.MUpdateQuantity = func(filter item.ItemFilter, quantity uint) error {
+			updatedQuantity = quantity
+			return nil
+		}
+		mockItemManager.MCreate = func(itm item.Item) (item.Item, error) {
+			createdItems = append(createdItems, itm)
+			return itm, nil
+		}
+		mockVasItemManager.MCreateItemVasItem = func(itemVasItem item.ItemVasItem) (item.ItemVasItem, error) {
+			createdItemVasItems = append(createdItemVasItems, itemVasItem)
+			return itemVasItem, nil
+		}
+
+		err := applyMergePlan(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}), plan)
+		So(err, ShouldBeNil)
+		So(updatedQuantity, ShouldEqual, 3)
+		So(createdItems, ShouldResemble, plan.NewItems)
+		So(createdItemVasItems, ShouldResemble, plan.NewItemVasItems)
+	})
+}
+
+func TestNewOrderLines(t *testing.T) {
+	Convey("TEST vas-item lines follow their item line", t, func() {
+		lines := newOrderLines([]item.ItemSerializer{
+			{
+				Item: item.Item{ItemID: 1, CategoryID: 1001, SellerID: 3, Price: 200 * money.Unit, Quantity: 2},
+				VasItems: []item.VasItemSerializer{
+					{VasItem: item.ItemVasItem{VasItemID: 4, CategoryID: item.VAS_ITEM_CATEGORY_ID, SellerID: 5003, Price: 20 * money.Unit, Quantity: 1}},
 				},
-				{
-					VasItemID: 3,
+			},
+			{
+				Item: item.Item{ItemID: 2, CategoryID: 3003, SellerID: 3, Price: money.FromFloat(10.5), Quantity: 1},
+			},
+		})
+
+		So(lines, ShouldResemble, []order.OrderLine{
+			{ItemID: 1, CategoryID: 1001, SellerID: 3, Price: 200 * money.Unit, Quantity: 2},
+			{ItemID: 1, VasItemID: 4, CategoryID: item.VAS_ITEM_CATEGORY_ID, SellerID: 5003, Price: 20 * money.Unit, Quantity: 1},
+			{ItemID: 2, CategoryID: 3003, SellerID: 3, Price: money.FromFloat(10.5), Quantity: 1},
+		})
+	})
+}
+
+func TestNewOrderPromotions(t *testing.T) {
+	Convey("TEST every applied promotion becomes an order promotion", t, func() {
+		orderPromotions := newOrderPromotions([]AppliedPromotion{
+			{PromotionID: 5676, Discount: 2200 * money.Unit},
+			{PromotionID: 1232, Discount: 1000 * money.Unit},
+		})
+
+		So(orderPromotions, ShouldResemble, []order.OrderPromotion{
+			{PromotionID: 5676, Discount: 2200 * money.Unit},
+			{PromotionID: 1232, Discount: 1000 * money.Unit},
+		})
+	})
+}
+
+func TestAllocateDiscounts(t *testing.T) {
+	Convey("TEST category discount goes to the items of the category, cart discount to all lines", t, func() {
+		items := []item.ItemSerializer{
+			{
+				Item: item.Item{ItemID: 1, CategoryID: 3003, Price: 100 * money.Unit, Quantity: 2},
+				VasItems: []item.VasItemSerializer{
+					{VasItem: item.ItemVasItem{VasItemID: 1, Price: 10 * money.Unit, Quantity: 1}},
 				},
-			}, nil
+			},
+			{Item: item.Item{ItemID: 2, CategoryID: 1001, Price: 50 * money.Unit, Quantity: 1}},
+		}
+
+		allocateDiscounts(items, []AppliedPromotion{
+			{PromotionID: 5676, CategoryID: 3003, Discount: 10 * money.Unit},
+			{PromotionID: 1232, Discount: 26 * money.Unit},
+		})
+
+		So(items[0].Discount, ShouldEqual, money.FromFloat(29.76))
+		So(items[0].VasItems[0].Discount, ShouldEqual, money.FromFloat(1.04))
+		So(items[1].Discount, ShouldEqual, money.FromFloat(5.2))
+	})
+
+	Convey("TEST category discount is allocated by the line totals of the category it was calculated on", t, func() {
+		items := []item.ItemSerializer{
+			{Item: item.Item{ItemID: 1, CategoryID: 3003, Price: 100 * money.Unit, Quantity: 1}, Discount: 50 * money.Unit},
+			{Item: item.Item{ItemID: 2, CategoryID: 3003, Price: 100 * money.Unit, Quantity: 1}},
+			{Item: item.Item{ItemID: 3, CategoryID: 1001, Price: 300 * money.Unit, Quantity: 1}},
+		}
+
+		allocateDiscounts(items, []AppliedPromotion{{PromotionID: 5676, CategoryID: 3003, Discount: 10 * money.Unit}})
+
+		So(items[0].Discount, ShouldEqual, money.FromFloat(55))
+		So(items[1].Discount, ShouldEqual, money.FromFloat(5))
+		So(items[2].Discount, ShouldEqual, 0)
+	})
+
+	Convey("TEST rounding remainder goes to the first lines with the biggest remainders", t, func() {
+		items := []item.ItemSerializer{
+			{Item: item.Item{ItemID: 1, Price: 10 * money.Unit, Quantity: 1}},
+			{Item: item.Item{ItemID: 2, Price: 10 * money.Unit, Quantity: 1}},
+			{Item: item.Item{ItemID: 3, Price: 10 * money.Unit, Quantity: 1}},
 		}
 
-		res, err := findItemsAndVasItems(mockItemManager, mockVasItemManager, log.WithFields(logrus.Fields{}))
+		allocateDiscounts(items, []AppliedPromotion{{PromotionID: 1232, Discount: 1 * money.Unit}})
+
+		So(items[0].Discount, ShouldEqual, money.FromFloat(0.34))
+		So(items[1].Discount, ShouldEqual, money.FromFloat(0.33))
+		So(items[2].Discount, ShouldEqual, money.FromFloat(0.33))
+	})
+
+	Convey("TEST no promotions", t, func() {
+		items := []item.ItemSerializer{{Item: item.Item{ItemID: 1, Price: 10 * money.Unit, Quantity: 1}}}
+
+		allocateDiscounts(items, []AppliedPromotion{})
+
+		So(items[0].Discount, ShouldEqual, 0)
+	})
+}
+
+func TestCouponUsabilityChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	Convey("TEST expired coupon error", t, func() {
+		expiredAt := time.Now().Add(-time.Minute)
+
+		err := couponUsabilityChecks(log.WithFields(logrus.Fields{}), Coupon{Code: "OLD", ExpiresAt: &expiredAt}, 100*money.Unit)
+		So(err, ShouldEqual, fmt.Errorf("coupon code OLD is expired"))
+	})
+
+	Convey("TEST exhausted coupon error", t, func() {
+		err := couponUsabilityChecks(log.WithFields(logrus.Fields{}), Coupon{Code: "USEDUP", UsageLimit: 5, UsedCount: 5}, 100*money.Unit)
+		So(err, ShouldEqual, fmt.Errorf("coupon code USEDUP has reached its usage limit"))
+	})
+
+	Convey("TEST total price below the minimum error", t, func() {
+		err := couponUsabilityChecks(log.WithFields(logrus.Fields{}), Coupon{Code: "BIGCART", MinTotalPrice: 1000 * money.Unit}, money.FromFloat(999.99))
+		So(err, ShouldEqual, fmt.Errorf("total price of the cart must be at least 1000.00 to use coupon code BIGCART"))
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		expiresAt := time.Now().Add(time.Hour)
+
+		err := couponUsabilityChecks(log.WithFields(logrus.Fields{}), Coupon{Code: "OK", ExpiresAt: &expiresAt, UsageLimit: 5, UsedCount: 4, MinTotalPrice: 1000 * money.Unit}, 1000*money.Unit)
 		So(err, ShouldBeNil)
-		So(res[0].Item.ItemID, ShouldEqual, 1)
-		So(res[0].VasItems[0].VasItem.VasItemID, ShouldEqual, 2)
-		So(res[0].VasItems[1].VasItem.VasItemID, ShouldEqual, 3)
-		So(res[1].VasItems[0].VasItem.VasItemID, ShouldEqual, 2)
-		So(res[1].VasItems[1].VasItem.VasItemID, ShouldEqual, 3)
+
+		err = couponUsabilityChecks(log.WithFields(logrus.Fields{}), Coupon{Code: "UNLIMITED", UsedCount: 1000}, 100*money.Unit)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestApplyCouponIsCouponExistsChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockCouponManager := NewMockCouponManager()
+
+	Convey("TEST couponManager.get fail", t, func() {
+		mockCouponManager.MGet = func(filter CouponFilter) (Coupon, error) {
+			return Coupon{}, errs.InternalServerErr
+		}
+
+		_, err := applyCouponIsCouponExistsChecks(mockCouponManager, log.WithFields(logrus.Fields{}), "SAVE10")
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST coupon does not exist error", t, func() {
+		mockCouponManager.MGet = func(filter CouponFilter) (Coupon, error) {
+			return Coupon{}, gorm.ErrRecordNotFound
+		}
+
+		_, err := applyCouponIsCouponExistsChecks(mockCouponManager, log.WithFields(logrus.Fields{}), "SAVE10")
+		So(err, ShouldEqual, fmt.Errorf("coupon code SAVE10 is not valid"))
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockCouponManager.MGet = func(filter CouponFilter) (Coupon, error) {
+			So(filter.Code, ShouldEqual, "SAVE10")
+			return Coupon{Code: "SAVE10"}, nil
+		}
+
+		coupon, err := applyCouponIsCouponExistsChecks(mockCouponManager, log.WithFields(logrus.Fields{}), "SAVE10")
+		So(err, ShouldBeNil)
+		So(coupon.Code, ShouldEqual, "SAVE10")
+	})
+}
+
+func TestRemoveCouponIsCartCouponExistsChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockCouponManager := NewMockCouponManager()
+
+	Convey("TEST couponManager.isCartCouponExists fail", t, func() {
+		mockCouponManager.MIsCartCouponExists = func(filter CartCouponFilter) (bool, error) {
+			return false, errs.InternalServerErr
+		}
+
+		err := removeCouponIsCartCouponExistsChecks(mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST cart has no coupon error", t, func() {
+		mockCouponManager.MIsCartCouponExists = func(filter CartCouponFilter) (bool, error) {
+			return false, nil
+		}
+
+		err := removeCouponIsCartCouponExistsChecks(mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.RecordNotFoundErr)
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockCouponManager.MIsCartCouponExists = func(filter CartCouponFilter) (bool, error) {
+			So(filter.CartID, ShouldEqual, 1)
+			return true, nil
+		}
+
+		err := removeCouponIsCartCouponExistsChecks(mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestCheckoutCouponUsageChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockCouponManager := NewMockCouponManager()
+
+	Convey("TEST no coupon applied", t, func() {
+		mockCouponManager.MIncreaseUsage = func(filter CouponFilter) (bool, error) {
+			return false, errs.InternalServerErr
+		}
+
+		err := checkoutCouponUsageChecks(mockCouponManager, log.WithFields(logrus.Fields{}), []AppliedPromotion{{PromotionID: 1232}})
+		So(err, ShouldBeNil)
+	})
+
+	Convey("TEST couponManager.increaseUsage fail", t, func() {
+		mockCouponManager.MIncreaseUsage = func(filter CouponFilter) (bool, error) {
+			return false, errs.InternalServerErr
+		}
+
+		err := checkoutCouponUsageChecks(mockCouponManager, log.WithFields(logrus.Fields{}), []AppliedPromotion{{CouponCode: "SAVE10"}})
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST coupon used up by other carts error", t, func() {
+		mockCouponManager.MIncreaseUsage = func(filter CouponFilter) (bool, error) {
+			return false, nil
+		}
+
+		err := checkoutCouponUsageChecks(mockCouponManager, log.WithFields(logrus.Fields{}), []AppliedPromotion{{CouponCode: "SAVE10"}})
+		So(err, ShouldEqual, fmt.Errorf("coupon code SAVE10 has reached its usage limit"))
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockCouponManager.MIncreaseUsage = func(filter CouponFilter) (bool, error) {
+			So(filter.Code, ShouldEqual, "SAVE10")
+			return true, nil
+		}
+
+		err := checkoutCouponUsageChecks(mockCouponManager, log.WithFields(logrus.Fields{}), []AppliedPromotion{{CouponCode: "SAVE10"}})
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestMarkPriceChanges(t *testing.T) {
+	Convey("TEST previous prices are set on the changed lines", t, func() {
+		items := []item.ItemSerializer{
+			{Item: item.Item{ItemID: 1}, VasItems: []item.VasItemSerializer{{VasItem: item.ItemVasItem{VasItemID: 101}}, {VasItem: item.ItemVasItem{VasItemID: 102}}}},
+			{Item: item.Item{ItemID: 2}},
+		}
+
+		markPriceChanges(items, []CartNotice{
+			{Type: PRICE_INCREASED_NOTICE, ItemID: 2, PreviousPrice: testPreviousPrice(5 * money.Unit)},
+			{Type: PRICE_DECREASED_NOTICE, ItemID: 1, VasItemID: 102, PreviousPrice: testPreviousPrice(7 * money.Unit)},
+			{Type: NOT_IN_CATALOG_NOTICE, ItemID: 1, VasItemID: 101, Price: 3 * money.Unit},
+		})
+
+		So(items[0].PreviousPrice, ShouldBeNil)
+		So(items[0].VasItems[0].PreviousPrice, ShouldBeNil)
+		So(*items[0].VasItems[1].PreviousPrice, ShouldEqual, 7*money.Unit)
+		So(*items[1].PreviousPrice, ShouldEqual, 5*money.Unit)
+	})
+
+	Convey("TEST the lines are marked with the direction of the change", t, func() {
+		items := []item.ItemSerializer{
+			{Item: item.Item{ItemID: 1, Price: 10 * money.Unit}, VasItems: []item.VasItemSerializer{{VasItem: item.ItemVasItem{VasItemID: 101, Price: money.Unit}}}},
+			{Item: item.Item{ItemID: 2, Price: 20 * money.Unit}},
+			{Item: item.Item{ItemID: 3, Price: 30 * money.Unit}},
+		}
+
+		markPriceChanges(items, []CartNotice{
+			{Type: PRICE_INCREASED_NOTICE, ItemID: 1, PreviousPrice: testPreviousPrice(0), Price: 10 * money.Unit},
+			{Type: PRICE_DECREASED_NOTICE, ItemID: 1, VasItemID: 101, PreviousPrice: testPreviousPrice(2 * money.Unit), Price: money.Unit},
+			{Type: PRICE_DECREASED_NOTICE, ItemID: 2, PreviousPrice: testPreviousPrice(25 * money.Unit), Price: 20 * money.Unit},
+		})
+
+		// a line added for free is marked with its previous price of 0
+		first := items[0].Response().(item.ItemResponse)
+		So(*first.PreviousPrice, ShouldEqual, 0)
+		So(first.PriceChange, ShouldEqual, item.PRICE_INCREASED)
+		So(*first.VasItems[0].PreviousPrice, ShouldEqual, 2*money.Unit)
+		So(first.VasItems[0].PriceChange, ShouldEqual, item.PRICE_DECREASED)
+
+		So(items[1].Response().(item.ItemResponse).PriceChange, ShouldEqual, item.PRICE_DECREASED)
+
+		unchanged := items[2].Response().(item.ItemResponse)
+		So(unchanged.PreviousPrice, ShouldBeNil)
+		So(unchanged.PriceChange, ShouldBeEmpty)
+	})
+}
+
+func TestFindCatalogProducts(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockProductLookup := catalog.NewMockProductLookup()
+
+	Convey("TEST productLookup.LookupProducts fail", t, func() {
+		mockProductLookup.MLookupProducts = func(ctx context.Context, productIDs []uint) (map[uint]catalog.Product, error) {
+			return nil, gorm.ErrInvalidTransaction
+		}
+
+		_, err := findCatalogProducts(context.Background(), mockProductLookup, log.WithFields(logrus.Fields{}), item.CartContent{})
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST products of the items and vas-items are looked up", t, func() {
+		mockProductLookup.MLookupProducts = func(ctx context.Context, productIDs []uint) (map[uint]catalog.Product, error) {
+			So(productIDs, ShouldResemble, []uint{1, 101})
+			return map[uint]catalog.Product{1: {ProductID: 1}}, nil
+		}
+
+		cart := item.CartContent{
+			Items:    []item.Item{{ItemID: 1}},
+			VasItems: map[uint][]item.ItemVasItem{1: {{VasItemID: 101}}},
+		}
+
+		products, err := findCatalogProducts(context.Background(), mockProductLookup, log.WithFields(logrus.Fields{}), cart)
+		So(err, ShouldBeNil)
+		So(products, ShouldResemble, map[uint]catalog.Product{1: {ProductID: 1}})
+	})
+}
+
+func TestCheckoutNoticeChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	Convey("TEST price changes do not block the checkout", t, func() {
+		err := checkoutNoticeChecks(log.WithFields(logrus.Fields{}), []CartNotice{{Type: PRICE_INCREASED_NOTICE, Message: "price of item 1 rose from 80.00 to 100.00"}})
+		So(err, ShouldBeNil)
+	})
+
+	Convey("TEST first broken rule is returned", t, func() {
+		err := checkoutNoticeChecks(log.WithFields(logrus.Fields{}), []CartNotice{
+			{Type: PRICE_DECREASED_NOTICE, Message: "price of item 1 fell from 100.00 to 80.00"},
+			{Type: VAS_ITEM_PRICE_NOTICE, Message: "price of vas-item 102 cannot be more than the price of item 2"},
+			{Type: MAX_PRICE_OF_CART_NOTICE, Message: "total price of cart cannot be over 500000.00"},
+		})
+		So(err, ShouldEqual, fmt.Errorf("price of vas-item 102 cannot be more than the price of item 2"))
 	})
 }
@@ -0,0 +1,173 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/order"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type checkoutTest struct {
+	Name                    string
+	CartID                  uint
+	ExpectedOrder           order.OrderMessageResponse
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+func TestCheckout(t *testing.T) {
+	tests := []checkoutTest{
+		{
+			Name:   "Server should return 201, create the order and empty the cart",
+			CartID: 2,
+			ExpectedOrder: order.OrderMessageResponse{
+				CartID: 2,
+				Items: []order.OrderItemResponse{
+					{
+						ItemID:     1,
+						CategoryID: 1001,
+						SellerID:   7,
+						Price:      money.FromFloat(999),
+						Quantity:   1,
+						VasItems: []order.OrderVasItemResponse{
+							{
+								VasItemID:  105,
+								CategoryID: 3242,
+								SellerID:   5003,
+								Price:      money.FromFloat(10),
+								Quantity:   1,
+							},
+						},
+					},
+				},
+				TotalPrice:        money.FromFloat(759),
+				AppliedPromotions: []order.OrderPromotionResponse{{PromotionID: 1232, Discount: money.FromFloat(250)}},
+				TotalDiscount:     money.FromFloat(250),
+			},
+			WantCode: http.StatusCreated,
+		},
+		{
+			Name:                    "Server should return 400 if the cart is already checked out",
+			CartID:                  2,
+			ExpectedResponseMessage: "cannot checkout an empty cart",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if the cart is empty",
+			CartID:                  3,
+			ExpectedResponseMessage: "cannot checkout an empty cart",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if a vas-item is more expensive than its item with the current prices",
+			CartID:                  6,
+			ExpectedResponseMessage: "price of vas-item 106 cannot be more than the price of item 6",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if the total price of the cart is over the limit with the current prices",
+			CartID:                  7,
+			ExpectedResponseMessage: fmt.Sprintf("total price of cart cannot be over %s", env.RULES.MaxPriceOfCart),
+			WantCode:                http.StatusBadRequest,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				POST(fmt.Sprintf("/api/carts/%d/checkout", tt.CartID)).
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client sends a request to checkout the cart", t, func() {
+				Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+					So(response.Code, ShouldEqual, tt.WantCode)
+				})
+
+				if response.Code != http.StatusCreated {
+					var res apiresponse.GenericResponse
+					err := json.Unmarshal(response.Body.Bytes(), &res)
+					So(err, ShouldBeNil)
+
+					Convey("Then response should have Message field equal to expected result message", func() {
+						So(res.Result, ShouldBeFalse)
+						So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+					})
+					return
+				}
+
+				var res order.OrderResponse
+				err := json.Unmarshal(response.Body.Bytes(), &res)
+				So(err, ShouldBeNil)
+
+				Convey("Then response should have the order of the cart", func() {
+					So(res.Result, ShouldBeTrue)
+					So(res.Message.OrderID, ShouldNotEqual, 0)
+					So(res.Message.CreatedAt, ShouldNotEqual, time.Time{})
+
+					res.Message.OrderID = 0
+					res.Message.CreatedAt = time.Time{}
+					So(res.Message, ShouldResemble, tt.ExpectedOrder)
+				})
+
+				Convey("Then the cart must be empty and other carts must not be changed", func() {
+					var count int64
+					err := TestDB.Table("items").Where("deleted_at IS NULL AND cart_id = ?", tt.CartID).Count(&count).Error
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, 0)
+
+					err = TestDB.Table("item_vas_items").Where("deleted_at IS NULL AND cart_id = ?", tt.CartID).Count(&count).Error
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, 0)
+
+					err = TestDB.Table("items").Where("deleted_at IS NULL AND cart_id = 1").Count(&count).Error
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, 4)
+				})
+
+				Convey("Then the order must be readable", func() {
+					var orderResponse gofight.HTTPResponse
+					gofight.New().
+						GET(fmt.Sprintf("/api/orders/%d", res.Message.OrderID)).
+						SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+						Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+							orderResponse = r
+						})
+
+					So(orderResponse.Code, ShouldEqual, http.StatusOK)
+
+					var savedOrder order.OrderResponse
+					err := json.Unmarshal(orderResponse.Body.Bytes(), &savedOrder)
+					So(err, ShouldBeNil)
+					So(savedOrder.Message.OrderID, ShouldEqual, res.Message.OrderID)
+					So(savedOrder.Message.Items, ShouldResemble, tt.ExpectedOrder.Items)
+					So(savedOrder.Message.TotalPrice, ShouldEqual, tt.ExpectedOrder.TotalPrice)
+					So(savedOrder.Message.TotalDiscount, ShouldEqual, tt.ExpectedOrder.TotalDiscount)
+				})
+			})
+		})
+	}
+}
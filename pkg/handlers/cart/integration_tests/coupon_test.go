@@ -0,0 +1,245 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/cart"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+)
+
+type applyCouponTest struct {
+	Name                    string
+	CartID                  uint
+	Body                    gofight.D
+	ExpectedResponseResult  bool
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+type removeCouponTest struct {
+	Name                    string
+	CartID                  uint
+	ExpectedResponseResult  bool
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+func TestApplyCoupon(t *testing.T) {
+	tests := []applyCouponTest{
+		{
+			Name:                    "Server should return 400 if the code is missing",
+			CartID:                  1,
+			Body:                    gofight.D{},
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: `{"Code":"This field is required"}`,
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if the coupon does not exist",
+			CartID:                  1,
+			Body:                    gofight.D{"code": "nope"},
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "coupon code NOPE is not valid",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if the coupon is expired",
+			CartID:                  1,
+			Body:                    gofight.D{"code": "EXPIRED"},
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "coupon code EXPIRED is expired",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if the coupon is used up",
+			CartID:                  1,
+			Body:                    gofight.D{"code": "USEDUP"},
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "coupon code USEDUP has reached its usage limit",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 400 if the total price of the cart is below the minimum of the coupon",
+			CartID:                  1,
+			Body:                    gofight.D{"code": "BIGCART"},
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "total price of the cart must be at least 1000000.00 to use coupon code BIGCART",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "Server should return 200 if the total price of the cart with the prices of the catalog reaches the minimum of the coupon",
+			CartID:                  7,
+			Body:                    gofight.D{"code": "SAVE3000"},
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "coupon applied successfully",
+			WantCode:                http.StatusOK,
+		},
+		{
+			Name:                    "Server should return 200 and apply the coupon",
+			CartID:                  1,
+			Body:                    gofight.D{"code": " save3000 "},
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "coupon applied successfully",
+			WantCode:                http.StatusOK,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				POST(fmt.Sprintf("/api/carts/%d/coupon", tt.CartID)).
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				SetJSON(tt.Body).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client sends a request to apply a coupon", t, func() {
+				Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+					So(response.Code, ShouldEqual, tt.WantCode)
+				})
+
+				var res apiresponse.GenericResponse
+				err := json.Unmarshal(response.Body.Bytes(), &res)
+				So(err, ShouldBeNil)
+
+				Convey("Then response should have Result and Message fields equal to expected values", func() {
+					So(res.Result, ShouldEqual, tt.ExpectedResponseResult)
+					So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+				})
+			})
+		})
+	}
+
+	t.Run("Server should apply the coupon when it gives the biggest discount", func(t *testing.T) {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/1").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("When client displays the cart with the coupon", t, func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.CartResponse
+			err := json.Unmarshal(response.Body.Bytes(), &res)
+			So(err, ShouldBeNil)
+
+			So(res.Message.AppliedPromotions, ShouldResemble, []cart.AppliedPromotionResponse{{CouponCode: "SAVE3000", Discount: money.FromFloat(3000)}})
+			So(res.Message.TotalDiscount, ShouldEqual, money.FromFloat(3000))
+			So(res.Message.TotalPrice, ShouldEqual, money.FromFloat(197448.35))
+		})
+	})
+}
+
+func TestRemoveCoupon(t *testing.T) {
+	tests := []removeCouponTest{
+		{
+			Name:                    "Server should return 404 if the cart belongs to another user",
+			CartID:                  otherUserCartID,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+		{
+			Name:                    "Server should return 200 and remove the coupon of the cart",
+			CartID:                  5,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "coupon removed successfully",
+			WantCode:                http.StatusOK,
+		},
+		{
+			Name:                    "Server should return 404 if the cart has no coupon",
+			CartID:                  5,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				DELETE(fmt.Sprintf("/api/carts/%d/coupon", tt.CartID)).
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client sends a request to remove the coupon", t, func() {
+				Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+					So(response.Code, ShouldEqual, tt.WantCode)
+				})
+
+				var res apiresponse.GenericResponse
+				err := json.Unmarshal(response.Body.Bytes(), &res)
+				So(err, ShouldBeNil)
+
+				Convey("Then response should have Result and Message fields equal to expected values", func() {
+					So(res.Result, ShouldEqual, tt.ExpectedResponseResult)
+					So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+				})
+			})
+		})
+	}
+}
+
+func TestCartCouponConstraints(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	Convey("When a coupon that does not exist is applied to a cart", t, func() {
+		err := TestDB.Create(&cart.CartCoupon{CartID: 4, Code: "NOTACOUPON"}).Error
+
+		Convey("Then database should reject it", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When a second coupon is applied to a cart", t, func() {
+		err := TestDB.Create(&cart.CartCoupon{CartID: 5, Code: "SAVE3000"}).Error
+
+		Convey("Then database should return a duplicated key error", func() {
+			So(err, ShouldEqual, gorm.ErrDuplicatedKey)
+		})
+	})
+}
@@ -2,19 +2,24 @@ package integration_tests
 
 import (
 	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/money"
 	"checkoutProject/pkg/common/testhelper"
 	"checkoutProject/pkg/handlers/cart"
 	"checkoutProject/pkg/handlers/item"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/appleboy/gofight/v2"
 	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
 	"net/http"
 	"testing"
 )
 
 type displayCartTest struct {
 	Name             string
+	CartID           uint
 	ExpectedResponse interface{}
 	WantCode         int
 }
@@ -22,76 +27,159 @@ type displayCartTest struct {
 func TestDisplayCart(t *testing.T) {
 	tests := []displayCartTest{
 		{
-			Name: "Server should return 400 if item does not exists in cart.",
+			Name:   "Server should return 200 and display the items of the first cart.",
+			CartID: 1,
 			ExpectedResponse: cart.CartResponse{Result: true, Message: cart.CartMessageResponse{
 				Items: []item.ItemResponse{
 					{
-						ItemID:     1,
-						CategoryID: 1001,
-						SellerID:   1,
-						Price:      20.45,
-						Quantity:   1,
+						ItemID:         5,
+						CategoryID:     1001,
+						SellerID:       1,
+						Price:          money.FromFloat(20.45),
+						Quantity:       1,
+						LineTotal:      money.FromFloat(20.45),
+						Discount:       money.FromFloat(0.2),
+						FinalLineTotal: money.FromFloat(20.25),
 						VasItems: []item.VasItemResponse{
 							{
-								VasItemID:  1,
-								CategoryID: 3242,
-								SellerID:   5003,
-								Price:      50,
-								Quantity:   2,
+								VasItemID:      101,
+								CategoryID:     3242,
+								SellerID:       5003,
+								Price:          money.FromFloat(50),
+								Quantity:       2,
+								LineTotal:      money.FromFloat(100),
+								Discount:       money.FromFloat(1),
+								FinalLineTotal: money.FromFloat(99),
 							},
 						},
 					},
 					{
-						ItemID:     2,
-						CategoryID: 1001,
-						SellerID:   1,
-						Price:      30.50,
-						Quantity:   6,
+						ItemID:         2,
+						CategoryID:     1001,
+						SellerID:       1,
+						Price:          money.FromFloat(30.50),
+						Quantity:       6,
+						LineTotal:      money.FromFloat(183),
+						Discount:       money.FromFloat(1.83),
+						FinalLineTotal: money.FromFloat(181.17),
 						VasItems: []item.VasItemResponse{
 							{
-								VasItemID:  2,
-								CategoryID: 3242,
-								SellerID:   5003,
-								Price:      40.2,
-								Quantity:   2,
+								VasItemID:      102,
+								CategoryID:     3242,
+								SellerID:       5003,
+								Price:          money.FromFloat(40.2),
+								Quantity:       2,
+								LineTotal:      money.FromFloat(80.4),
+								Discount:       money.FromFloat(0.8),
+								FinalLineTotal: money.FromFloat(79.6),
 							},
 							{
-								VasItemID:  3,
-								CategoryID: 3242,
-								SellerID:   5003,
-								Price:      30.50,
-								Quantity:   1,
+								VasItemID:      103,
+								CategoryID:     3242,
+								SellerID:       5003,
+								Price:          money.FromFloat(30.50),
+								Quantity:       1,
+								LineTotal:      money.FromFloat(30.5),
+								Discount:       money.FromFloat(0.3),
+								FinalLineTotal: money.FromFloat(30.2),
 							},
 						},
 					},
 					{
-						ItemID:     3,
-						CategoryID: 3004,
-						SellerID:   1,
-						Price:      3.50,
-						Quantity:   1,
+						ItemID:         3,
+						CategoryID:     3004,
+						SellerID:       1,
+						Price:          money.FromFloat(3.50),
+						Quantity:       1,
+						LineTotal:      money.FromFloat(3.5),
+						Discount:       money.FromFloat(0.04),
+						FinalLineTotal: money.FromFloat(3.46),
 						VasItems: []item.VasItemResponse{
 							{
-								VasItemID:  3,
-								CategoryID: 3242,
-								SellerID:   5003,
-								Price:      30.50,
-								Quantity:   1,
+								VasItemID:      103,
+								CategoryID:     3242,
+								SellerID:       5003,
+								Price:          money.FromFloat(30.50),
+								Quantity:       1,
+								LineTotal:      money.FromFloat(30.5),
+								Discount:       money.FromFloat(0.3),
+								FinalLineTotal: money.FromFloat(30.2),
 							},
 						},
 					},
 					{
-						ItemID:     4,
-						CategoryID: 1001,
-						SellerID:   6,
-						Price:      100000,
-						Quantity:   2,
-						VasItems:   []item.VasItemResponse{},
+						ItemID:         4,
+						CategoryID:     1001,
+						SellerID:       6,
+						Price:          money.FromFloat(100000),
+						Quantity:       2,
+						LineTotal:      money.FromFloat(200000),
+						Discount:       money.FromFloat(1995.53),
+						FinalLineTotal: money.FromFloat(198004.47),
+						VasItems:       []item.VasItemResponse{},
 					},
 				},
-				TotalPrice:         198448.35,
-				AppliedPromotionID: 1232,
-				TotalDiscount:      2000,
+				TotalPrice:        money.FromFloat(198448.35),
+				AppliedPromotions: []cart.AppliedPromotionResponse{{PromotionID: 1232, Discount: money.FromFloat(2000)}},
+				TotalDiscount:     money.FromFloat(2000),
+				Notices: []cart.CartNoticeResponse{
+					{
+						Type:      cart.VAS_ITEM_PRICE_NOTICE,
+						ItemID:    5,
+						VasItemID: 101,
+						Price:     money.FromFloat(50),
+						Message:   "price of vas-item 101 cannot be more than the price of item 5",
+					},
+					{
+						Type:      cart.VAS_ITEM_PRICE_NOTICE,
+						ItemID:    2,
+						VasItemID: 102,
+						Price:     money.FromFloat(40.2),
+						Message:   "price of vas-item 102 cannot be more than the price of item 2",
+					},
+					{
+						Type:      cart.VAS_ITEM_PRICE_NOTICE,
+						ItemID:    3,
+						VasItemID: 103,
+						Price:     money.FromFloat(30.5),
+						Message:   "price of vas-item 103 cannot be more than the price of item 3",
+					},
+				},
+			}},
+			WantCode: http.StatusOK,
+		},
+		{
+			Name:   "Server should return 200 and display only the items of the second cart.",
+			CartID: 2,
+			ExpectedResponse: cart.CartResponse{Result: true, Message: cart.CartMessageResponse{
+				Items: []item.ItemResponse{
+					{
+						ItemID:         1,
+						CategoryID:     1001,
+						SellerID:       7,
+						Price:          money.FromFloat(999),
+						Quantity:       1,
+						LineTotal:      money.FromFloat(999),
+						Discount:       money.FromFloat(247.52),
+						FinalLineTotal: money.FromFloat(751.48),
+						VasItems: []item.VasItemResponse{
+							{
+								VasItemID:      105,
+								CategoryID:     3242,
+								SellerID:       5003,
+								Price:          money.FromFloat(10),
+								Quantity:       1,
+								LineTotal:      money.FromFloat(10),
+								Discount:       money.FromFloat(2.48),
+								FinalLineTotal: money.FromFloat(7.52),
+							},
+						},
+					},
+				},
+				TotalPrice:        money.FromFloat(759),
+				AppliedPromotions: []cart.AppliedPromotionResponse{{PromotionID: 1232, Discount: money.FromFloat(250)}},
+				TotalDiscount:     money.FromFloat(250),
+				Notices:           []cart.CartNoticeResponse{},
 			}},
 			WantCode: http.StatusOK,
 		},
@@ -112,7 +200,8 @@ func TestDisplayCart(t *testing.T) {
 
 		t.Run(tt.Name, func(t *testing.T) {
 			gofight.New().
-				GET("/api/cart").
+				GET(fmt.Sprintf("/api/carts/%d", tt.CartID)).
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
 				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
 					response = r
 				})
@@ -132,3 +221,165 @@ func TestDisplayCart(t *testing.T) {
 		})
 	}
 }
+
+func TestDisplayCartPriceChanges(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	Convey("When client displays a cart whose prices changed in the catalog", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/6").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		So(response.Code, ShouldEqual, http.StatusOK)
+
+		var res cart.CartResponse
+		So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+		So(res.Message.Items, ShouldHaveLength, 3)
+
+		Convey("Then the lines should have the current prices and the changed ones should be marked", func() {
+			So(res.Message.Items[0].Price, ShouldEqual, money.FromFloat(120))
+			So(res.Message.Items[0].PreviousPrice, ShouldResemble, previousPrice(100))
+			So(res.Message.Items[0].PriceChange, ShouldEqual, item.PRICE_INCREASED)
+
+			So(res.Message.Items[0].VasItems[0].Price, ShouldEqual, money.FromFloat(150))
+			So(res.Message.Items[0].VasItems[0].PreviousPrice, ShouldResemble, previousPrice(10))
+			So(res.Message.Items[0].VasItems[0].PriceChange, ShouldEqual, item.PRICE_INCREASED)
+
+			So(res.Message.Items[1].Price, ShouldEqual, money.FromFloat(40))
+			So(res.Message.Items[1].PreviousPrice, ShouldResemble, previousPrice(50))
+			So(res.Message.Items[1].PriceChange, ShouldEqual, item.PRICE_DECREASED)
+
+			So(res.Message.Items[2].Price, ShouldEqual, money.FromFloat(30))
+			So(res.Message.Items[2].PreviousPrice, ShouldBeNil)
+			So(res.Message.Items[2].PriceChange, ShouldBeEmpty)
+		})
+
+		Convey("Then the price changes and the broken rules should be listed", func() {
+			So(res.Message.Notices, ShouldResemble, []cart.CartNoticeResponse{
+				{Type: cart.PRICE_INCREASED_NOTICE, ItemID: 6, PreviousPrice: previousPrice(100), Price: money.FromFloat(120), Message: "price of item 6 rose from 100.00 to 120.00"},
+				{Type: cart.PRICE_INCREASED_NOTICE, ItemID: 6, VasItemID: 106, PreviousPrice: previousPrice(10), Price: money.FromFloat(150), Message: "price of vas-item 106 of item 6 rose from 10.00 to 150.00"},
+				{Type: cart.VAS_ITEM_PRICE_NOTICE, ItemID: 6, VasItemID: 106, Price: money.FromFloat(150), Message: "price of vas-item 106 cannot be more than the price of item 6"},
+				{Type: cart.PRICE_DECREASED_NOTICE, ItemID: 7, PreviousPrice: previousPrice(50), Price: money.FromFloat(40), Message: "price of item 7 fell from 50.00 to 40.00"},
+				{Type: cart.NOT_IN_CATALOG_NOTICE, ItemID: 99, Price: money.FromFloat(30), Message: "item 99 is not in the catalog anymore"},
+			})
+		})
+
+		Convey("Then the prices stored in the cart should not be changed", func() {
+			var count int64
+			err := TestDB.Table("items").Where("deleted_at IS NULL AND cart_id = 6 AND item_id = 6 AND price = ?", 100).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client displays a cart with a free line that is over the price limit with the current prices", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/7").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		So(response.Code, ShouldEqual, http.StatusOK)
+
+		var res cart.CartResponse
+		So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+
+		Convey("Then the line added for free should be reported and the limit listed after it", func() {
+			So(res.Message.Items[0].PreviousPrice, ShouldResemble, previousPrice(0))
+			So(res.Message.Items[0].PriceChange, ShouldEqual, item.PRICE_INCREASED)
+
+			So(res.Message.Notices, ShouldHaveLength, 2)
+			So(res.Message.Notices[0], ShouldResemble, cart.CartNoticeResponse{
+				Type:          cart.PRICE_INCREASED_NOTICE,
+				ItemID:        8,
+				PreviousPrice: previousPrice(0),
+				Price:         money.FromFloat(300000),
+				Message:       "price of item 8 rose from 0.00 to 300000.00",
+			})
+			So(res.Message.Notices[1], ShouldResemble, cart.CartNoticeResponse{
+				Type:    cart.MAX_PRICE_OF_CART_NOTICE,
+				Price:   money.FromFloat(600000),
+				Message: fmt.Sprintf("total price of cart cannot be over %s", env.RULES.MaxPriceOfCart),
+			})
+		})
+	})
+}
+
+func previousPrice(price float64) *money.Amount {
+	amount := money.FromFloat(price)
+	return &amount
+}
+
+// countQueries counts the queries sent to the database while run is called.
+func countQueries(t *testing.T, run func()) int {
+	var count int
+	countQuery := func(*gorm.DB) {
+		count++
+	}
+
+	callback := TestDB.Callback()
+	err := errors.Join(
+		callback.Query().After("gorm:query").Register("test:count_query", countQuery),
+		callback.Row().After("gorm:row").Register("test:count_row", countQuery),
+		callback.Raw().After("gorm:raw").Register("test:count_raw", countQuery),
+	)
+	if err != nil {
+		t.Fatalf("error while registering the query counters: %v", err)
+	}
+
+	defer func() {
+		_ = errors.Join(
+			callback.Query().Remove("test:count_query"),
+			callback.Row().Remove("test:count_row"),
+			callback.Raw().Remove("test:count_raw"),
+		)
+	}()
+
+	run()
+	return count
+}
+
+func TestDisplayCartQueryCount(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	Convey("When client displays a cart with many items and vas-items", t, func() {
+		var response gofight.HTTPResponse
+		queryCount := countQueries(t, func() {
+			gofight.New().
+				GET("/api/carts/1").
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+		})
+
+		Convey("Then the items should be loaded without a query per item", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			// items, vas-items, products, promotions, promotion tiers and the coupon of the cart
+			So(queryCount, ShouldBeLessThanOrEqualTo, 6)
+		})
+	})
+}
@@ -0,0 +1,153 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/cart"
+	"checkoutProject/pkg/handlers/item"
+	"encoding/json"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+// cart 30 of the fixtures belongs to the guest session of testhelper.TEST_GUEST_TOKEN
+const guestCartID = 30
+
+// cart 31 of the fixtures belongs to another guest session
+const otherGuestCartID = 31
+
+func TestMergeCart(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	Convey("When a guest merges the guest cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/2/merge").
+			SetJSON(gofight.D{"guest_cart_id": guestCartID}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 401", func() {
+			So(response.Code, ShouldEqual, http.StatusUnauthorized)
+			So(countItemsOfCart(guestCartID), ShouldEqual, 2)
+		})
+	})
+
+	Convey("When client merges the guest cart without the guest token", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/2/merge").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			SetJSON(gofight.D{"guest_cart_id": guestCartID}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 403 and not change the guest cart", func() {
+			So(response.Code, ShouldEqual, http.StatusForbidden)
+			So(countItemsOfCart(guestCartID), ShouldEqual, 2)
+		})
+	})
+
+	Convey("When client merges a guest cart that it did not create", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/2/merge").
+			SetHeader(testhelper.AuthorizationHeaderWithGuestToken(testhelper.TEST_USER_ID, testhelper.TEST_GUEST_TOKEN)).
+			SetJSON(gofight.D{"guest_cart_id": otherGuestCartID}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 404 and not change the carts", func() {
+			So(response.Code, ShouldEqual, http.StatusNotFound)
+			So(countItemsOfCart(otherGuestCartID), ShouldEqual, 1)
+			So(countItemsOfCart(2), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client merges the guest cart into its cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/2/merge").
+			SetHeader(testhelper.AuthorizationHeaderWithGuestToken(testhelper.TEST_USER_ID, testhelper.TEST_GUEST_TOKEN)).
+			SetJSON(gofight.D{"guest_cart_id": guestCartID}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should report the merged and dropped lines", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.MergeCartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Lines, ShouldResemble, []cart.MergeLineResponse{
+				{ItemID: 1, RequestedQuantity: 2, Quantity: 2, Status: item.MERGE_STATUS_MERGED},
+				{ItemID: 1, VasItemID: 104, RequestedQuantity: 1, Quantity: 1, Status: item.MERGE_STATUS_MERGED},
+				{ItemID: 9, RequestedQuantity: 1, Status: item.MERGE_STATUS_DROPPED, Reason: "cannot add a digital item if default item exists in cart"},
+			})
+		})
+
+		Convey("Then the guest cart should be emptied", func() {
+			So(countItemsOfCart(guestCartID), ShouldEqual, 0)
+		})
+
+		Convey("Then the cart should have the merged lines", func() {
+			var response gofight.HTTPResponse
+			gofight.New().
+				GET("/api/carts/2").
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			var res cart.CartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Items, ShouldHaveLength, 1)
+			So(res.Message.Items[0].Quantity, ShouldEqual, 3)
+			So(res.Message.Items[0].VasItems, ShouldHaveLength, 2)
+		})
+	})
+
+	Convey("When client merges an empty guest cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/2/merge").
+			SetHeader(testhelper.AuthorizationHeaderWithGuestToken(testhelper.TEST_USER_ID, testhelper.TEST_GUEST_TOKEN)).
+			SetJSON(gofight.D{"guest_cart_id": guestCartID}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 404", func() {
+			So(response.Code, ShouldEqual, http.StatusNotFound)
+		})
+	})
+
+	Convey("When client merges a cart of a user as the guest cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/2/merge").
+			SetHeader(testhelper.AuthorizationHeaderWithGuestToken(testhelper.TEST_USER_ID, testhelper.TEST_GUEST_TOKEN)).
+			SetJSON(gofight.D{"guest_cart_id": otherUserCartID}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 404 and not change the cart", func() {
+			So(response.Code, ShouldEqual, http.StatusNotFound)
+			So(countItemsOfCart(otherUserCartID), ShouldEqual, 1)
+		})
+	})
+}
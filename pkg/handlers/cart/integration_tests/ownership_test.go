@@ -0,0 +1,211 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/auth"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/cart"
+	"encoding/json"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+// cart 20 of the fixtures belongs to user 2
+const otherUserCartID = 20
+
+func countItemsOfCart(cartID uint) int64 {
+	var count int64
+	err := TestDB.Table("items").Where("deleted_at IS NULL AND cart_id = ?", cartID).Count(&count).Error
+	So(err, ShouldBeNil)
+	return count
+}
+
+func TestCartOwnership(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	Convey("When client sends a request with an invalid token", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/1").
+			SetHeader(gofight.H{"Authorization": "Bearer not-a-token"}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 401", func() {
+			So(response.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+
+	Convey("When a guest displays the cart of a user", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/1").
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should not return the items of the cart", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.CartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Items, ShouldBeEmpty)
+		})
+
+		Convey("Then server should return the guest token of a new guest session", func() {
+			So(response.HeaderMap.Get(auth.GUEST_TOKEN_HEADER), ShouldNotBeEmpty)
+		})
+	})
+
+	Convey("When a guest displays its guest cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/30").
+			SetHeader(testhelper.GuestTokenHeader(testhelper.TEST_GUEST_TOKEN)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return the items of the cart", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.CartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Items, ShouldHaveLength, 2)
+		})
+	})
+
+	Convey("When a guest displays the guest cart of another guest session", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/31").
+			SetHeader(testhelper.GuestTokenHeader(testhelper.TEST_GUEST_TOKEN)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should not return the items of the cart", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.CartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Items, ShouldBeEmpty)
+		})
+	})
+
+	Convey("When a guest adds an item to the guest cart of another guest session", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/31/items").
+			SetJSON(gofight.D{"item_id": 2, "quantity": 1}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 404 and not add the item", func() {
+			So(response.Code, ShouldEqual, http.StatusNotFound)
+			So(countItemsOfCart(otherGuestCartID), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When a guest resets the guest cart of another guest session", t, func() {
+		gofight.New().
+			DELETE("/api/carts/31/reset").
+			SetHeader(testhelper.GuestTokenHeader(testhelper.TEST_GUEST_TOKEN)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {})
+
+		Convey("Then the items of the cart should not be deleted", func() {
+			So(countItemsOfCart(otherGuestCartID), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client adds an item to a guest cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/31/items").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			SetJSON(gofight.D{"item_id": 2, "quantity": 1}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 404 and not add the item", func() {
+			So(response.Code, ShouldEqual, http.StatusNotFound)
+			So(countItemsOfCart(otherGuestCartID), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client displays the cart of another user", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/20").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should not return the items of the cart", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.CartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Items, ShouldBeEmpty)
+		})
+	})
+
+	Convey("When client adds an item to the cart of another user", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			POST("/api/carts/20/items").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			SetJSON(gofight.D{"item_id": 2, "quantity": 1}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return 404 and not add the item", func() {
+			So(response.Code, ShouldEqual, http.StatusNotFound)
+			So(countItemsOfCart(otherUserCartID), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client resets the cart of another user", t, func() {
+		gofight.New().
+			DELETE("/api/carts/20/reset").
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {})
+
+		Convey("Then the items of the cart should not be deleted", func() {
+			So(countItemsOfCart(otherUserCartID), ShouldEqual, 1)
+		})
+	})
+
+	Convey("When the owner displays the cart", t, func() {
+		var response gofight.HTTPResponse
+		gofight.New().
+			GET("/api/carts/20").
+			SetHeader(testhelper.AuthorizationHeader(2)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("Then server should return the items of the cart", func() {
+			So(response.Code, ShouldEqual, http.StatusOK)
+
+			var res cart.CartResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message.Items, ShouldHaveLength, 1)
+		})
+	})
+}
@@ -0,0 +1,84 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/cart"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+type promotionStackingTest struct {
+	Name                      string
+	CartID                    uint
+	ExpectedAppliedPromotions []cart.AppliedPromotionResponse
+	ExpectedTotalDiscount     money.Amount
+	ExpectedTotalPrice        money.Amount
+}
+
+func TestPromotionStacking(t *testing.T) {
+	tests := []promotionStackingTest{
+		{
+			Name:   "Server should apply the category promotion first and the tiered promotion on the reduced total price",
+			CartID: 8,
+			// 5% of 6000 is 300, the reduced total 11700 still reaches the 10000 tier
+			ExpectedAppliedPromotions: []cart.AppliedPromotionResponse{
+				{PromotionID: 5676, Discount: money.FromFloat(300)},
+				{PromotionID: 1232, Discount: money.FromFloat(1000)},
+			},
+			ExpectedTotalDiscount: money.FromFloat(1300),
+			ExpectedTotalPrice:    money.FromFloat(10700),
+		},
+		{
+			Name:   "Server should apply the tiered promotion alone when the reduced total price falls to a lower tier",
+			CartID: 9,
+			// stacked, the reduced total 9900 only reaches the 5000 tier and gives 300 + 500
+			ExpectedAppliedPromotions: []cart.AppliedPromotionResponse{
+				{PromotionID: 1232, Discount: money.FromFloat(1000)},
+			},
+			ExpectedTotalDiscount: money.FromFloat(1000),
+			ExpectedTotalPrice:    money.FromFloat(9200),
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.StackablePromotionsPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				GET(fmt.Sprintf("/api/carts/%d", tt.CartID)).
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client displays a cart with stackable promotions", t, func() {
+				So(response.Code, ShouldEqual, http.StatusOK)
+
+				var res cart.CartResponse
+				So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+
+				Convey("Then the promotions should be applied in their priority", func() {
+					So(res.Message.AppliedPromotions, ShouldResemble, tt.ExpectedAppliedPromotions)
+					So(res.Message.TotalDiscount, ShouldEqual, tt.ExpectedTotalDiscount)
+					So(res.Message.TotalPrice, ShouldEqual, tt.ExpectedTotalPrice)
+				})
+			})
+		})
+	}
+}
@@ -44,7 +44,8 @@ func TestResetCard(t *testing.T) {
 
 		t.Run(tt.Name, func(t *testing.T) {
 			gofight.New().
-				DELETE("/api/cart/reset").
+				DELETE("/api/carts/1/reset").
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
 				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
 					response = r
 				})
@@ -67,19 +68,26 @@ func TestResetCard(t *testing.T) {
 				})
 
 				if response.Code == http.StatusOK {
-					Convey(fmt.Sprintf("Then items, vas-items and item_vas_items tables must be empty"), func() {
+					Convey(fmt.Sprintf("Then items and item_vas_items of the cart must be empty"), func() {
 						var count int64
-						err := TestDB.Table("items").Where("deleted_at IS NULL").Count(&count).Error
+						err := TestDB.Table("items").Where("deleted_at IS NULL AND cart_id = 1").Count(&count).Error
 						So(err, ShouldBeNil)
 						So(count, ShouldEqual, 0)
 
-						err = TestDB.Table("item_vas_items").Where("deleted_at IS NULL").Count(&count).Error
+						err = TestDB.Table("item_vas_items").Where("deleted_at IS NULL AND cart_id = 1").Count(&count).Error
 						So(err, ShouldBeNil)
 						So(count, ShouldEqual, 0)
+					})
 
-						err = TestDB.Table("vas_items").Where("deleted_at IS NULL").Count(&count).Error
+					Convey(fmt.Sprintf("Then items and item_vas_items of other carts must not be deleted"), func() {
+						var count int64
+						err := TestDB.Table("items").Where("deleted_at IS NULL AND cart_id = 2").Count(&count).Error
 						So(err, ShouldBeNil)
-						So(count, ShouldEqual, 0)
+						So(count, ShouldEqual, 1)
+
+						err = TestDB.Table("item_vas_items").Where("deleted_at IS NULL AND cart_id = 2").Count(&count).Error
+						So(err, ShouldBeNil)
+						So(count, ShouldEqual, 1)
 					})
 				}
 			})
@@ -0,0 +1,163 @@
+package cart
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"context"
+	"gorm.io/gorm"
+)
+
+type PromotionManager interface {
+	WithTx(tx *gorm.DB) PromotionManager
+	WithContext(ctx context.Context) PromotionManager
+	Find(filter PromotionFilter) ([]Promotion, error)
+}
+
+type promotionManager struct {
+	db.BaseManager
+}
+
+func NewDefaultPromotionManager() PromotionManager {
+	return NewPromotionManager(db.GetInstance())
+}
+
+func NewPromotionManager(withDB *gorm.DB) PromotionManager {
+	return promotionManager{
+		BaseManager: db.NewBaseManager(withDB),
+	}
+}
+
+func (m promotionManager) WithTx(tx *gorm.DB) PromotionManager {
+	return promotionManager{
+		BaseManager: m.BaseManager.WithTx(tx),
+	}
+}
+
+func (m promotionManager) WithContext(ctx context.Context) PromotionManager {
+	return promotionManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
+func (m promotionManager) Find(filter PromotionFilter) ([]Promotion, error) {
+	var promotions []Promotion
+	query := filter.ToQuery(m.DB)
+
+	query = query.Model(&Promotion{}).
+		Preload("Tiers", func(q *gorm.DB) *gorm.DB {
+			return q.Order("promotion_tiers.min_total_price")
+		}).
+		Order("promotions.promotion_id")
+
+	if err := query.Find(&promotions).Error; err != nil {
+		return []Promotion{}, err
+	}
+
+	return promotions, nil
+}
+
+type CouponManager interface {
+	WithTx(tx *gorm.DB) CouponManager
+	WithContext(ctx context.Context) CouponManager
+	Get(filter CouponFilter) (Coupon, error)
+	GetCouponOfCart(cartID uint) (Coupon, error)
+	IncreaseUsage(filter CouponFilter) (bool, error)
+	CreateCartCoupon(cartCoupon CartCoupon) (CartCoupon, error)
+	IsCartCouponExists(filter CartCouponFilter) (bool, error)
+	DeleteCartCoupons(filter CartCouponFilter) error
+}
+
+type couponManager struct {
+	db.BaseManager
+}
+
+func NewDefaultCouponManager() CouponManager {
+	return NewCouponManager(db.GetInstance())
+}
+
+func NewCouponManager(withDB *gorm.DB) CouponManager {
+	return couponManager{
+		BaseManager: db.NewBaseManager(withDB),
+	}
+}
+
+func (m couponManager) WithTx(tx *gorm.DB) CouponManager {
+	return couponManager{
+		BaseManager: m.BaseManager.WithTx(tx),
+	}
+}
+
+func (m couponManager) WithContext(ctx context.Context) CouponManager {
+	return couponManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
+func (m couponManager) Get(filter CouponFilter) (Coupon, error) {
+	var coupon Coupon
+	query := filter.ToQuery(m.DB)
+
+	if err := query.Model(&Coupon{}).First(&coupon).Error; err != nil {
+		return Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+// GetCouponOfCart returns gorm.ErrRecordNotFound when no coupon is applied to the cart.
+func (m couponManager) GetCouponOfCart(cartID uint) (Coupon, error) {
+	var coupon Coupon
+
+	query := db.OwnedCarts(m.DB, "cart_coupons").Model(&Coupon{}).
+		Joins("JOIN cart_coupons ON cart_coupons.code = coupons.code").
+		Where("cart_coupons.deleted_at IS NULL AND cart_coupons.cart_id = ?", cartID)
+
+	if err := query.First(&coupon).Error; err != nil {
+		return Coupon{}, err
+	}
+
+	return coupon, nil
+}
+
+// IncreaseUsage counts one more use of the coupon, it returns false when the coupon has reached its usage limit.
+func (m couponManager) IncreaseUsage(filter CouponFilter) (bool, error) {
+	query := filter.ToQuery(m.DB).Model(&Coupon{}).
+		Where("(coupons.usage_limit = 0 OR coupons.used_count < coupons.usage_limit)").
+		Update("used_count", gorm.Expr("used_count + 1"))
+
+	if err := query.Error; err != nil {
+		return false, err
+	}
+
+	return query.RowsAffected > 0, nil
+}
+
+func (m couponManager) CreateCartCoupon(cartCoupon CartCoupon) (CartCoupon, error) {
+
+	if err := m.DB.Create(&cartCoupon).Error; err != nil {
+		return CartCoupon{}, err
+	}
+
+	return cartCoupon, nil
+}
+
+func (m couponManager) IsCartCouponExists(filter CartCouponFilter) (bool, error) {
+	var count int64
+	query := filter.ToQuery(m.DB)
+
+	if err := query.Model(&CartCoupon{}).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (m couponManager) DeleteCartCoupons(filter CartCouponFilter) error {
+
+	query := filter.ToQuery(m.DB)
+
+	if err := query.Delete(&CartCoupon{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package cart
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type mockPromotionManagerImpl struct {
+	MWithTx      func(tx *gorm.DB) PromotionManager
+	MWithContext func(ctx context.Context) PromotionManager
+	MFind        func(filter PromotionFilter) ([]Promotion, error)
+}
+
+func NewMockPromotionManager() mockPromotionManagerImpl {
+	return mockPromotionManagerImpl{}
+}
+
+func (m mockPromotionManagerImpl) WithTx(tx *gorm.DB) PromotionManager {
+	return m.MWithTx(tx)
+}
+
+func (m mockPromotionManagerImpl) WithContext(ctx context.Context) PromotionManager {
+	return m.MWithContext(ctx)
+}
+
+func (m mockPromotionManagerImpl) Find(filter PromotionFilter) ([]Promotion, error) {
+	return m.MFind(filter)
+}
+
+type mockCouponManagerImpl struct {
+	MWithTx             func(tx *gorm.DB) CouponManager
+	MWithContext        func(ctx context.Context) CouponManager
+	MGet                func(filter CouponFilter) (Coupon, error)
+	MGetCouponOfCart    func(cartID uint) (Coupon, error)
+	MIncreaseUsage      func(filter CouponFilter) (bool, error)
+	MCreateCartCoupon   func(cartCoupon CartCoupon) (CartCoupon, error)
+	MIsCartCouponExists func(filter CartCouponFilter) (bool, error)
+	MDeleteCartCoupons  func(filter CartCouponFilter) error
+}
+
+func NewMockCouponManager() mockCouponManagerImpl {
+	return mockCouponManagerImpl{}
+}
+
+func (m mockCouponManagerImpl) WithTx(tx *gorm.DB) CouponManager {
+	return m.MWithTx(tx)
+}
+
+func (m mockCouponManagerImpl) WithContext(ctx context.Context) CouponManager {
+	return m.MWithContext(ctx)
+}
+
+func (m mockCouponManagerImpl) Get(filter CouponFilter) (Coupon, error) {
+	return m.MGet(filter)
+}
+
+func (m mockCouponManagerImpl) GetCouponOfCart(cartID uint) (Coupon, error) {
+	return m.MGetCouponOfCart(cartID)
+}
+
+func (m mockCouponManagerImpl) IncreaseUsage(filter CouponFilter) (bool, error) {
+	return m.MIncreaseUsage(filter)
+}
+
+func (m mockCouponManagerImpl) CreateCartCoupon(cartCoupon CartCoupon) (CartCoupon, error) {
+	return m.MCreateCartCoupon(cartCoupon)
+}
+
+func (m mockCouponManagerImpl) IsCartCouponExists(filter CartCouponFilter) (bool, error) {
+	return m.MIsCartCouponExists(filter)
+}
+
+func (m mockCouponManagerImpl) DeleteCartCoupons(filter CartCouponFilter) error {
+	return m.MDeleteCartCoupons(filter)
+}
@@ -0,0 +1,60 @@
+package cart
+
+import (
+	"checkoutProject/pkg/common/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+// Promotion is a rule evaluated on the cart. Which parameters are used depends on the Type:
+// same_seller_percentage uses Percentage, category_percentage uses CategoryID and Percentage,
+// tiered_fixed_amount uses Tiers. Stackable promotions can be applied together, in ascending Priority.
+type Promotion struct {
+	gorm.Model
+	PromotionID uint
+	Type        string
+	Percentage  money.Rate
+	CategoryID  uint
+	Stackable   bool
+	Priority    int
+	StartsAt    *time.Time
+	EndsAt      *time.Time
+	Tiers       []PromotionTier `gorm:"foreignKey:PromotionID;references:PromotionID"`
+}
+
+// PromotionTier gives Discount when the total price of the cart is at least MinTotalPrice.
+type PromotionTier struct {
+	gorm.Model
+	PromotionID   uint
+	MinTotalPrice money.Amount
+	Discount      money.Amount
+}
+
+// Coupon is a discount the customer enters with its Code. Percentage coupons use Percentage, fixed_amount coupons use Amount.
+// A coupon can be used while UsedCount is below UsageLimit, zero UsageLimit means no limit.
+type Coupon struct {
+	gorm.Model
+	Code          string
+	Type          string
+	Percentage    money.Rate
+	Amount        money.Amount
+	MinTotalPrice money.Amount
+	UsageLimit    uint
+	UsedCount     uint
+	ExpiresAt     *time.Time
+}
+
+func (coupon Coupon) isExpired(now time.Time) bool {
+	return coupon.ExpiresAt != nil && !now.Before(*coupon.ExpiresAt)
+}
+
+func (coupon Coupon) isExhausted() bool {
+	return coupon.UsageLimit != 0 && coupon.UsedCount >= coupon.UsageLimit
+}
+
+// CartCoupon is the coupon applied to a cart, a cart has one coupon at most.
+type CartCoupon struct {
+	gorm.Model
+	CartID uint
+	Code   string
+}
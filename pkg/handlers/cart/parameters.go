@@ -0,0 +1,29 @@
+package cart
+
+import "checkoutProject/pkg/handlers/item"
+
+type DisplayCartParams struct {
+	item.CartUriParams
+}
+
+type ResetCartParams struct {
+	item.CartUriParams
+}
+
+type CheckoutParams struct {
+	item.CartUriParams
+}
+
+type ApplyCouponParams struct {
+	item.CartUriParams
+	Code string `json:"code" binding:"required"`
+}
+
+type RemoveCouponParams struct {
+	item.CartUriParams
+}
+
+type MergeCartParams struct {
+	item.CartUriParams
+	GuestCartID uint `json:"guest_cart_id" binding:"required"`
+}
@@ -2,77 +2,135 @@ package cart
 
 import (
 	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/money"
 	"checkoutProject/pkg/handlers/item"
+	"errors"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"sort"
+	"time"
 )
 
-func ApplyPromotion(totalPrice float64, itemManager item.ItemManager, log *logrus.Entry) (float64, uint, error) {
-	promotionDiscounts := make(map[uint]float64)
+// AppliedPromotion is a promotion or the coupon of the cart applied with the discount it gives. CouponCode is set
+// instead of PromotionID for coupons, CategoryID is set when the discount is given only for the items of that category.
+type AppliedPromotion struct {
+	PromotionID uint
+	CouponCode  string
+	CategoryID  uint
+	Discount    money.Amount
+}
 
-	sameSellerPromotionDiscount, err := getSameSellerPromotionDiscount(itemManager, log, totalPrice)
+// ApplyPromotion evaluates the promotions that are active now and the coupon of the cart. Every promotion and the coupon
+// are tried on their own and the stackable promotions are also tried together, the option with the biggest total
+// discount is applied. The promotions are evaluated on the items of the cart that are already loaded.
+func ApplyPromotion(totalPrice money.Amount, cart item.CartContent, promotionManager PromotionManager, couponManager CouponManager, log *logrus.Entry, cartID uint) (money.Amount, []AppliedPromotion, error) {
+	promotions, err := promotionManager.Find(PromotionFilter{ActiveAt: time.Now()})
 	if err != nil {
-		return 0, 0, err
+		log.WithError(err).Error("error while finding the active promotions")
+		return 0, nil, errs.InternalServerErr
+	}
+
+	var maxDiscount money.Amount
+	appliedPromotions := []AppliedPromotion{}
+
+	for _, promotion := range promotions {
+		discount := getPromotionDiscount(promotion, cart, log, totalPrice)
+
+		if discount > maxDiscount {
+			maxDiscount = discount
+			appliedPromotions = []AppliedPromotion{newAppliedPromotion(promotion, discount)}
+		}
 	}
 
-	categoryPromotionDiscount, err := getCategoryPromotionDiscount(itemManager, log)
+	couponDiscount, coupon, err := getCartCouponDiscount(couponManager, log, cartID, totalPrice)
 	if err != nil {
-		return 0, 0, err
+		return 0, nil, err
+	}
+
+	if couponDiscount > maxDiscount {
+		maxDiscount = couponDiscount
+		appliedPromotions = []AppliedPromotion{{CouponCode: coupon.Code, Discount: couponDiscount}}
 	}
 
-	totalPricePromotionDiscount := getTotalPricePromotionDiscount(totalPrice)
+	stackedDiscount, stackedPromotions := getStackedPromotionsDiscount(findStackablePromotions(promotions), cart, log, totalPrice)
 
-	promotionDiscounts[SAME_SELLER_PROMOTION_ID] = sameSellerPromotionDiscount
-	promotionDiscounts[CATEGORY_PROMOTION_ID] = categoryPromotionDiscount
-	promotionDiscounts[TOTAL_PRICE_PROMOTION_ID] = totalPricePromotionDiscount
+	if stackedDiscount > maxDiscount {
+		maxDiscount = stackedDiscount
+		appliedPromotions = stackedPromotions
+	}
 
-	maxDiscount, promID := findMaxDiscountAndPromotion(promotionDiscounts)
-	return maxDiscount, promID, err
+	return maxDiscount, appliedPromotions, nil
 }
 
-func getSameSellerPromotionDiscount(itemManager item.ItemManager, log *logrus.Entry, totalPrice float64) (float64, error) {
-	isAllSameSeller, err := itemManager.AreAllItemsFromSameSeller()
-	if err != nil {
-		log.WithError(err).Error("error while finding total price")
-		return 0, errs.InternalServerErr
+// findStackablePromotions returns the stackable promotions in the order they are applied.
+func findStackablePromotions(promotions []Promotion) []Promotion {
+	var stackablePromotions []Promotion
+	for _, promotion := range promotions {
+		if promotion.Stackable {
+			stackablePromotions = append(stackablePromotions, promotion)
+		}
 	}
 
-	if isAllSameSeller {
-		return SAME_SELLER_PROMOTION_PERCENTAGE * totalPrice, nil
-	}
-	return 0, nil
+	sort.SliceStable(stackablePromotions, func(i, j int) bool {
+		return stackablePromotions[i].Priority < stackablePromotions[j].Priority
+	})
+
+	return stackablePromotions
 }
 
-func getCategoryPromotionDiscount(itemManager item.ItemManager, log *logrus.Entry) (float64, error) {
-	itemsWithCategoryPromotion, err := itemManager.Find(item.ItemFilter{CategoryID: CATEGORY_PROMOTION_APPLICABLE_CAT_ID})
-	if err != nil {
-		log.WithError(err).Error("error while finding category promotion applicable items")
-		return 0, errs.InternalServerErr
+// getStackedPromotionsDiscount applies the promotions one after another, each promotion is evaluated on the total
+// price reduced by the promotions applied before it.
+func getStackedPromotionsDiscount(promotions []Promotion, cart item.CartContent, log *logrus.Entry, totalPrice money.Amount) (money.Amount, []AppliedPromotion) {
+	remainingPrice := totalPrice
+	appliedPromotions := []AppliedPromotion{}
+
+	for _, promotion := range promotions {
+		discount := getPromotionDiscount(promotion, cart, log, remainingPrice)
+
+		if discount > remainingPrice {
+			discount = remainingPrice
+		}
+
+		if discount == 0 {
+			continue
+		}
+
+		remainingPrice -= discount
+		appliedPromotions = append(appliedPromotions, newAppliedPromotion(promotion, discount))
 	}
 
-	if len(itemsWithCategoryPromotion) == 0 {
-		return 0, nil
+	return totalPrice - remainingPrice, appliedPromotions
+}
+
+// getCartCouponDiscount returns no discount when the cart has no coupon or the coupon cannot be used anymore.
+func getCartCouponDiscount(couponManager CouponManager, log *logrus.Entry, cartID uint, totalPrice money.Amount) (money.Amount, Coupon, error) {
+	coupon, err := couponManager.GetCouponOfCart(cartID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, Coupon{}, nil
 	}
 
-	var totalDiscount float64
+	if err != nil {
+		log.WithError(err).Error("error while finding the coupon of the cart")
+		return 0, Coupon{}, errs.InternalServerErr
+	}
 
-	for _, item := range itemsWithCategoryPromotion {
-		totalDiscount += item.OrderPrice() * CATEGORY_PROMOTION_PERCENTAGE
+	if err := couponUsabilityChecks(log, coupon, totalPrice); err != nil {
+		log.WithError(err).Warnf("skipping coupon %s of the cart", coupon.Code)
+		return 0, Coupon{}, nil
 	}
 
-	return totalDiscount, nil
+	return getCouponDiscount(coupon, totalPrice), coupon, nil
 }
 
-func getTotalPricePromotionDiscount(totalPrice float64) float64 {
-	var discount float64
+func getCouponDiscount(coupon Coupon, totalPrice money.Amount) money.Amount {
+	var discount money.Amount
+
+	switch coupon.Type {
+	case PERCENTAGE_COUPON:
+		discount = totalPrice.ApplyRate(coupon.Percentage)
 
-	if totalPrice < 5000 {
-		discount = 250
-	} else if totalPrice < 10000 {
-		discount = 500
-	} else if totalPrice < 50000 {
-		discount = 1000
-	} else {
-		discount = 2000
+	case FIXED_AMOUNT_COUPON:
+		discount = coupon.Amount
 	}
 
 	if discount >= totalPrice {
@@ -82,14 +140,62 @@ func getTotalPricePromotionDiscount(totalPrice float64) float64 {
 	return discount
 }
 
-func findMaxDiscountAndPromotion(discounts map[uint]float64) (float64, uint) {
-	maxDiscount := -1.0
-	var promotionID uint
-	for promID, discount := range discounts {
-		if discount > maxDiscount {
-			maxDiscount = discount
-			promotionID = promID
+func newAppliedPromotion(promotion Promotion, discount money.Amount) AppliedPromotion {
+	appliedPromotion := AppliedPromotion{PromotionID: promotion.PromotionID, Discount: discount}
+	if promotion.Type == CATEGORY_PERCENTAGE_PROMOTION {
+		appliedPromotion.CategoryID = promotion.CategoryID
+	}
+
+	return appliedPromotion
+}
+
+func getPromotionDiscount(promotion Promotion, cart item.CartContent, log *logrus.Entry, totalPrice money.Amount) money.Amount {
+	switch promotion.Type {
+	case SAME_SELLER_PERCENTAGE_PROMOTION:
+		return getSameSellerPromotionDiscount(cart, totalPrice, promotion.Percentage)
+
+	case CATEGORY_PERCENTAGE_PROMOTION:
+		return getCategoryPromotionDiscount(cart, promotion.CategoryID, promotion.Percentage)
+
+	case TIERED_FIXED_AMOUNT_PROMOTION:
+		return getTotalPricePromotionDiscount(totalPrice, promotion.Tiers)
+	}
+
+	log.Warnf("skipping promotion %d with unknown type %s", promotion.PromotionID, promotion.Type)
+	return 0
+}
+
+func getSameSellerPromotionDiscount(cart item.CartContent, totalPrice money.Amount, percentage money.Rate) money.Amount {
+	if cart.AreAllItemsFromSameSeller() {
+		return totalPrice.ApplyRate(percentage)
+	}
+	return 0
+}
+
+func getCategoryPromotionDiscount(cart item.CartContent, categoryID uint, percentage money.Rate) money.Amount {
+	var totalPrice money.Amount
+
+	// the percentage is applied once to the sum of the lines, so rounding does not add up per line
+	for _, item := range cart.ItemsOfCategory(categoryID) {
+		totalPrice += item.OrderPrice()
+	}
+
+	return totalPrice.ApplyRate(percentage)
+}
+
+// tiers must be ordered by MinTotalPrice, the last tier the total price reaches is used
+func getTotalPricePromotionDiscount(totalPrice money.Amount, tiers []PromotionTier) money.Amount {
+	var discount money.Amount
+
+	for _, tier := range tiers {
+		if totalPrice >= tier.MinTotalPrice {
+			discount = tier.Discount
 		}
 	}
-	return maxDiscount, promotionID
+
+	if discount >= totalPrice {
+		discount = totalPrice
+	}
+
+	return discount
 }
@@ -3,225 +3,413 @@ package cart
 import (
 	errs "checkoutProject/pkg/common/errors"
 	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/money"
 	"checkoutProject/pkg/handlers/item"
 	"github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
 	"testing"
+	"time"
 )
 
+const (
+	testSameSellerPromotionID = 9909
+	testCategoryPromotionID   = 5676
+	testTotalPricePromotionID = 1232
+	testPromotionCategoryID   = 3003
+)
+
+var testPromotionTiers = []PromotionTier{
+	{PromotionID: testTotalPricePromotionID, MinTotalPrice: 0, Discount: 250 * money.Unit},
+	{PromotionID: testTotalPricePromotionID, MinTotalPrice: 5000 * money.Unit, Discount: 500 * money.Unit},
+	{PromotionID: testTotalPricePromotionID, MinTotalPrice: 10000 * money.Unit, Discount: 1000 * money.Unit},
+	{PromotionID: testTotalPricePromotionID, MinTotalPrice: 50000 * money.Unit, Discount: 2000 * money.Unit},
+}
+
+var testPromotions = []Promotion{
+	{PromotionID: testSameSellerPromotionID, Type: SAME_SELLER_PERCENTAGE_PROMOTION, Percentage: 10 * money.Percent},
+	{PromotionID: testCategoryPromotionID, Type: CATEGORY_PERCENTAGE_PROMOTION, CategoryID: testPromotionCategoryID, Percentage: 5 * money.Percent},
+	{PromotionID: testTotalPricePromotionID, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: testPromotionTiers},
+}
+
 func TestApplyPromotion(t *testing.T) {
 	log, err := logger.Initialize()
 	if err != nil {
 		t.Fail()
 	}
 
-	mockItemManager := item.NewMockItemManager()
+	mockPromotionManager := NewMockPromotionManager()
+	mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
+		return testPromotions, nil
+	}
+	mockCouponManager := NewMockCouponManager()
+	mockCouponManager.MGetCouponOfCart = func(cartID uint) (Coupon, error) {
+		return Coupon{}, gorm.ErrRecordNotFound
+	}
 
-	Convey("TEST itemManager.find fail", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
+	Convey("TEST promotionManager.find fail", t, func() {
+		mockPromotionManager := NewMockPromotionManager()
+		mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
 			return nil, errs.InternalServerErr
 		}
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return true, nil
-		}
 
-		_, _, err := ApplyPromotion(500, mockItemManager, log.WithFields(logrus.Fields{}))
+		_, _, err := ApplyPromotion(500*money.Unit, item.CartContent{}, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
-	Convey("TEST itemManager.areAllItemsFromSameSeller fail", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{}, nil
+	Convey("TEST no active promotions", t, func() {
+		mockPromotionManager := NewMockPromotionManager()
+		mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
+			So(filter.ActiveAt.IsZero(), ShouldBeFalse)
+			return []Promotion{}, nil
 		}
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return false, errs.InternalServerErr
+
+		discount, appliedPromotions, err := ApplyPromotion(500*money.Unit, item.CartContent{}, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 0)
+		So(appliedPromotions, ShouldBeEmpty)
+	})
+
+	Convey("TEST promotion with unknown type is skipped", t, func() {
+		mockPromotionManager := NewMockPromotionManager()
+		mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
+			return []Promotion{{PromotionID: 1, Type: "unknown"}}, nil
 		}
 
-		_, _, err := ApplyPromotion(500, mockItemManager, log.WithFields(logrus.Fields{}))
-		So(err, ShouldEqual, errs.InternalServerErr)
+		discount, appliedPromotions, err := ApplyPromotion(500*money.Unit, item.CartContent{}, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 0)
+		So(appliedPromotions, ShouldBeEmpty)
 	})
 
 	Convey("TEST success and choose same seller promotion", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{}, nil
-		}
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return true, nil
-		}
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 1, CategoryID: 1001, Quantity: 1, Price: 4000 * money.Unit, SellerID: 2},
+		}}
 
-		discount, promID, err := ApplyPromotion(4000, mockItemManager, log.WithFields(logrus.Fields{}))
+		discount, appliedPromotions, err := ApplyPromotion(4000*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
 		So(err, ShouldEqual, nil)
-		So(discount, ShouldEqual, 400)
-		So(promID, ShouldEqual, SAME_SELLER_PROMOTION_ID)
+		So(discount, ShouldEqual, 400*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{{PromotionID: testSameSellerPromotionID, Discount: 400 * money.Unit}})
 	})
 
 	Convey("TEST success and choose category promotion", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{
-				{
-					ItemID:     2,
-					CategoryID: CATEGORY_PROMOTION_APPLICABLE_CAT_ID,
-					Quantity:   2,
-					Price:      22000,
-					SellerID:   2,
-				},
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 2, CategoryID: testPromotionCategoryID, Quantity: 2, Price: 22000 * money.Unit, SellerID: 2},
+			{ItemID: 3, CategoryID: 1001, Quantity: 1, Price: 0, SellerID: 3},
+		}}
+
+		discount, appliedPromotions, err := ApplyPromotion(44000*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, nil)
+		So(discount, ShouldEqual, 2200*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{{PromotionID: testCategoryPromotionID, CategoryID: testPromotionCategoryID, Discount: 2200 * money.Unit}})
+	})
+
+	Convey("TEST success and choose total price promotion", t, func() {
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 2, CategoryID: testPromotionCategoryID, Quantity: 2, Price: 50 * money.Unit, SellerID: 2},
+		}}
+
+		discount, appliedPromotions, err := ApplyPromotion(360*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, nil)
+		So(discount, ShouldEqual, 250*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{{PromotionID: testTotalPricePromotionID, Discount: 250 * money.Unit}})
+	})
+
+	Convey("TEST success and stack the stackable promotions", t, func() {
+		mockPromotionManager := NewMockPromotionManager()
+		mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
+			return []Promotion{
+				{PromotionID: testSameSellerPromotionID, Type: SAME_SELLER_PERCENTAGE_PROMOTION, Percentage: 10 * money.Percent},
+				{PromotionID: testCategoryPromotionID, Type: CATEGORY_PERCENTAGE_PROMOTION, CategoryID: testPromotionCategoryID, Percentage: 5 * money.Percent, Stackable: true, Priority: 1},
+				{PromotionID: testTotalPricePromotionID, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: testPromotionTiers, Stackable: true, Priority: 2},
 			}, nil
 		}
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return false, nil
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 2, CategoryID: testPromotionCategoryID, Quantity: 2, Price: 22000 * money.Unit, SellerID: 2},
+			{ItemID: 3, CategoryID: 1001, Quantity: 1, Price: 0, SellerID: 3},
+		}}
+
+		discount, appliedPromotions, err := ApplyPromotion(44000*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 3200*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{
+			{PromotionID: testCategoryPromotionID, CategoryID: testPromotionCategoryID, Discount: 2200 * money.Unit},
+			{PromotionID: testTotalPricePromotionID, Discount: 1000 * money.Unit},
+		})
+	})
+
+	Convey("TEST stacked promotions are applied in the order of their priority", t, func() {
+		mockPromotionManager := NewMockPromotionManager()
+		mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
+			return []Promotion{
+				{PromotionID: testSameSellerPromotionID, Type: SAME_SELLER_PERCENTAGE_PROMOTION, Percentage: 10 * money.Percent, Stackable: true, Priority: 2},
+				{PromotionID: testTotalPricePromotionID, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: testPromotionTiers, Stackable: true, Priority: 1},
+			}, nil
 		}
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 2, CategoryID: 1001, Quantity: 1, Price: 5200 * money.Unit, SellerID: 2},
+		}}
 
-		discount, promID, err := ApplyPromotion(44000, mockItemManager, log.WithFields(logrus.Fields{}))
-		So(err, ShouldEqual, nil)
-		So(discount, ShouldEqual, 2200)
-		So(promID, ShouldEqual, CATEGORY_PROMOTION_ID)
+		discount, appliedPromotions, err := ApplyPromotion(5200*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 970*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{
+			{PromotionID: testTotalPricePromotionID, Discount: 500 * money.Unit},
+			{PromotionID: testSameSellerPromotionID, Discount: 470 * money.Unit},
+		})
 	})
 
-	Convey("TEST success and choose total price promotion", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{
-				{
-					ItemID:     2,
-					CategoryID: CATEGORY_PROMOTION_APPLICABLE_CAT_ID,
-					Quantity:   2,
-					Price:      50,
-					SellerID:   2,
-				},
+	Convey("TEST single promotion is chosen when it is bigger than the stacked promotions", t, func() {
+		mockPromotionManager := NewMockPromotionManager()
+		mockPromotionManager.MFind = func(filter PromotionFilter) ([]Promotion, error) {
+			return []Promotion{
+				{PromotionID: testCategoryPromotionID, Type: CATEGORY_PERCENTAGE_PROMOTION, CategoryID: testPromotionCategoryID, Percentage: 5 * money.Percent, Stackable: true, Priority: 1},
+				{PromotionID: testTotalPricePromotionID, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: testPromotionTiers, Stackable: true, Priority: 2},
 			}, nil
 		}
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return true, nil
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 2, CategoryID: testPromotionCategoryID, Quantity: 1, Price: 100 * money.Unit, SellerID: 2},
+		}}
+
+		discount, appliedPromotions, err := ApplyPromotion(5000*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 500*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{{PromotionID: testTotalPricePromotionID, Discount: 500 * money.Unit}})
+	})
+
+	Convey("TEST couponManager.getCouponOfCart fail", t, func() {
+		mockCouponManager := NewMockCouponManager()
+		mockCouponManager.MGetCouponOfCart = func(cartID uint) (Coupon, error) {
+			return Coupon{}, errs.InternalServerErr
 		}
 
-		discount, promID, err := ApplyPromotion(360, mockItemManager, log.WithFields(logrus.Fields{}))
-		So(err, ShouldEqual, nil)
-		So(discount, ShouldEqual, 250)
-		So(promID, ShouldEqual, TOTAL_PRICE_PROMOTION_ID)
+		_, _, err := ApplyPromotion(500*money.Unit, item.CartContent{}, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST success and choose the coupon of the cart", t, func() {
+		mockCouponManager := NewMockCouponManager()
+		mockCouponManager.MGetCouponOfCart = func(cartID uint) (Coupon, error) {
+			So(cartID, ShouldEqual, 1)
+			return Coupon{Code: "SAVE5000", Type: FIXED_AMOUNT_COUPON, Amount: 5000 * money.Unit}, nil
+		}
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 2, CategoryID: testPromotionCategoryID, Quantity: 2, Price: 22000 * money.Unit, SellerID: 2},
+			{ItemID: 3, CategoryID: 1001, Quantity: 1, Price: 0, SellerID: 3},
+		}}
+
+		discount, appliedPromotions, err := ApplyPromotion(44000*money.Unit, cart, mockPromotionManager, mockCouponManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 5000*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{{CouponCode: "SAVE5000", Discount: 5000 * money.Unit}})
 	})
 }
 
-func TestGetSameSellerPromotionDiscount(t *testing.T) {
+func TestGetCartCouponDiscount(t *testing.T) {
 	log, err := logger.Initialize()
 	if err != nil {
 		t.Fail()
 	}
 
-	mockItemManager := item.NewMockItemManager()
+	expiredAt := time.Now().Add(-time.Hour)
 
-	Convey("TEST areAllItemsFromSameSeller fail", t, func() {
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return false, errs.InternalServerErr
+	Convey("TEST cart has no coupon", t, func() {
+		mockCouponManager := NewMockCouponManager()
+		mockCouponManager.MGetCouponOfCart = func(cartID uint) (Coupon, error) {
+			return Coupon{}, gorm.ErrRecordNotFound
 		}
 
-		_, err := getSameSellerPromotionDiscount(mockItemManager, log.WithFields(logrus.Fields{}), 500)
-		So(err, ShouldEqual, errs.InternalServerErr)
+		discount, _, err := getCartCouponDiscount(mockCouponManager, log.WithFields(logrus.Fields{}), 1, 500*money.Unit)
+		So(err, ShouldBeNil)
+		So(discount, ShouldEqual, 0)
 	})
 
-	Convey("TEST areAllItemsFromSameSeller returns false", t, func() {
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return false, nil
+	Convey("TEST coupons that cannot be used are skipped", t, func() {
+		coupons := []Coupon{
+			{Code: "EXPIRED", Type: FIXED_AMOUNT_COUPON, Amount: 10 * money.Unit, ExpiresAt: &expiredAt},
+			{Code: "USEDUP", Type: FIXED_AMOUNT_COUPON, Amount: 10 * money.Unit, UsageLimit: 3, UsedCount: 3},
+			{Code: "BIGCART", Type: FIXED_AMOUNT_COUPON, Amount: 10 * money.Unit, MinTotalPrice: 1000 * money.Unit},
 		}
 
-		discount, err := getSameSellerPromotionDiscount(mockItemManager, log.WithFields(logrus.Fields{}), 500)
-		So(err, ShouldBeNil)
-		So(discount, ShouldEqual, 0)
+		for _, coupon := range coupons {
+			mockCouponManager := NewMockCouponManager()
+			mockCouponManager.MGetCouponOfCart = func(cartID uint) (Coupon, error) {
+				return coupon, nil
+			}
+
+			discount, _, err := getCartCouponDiscount(mockCouponManager, log.WithFields(logrus.Fields{}), 1, 500*money.Unit)
+			So(err, ShouldBeNil)
+			So(discount, ShouldEqual, 0)
+		}
 	})
 
-	Convey("TEST areAllItemsFromSameSeller returns true", t, func() {
-		mockItemManager.MAreAllItemsFromSameSeller = func() (bool, error) {
-			return true, nil
+	Convey("TEST percentage coupon", t, func() {
+		mockCouponManager := NewMockCouponManager()
+		mockCouponManager.MGetCouponOfCart = func(cartID uint) (Coupon, error) {
+			return Coupon{Code: "TEN", Type: PERCENTAGE_COUPON, Percentage: 10 * money.Percent, UsageLimit: 3, UsedCount: 2}, nil
 		}
 
-		discount, err := getSameSellerPromotionDiscount(mockItemManager, log.WithFields(logrus.Fields{}), 500)
+		discount, coupon, err := getCartCouponDiscount(mockCouponManager, log.WithFields(logrus.Fields{}), 1, money.FromFloat(500.55))
 		So(err, ShouldBeNil)
-		So(discount, ShouldEqual, 50)
+		So(discount, ShouldEqual, money.FromFloat(50.06))
+		So(coupon.Code, ShouldEqual, "TEN")
+	})
+}
+
+func TestGetCouponDiscount(t *testing.T) {
+	Convey("TEST fixed amount coupon is capped by the total price", t, func() {
+		coupon := Coupon{Type: FIXED_AMOUNT_COUPON, Amount: 100 * money.Unit}
+
+		So(getCouponDiscount(coupon, 500*money.Unit), ShouldEqual, 100*money.Unit)
+		So(getCouponDiscount(coupon, 60*money.Unit), ShouldEqual, 60*money.Unit)
 	})
 
+	Convey("TEST coupon with unknown type gives no discount", t, func() {
+		So(getCouponDiscount(Coupon{Type: "unknown", Amount: 100 * money.Unit}, 500*money.Unit), ShouldEqual, 0)
+	})
 }
 
-func TestGetCategoryPromotionDiscount(t *testing.T) {
+func TestGetStackedPromotionsDiscount(t *testing.T) {
 	log, err := logger.Initialize()
 	if err != nil {
 		t.Fail()
 	}
 
-	mockItemManager := item.NewMockItemManager()
-
-	Convey("TEST itemManager.find fail", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return nil, errs.InternalServerErr
+	Convey("TEST discount is capped by the remaining price", t, func() {
+		promotions := []Promotion{
+			{PromotionID: 1, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: []PromotionTier{{Discount: 150 * money.Unit}}},
+			{PromotionID: 2, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: []PromotionTier{{Discount: 150 * money.Unit}}},
 		}
 
-		_, err := getCategoryPromotionDiscount(mockItemManager, log.WithFields(logrus.Fields{}))
-		So(err, ShouldEqual, errs.InternalServerErr)
+		discount, appliedPromotions := getStackedPromotionsDiscount(promotions, item.CartContent{}, log.WithFields(logrus.Fields{}), 200*money.Unit)
+		So(discount, ShouldEqual, 200*money.Unit)
+		So(appliedPromotions, ShouldResemble, []AppliedPromotion{
+			{PromotionID: 1, Discount: 150 * money.Unit},
+			{PromotionID: 2, Discount: 50 * money.Unit},
+		})
 	})
 
-	Convey("TEST itemManager.find return no item", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{}, nil
+	Convey("TEST promotions without discount are not applied", t, func() {
+		promotions := []Promotion{
+			{PromotionID: 1, Type: TIERED_FIXED_AMOUNT_PROMOTION, Tiers: []PromotionTier{{MinTotalPrice: 1000 * money.Unit, Discount: 150 * money.Unit}}},
 		}
 
-		discount, err := getCategoryPromotionDiscount(mockItemManager, log.WithFields(logrus.Fields{}))
-		So(err, ShouldBeNil)
+		discount, appliedPromotions := getStackedPromotionsDiscount(promotions, item.CartContent{}, log.WithFields(logrus.Fields{}), 200*money.Unit)
 		So(discount, ShouldEqual, 0)
+		So(appliedPromotions, ShouldBeEmpty)
+	})
+}
+
+func TestFindStackablePromotions(t *testing.T) {
+	Convey("TEST only stackable promotions ordered by priority", t, func() {
+		promotions := findStackablePromotions([]Promotion{
+			{PromotionID: 1, Stackable: true, Priority: 2},
+			{PromotionID: 2},
+			{PromotionID: 3, Stackable: true, Priority: 1},
+			{PromotionID: 4, Stackable: true, Priority: 2},
+		})
+
+		So(len(promotions), ShouldEqual, 3)
+		So(promotions[0].PromotionID, ShouldEqual, 3)
+		So(promotions[1].PromotionID, ShouldEqual, 1)
+		So(promotions[2].PromotionID, ShouldEqual, 4)
 	})
+}
 
-	Convey("TEST itemManager.find return some items", t, func() {
-		mockItemManager.MFind = func(filter item.ItemFilter) ([]item.Item, error) {
-			return []item.Item{
-				{
-					ItemID:     2,
-					CategoryID: CATEGORY_PROMOTION_APPLICABLE_CAT_ID,
-					Quantity:   3,
-					Price:      200,
-				},
-				{
-					ItemID:     2,
-					CategoryID: CATEGORY_PROMOTION_APPLICABLE_CAT_ID,
-					Quantity:   7,
-					Price:      150,
-				},
-			}, nil
+func TestGetSameSellerPromotionDiscount(t *testing.T) {
+	Convey("TEST empty cart", t, func() {
+		discount := getSameSellerPromotionDiscount(item.CartContent{}, 500*money.Unit, 10*money.Percent)
+		So(discount, ShouldEqual, 0)
+	})
+
+	Convey("TEST items from different sellers", t, func() {
+		cart := item.CartContent{Items: []item.Item{{ItemID: 1, SellerID: 1}, {ItemID: 2, SellerID: 2}}}
+
+		discount := getSameSellerPromotionDiscount(cart, 500*money.Unit, 10*money.Percent)
+		So(discount, ShouldEqual, 0)
+	})
+
+	Convey("TEST items from the same seller", t, func() {
+		cart := item.CartContent{Items: []item.Item{{ItemID: 1, SellerID: 2}, {ItemID: 2, SellerID: 2}}}
+
+		discount := getSameSellerPromotionDiscount(cart, 500*money.Unit, 10*money.Percent)
+		So(discount, ShouldEqual, 50*money.Unit)
+	})
+}
+
+func TestGetCategoryPromotionDiscount(t *testing.T) {
+	Convey("TEST no item of the category", t, func() {
+		cart := item.CartContent{Items: []item.Item{{ItemID: 1, CategoryID: 1001, Quantity: 1, Price: 200 * money.Unit}}}
+
+		discount := getCategoryPromotionDiscount(cart, testPromotionCategoryID, 5*money.Percent)
+		So(discount, ShouldEqual, 0)
+	})
+
+	Convey("TEST only the items of the category are discounted", t, func() {
+		cart := item.CartContent{Items: []item.Item{
+			{ItemID: 1, CategoryID: testPromotionCategoryID, Quantity: 3, Price: 200 * money.Unit},
+			{ItemID: 2, CategoryID: testPromotionCategoryID, Quantity: 7, Price: 150 * money.Unit},
+			{ItemID: 3, CategoryID: 1001, Quantity: 1, Price: 1000 * money.Unit},
+		}}
+
+		discount := getCategoryPromotionDiscount(cart, testPromotionCategoryID, 5*money.Percent)
+		So(discount, ShouldEqual, money.FromFloat(82.5))
+	})
+
+	Convey("TEST percentage is rounded once for the sum of the items", t, func() {
+		var cart item.CartContent
+		for i := uint(1); i <= 10; i++ {
+			cart.Items = append(cart.Items, item.Item{ItemID: i, CategoryID: testPromotionCategoryID, Quantity: 1, Price: money.FromFloat(0.30)})
 		}
 
-		discount, err := getCategoryPromotionDiscount(mockItemManager, log.WithFields(logrus.Fields{}))
-		So(err, ShouldBeNil)
-		So(discount, ShouldEqual, 1650*CATEGORY_PROMOTION_PERCENTAGE)
+		discount := getCategoryPromotionDiscount(cart, testPromotionCategoryID, 5*money.Percent)
+		So(discount, ShouldEqual, money.FromFloat(0.15))
 	})
 }
 
 func TestGetTotalPricePromotionDiscount(t *testing.T) {
 
 	Convey("TEST between 0 - 5000", t, func() {
-		discount := getTotalPricePromotionDiscount(2000)
-		So(discount, ShouldEqual, 250)
+		discount := getTotalPricePromotionDiscount(2000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 250*money.Unit)
 
-		discount = getTotalPricePromotionDiscount(200)
-		So(discount, ShouldEqual, 200)
+		discount = getTotalPricePromotionDiscount(200*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 200*money.Unit)
 	})
 
 	Convey("TEST between 5000 - 10000", t, func() {
-		discount := getTotalPricePromotionDiscount(5000)
-		So(discount, ShouldEqual, 500)
+		discount := getTotalPricePromotionDiscount(5000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 500*money.Unit)
 
-		getTotalPricePromotionDiscount(8000)
-		So(discount, ShouldEqual, 500)
+		getTotalPricePromotionDiscount(8000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 500*money.Unit)
 	})
 
 	Convey("TEST between 10000 - 50000", t, func() {
-		discount := getTotalPricePromotionDiscount(10000)
-		So(discount, ShouldEqual, 1000)
+		discount := getTotalPricePromotionDiscount(10000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 1000*money.Unit)
+
+		discount = getTotalPricePromotionDiscount(45000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 1000*money.Unit)
+	})
+
+	Convey("TEST total price below the first tier", t, func() {
+		tiers := []PromotionTier{{MinTotalPrice: 1000 * money.Unit, Discount: 100 * money.Unit}}
+
+		discount := getTotalPricePromotionDiscount(999*money.Unit, tiers)
+		So(discount, ShouldEqual, 0)
 
-		discount = getTotalPricePromotionDiscount(45000)
-		So(discount, ShouldEqual, 1000)
+		discount = getTotalPricePromotionDiscount(1000*money.Unit, tiers)
+		So(discount, ShouldEqual, 100*money.Unit)
 	})
 
 	Convey("TEST 50000+", t, func() {
-		discount := getTotalPricePromotionDiscount(50000)
-		So(discount, ShouldEqual, 2000)
+		discount := getTotalPricePromotionDiscount(50000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 2000*money.Unit)
 
-		discount = getTotalPricePromotionDiscount(264000)
-		So(discount, ShouldEqual, 2000)
+		discount = getTotalPricePromotionDiscount(264000*money.Unit, testPromotionTiers)
+		So(discount, ShouldEqual, 2000*money.Unit)
 	})
 
 }
@@ -0,0 +1,106 @@
+package cart
+
+import (
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	"checkoutProject/pkg/handlers/item"
+	"fmt"
+)
+
+// CartNotice is a line of the cart whose price changed since it was added, or a rule the cart breaks with the current
+// prices. VasItemID is 0 for the items, ItemID and VasItemID are 0 for the rules of the whole cart. PreviousPrice is
+// only set for the price changes, a line added for free has a previous price of 0.
+type CartNotice struct {
+	Type          string
+	ItemID        uint
+	VasItemID     uint
+	PreviousPrice *money.Amount
+	Price         money.Amount
+	Message       string
+}
+
+// IsBlocking returns true for the notices the cart cannot be checked out with, price changes are only reported.
+func (notice CartNotice) IsBlocking() bool {
+	return notice.Type != PRICE_INCREASED_NOTICE && notice.Type != PRICE_DECREASED_NOTICE
+}
+
+// revalidateCart returns the cart with the current catalog prices on its lines and the notices of the lines whose price
+// rose or fell, lines that are not in the catalog anymore keep their price. The total price of the cart and the prices
+// of the vas-items are then checked again with the rules used when they are added. The given cart is not changed.
+func revalidateCart(cart item.CartContent, products map[uint]catalog.Product, rules env.Rules) (item.CartContent, []CartNotice) {
+	// the lines are priced like the items and vas-items are checked when they are added, see item.findCatalogCart
+	revalidated := cart.WithCatalogPrices(products)
+	var notices []CartNotice
+
+	for i, itm := range revalidated.Items {
+		storedItem := cart.Items[i]
+		if _, ok := products[itm.ItemID]; !ok {
+			notices = append(notices, CartNotice{
+				Type:    NOT_IN_CATALOG_NOTICE,
+				ItemID:  itm.ItemID,
+				Price:   itm.Price,
+				Message: fmt.Sprintf("item %d is not in the catalog anymore", itm.ItemID),
+			})
+		} else if notice, changed := priceChangeNotice(storedItem.Price, itm.Price, itm.ItemID, 0); changed {
+			notices = append(notices, notice)
+		}
+
+		for j, vasItm := range revalidated.VasItems[itm.ItemID] {
+			storedVasItem := cart.VasItems[itm.ItemID][j]
+			if _, ok := products[vasItm.VasItemID]; !ok {
+				notices = append(notices, CartNotice{
+					Type:      NOT_IN_CATALOG_NOTICE,
+					ItemID:    itm.ItemID,
+					VasItemID: vasItm.VasItemID,
+					Price:     vasItm.Price,
+					Message:   fmt.Sprintf("vas-item %d of item %d is not in the catalog anymore", vasItm.VasItemID, itm.ItemID),
+				})
+			} else if notice, changed := priceChangeNotice(storedVasItem.Price, vasItm.Price, itm.ItemID, vasItm.VasItemID); changed {
+				notices = append(notices, notice)
+			}
+
+			// the same check as addVasItemPriceChecks with the current prices
+			if vasItm.Price > itm.Price {
+				notices = append(notices, CartNotice{
+					Type:      VAS_ITEM_PRICE_NOTICE,
+					ItemID:    itm.ItemID,
+					VasItemID: vasItm.VasItemID,
+					Price:     vasItm.Price,
+					Message:   fmt.Sprintf("price of vas-item %d cannot be more than the price of item %d", vasItm.VasItemID, itm.ItemID),
+				})
+			}
+		}
+	}
+
+	if totalPrice := revalidated.TotalPrice(); totalPrice > rules.MaxPriceOfCart {
+		notices = append(notices, CartNotice{
+			Type:    MAX_PRICE_OF_CART_NOTICE,
+			Price:   totalPrice,
+			Message: fmt.Sprintf("total price of cart cannot be over %s", rules.MaxPriceOfCart),
+		})
+	}
+
+	return revalidated, notices
+}
+
+func priceChangeNotice(previousPrice money.Amount, price money.Amount, itemID uint, vasItemID uint) (CartNotice, bool) {
+	if price == previousPrice {
+		return CartNotice{}, false
+	}
+
+	notice := CartNotice{Type: PRICE_INCREASED_NOTICE, ItemID: itemID, VasItemID: vasItemID, PreviousPrice: &previousPrice, Price: price}
+	change := "rose"
+	if price < previousPrice {
+		notice.Type = PRICE_DECREASED_NOTICE
+		change = "fell"
+	}
+
+	if vasItemID == 0 {
+		notice.Message = fmt.Sprintf("price of item %d %s from %s to %s", itemID, change, previousPrice, price)
+	} else {
+		notice.Message = fmt.Sprintf("price of vas-item %d of item %d %s from %s to %s", vasItemID, itemID, change, previousPrice, price)
+	}
+
+	return notice, true
+}
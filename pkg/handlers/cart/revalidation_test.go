@@ -0,0 +1,134 @@
+package cart
+
+import (
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	"checkoutProject/pkg/handlers/item"
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+var testRevalidationRules = env.DefaultRules()
+
+func testPreviousPrice(price money.Amount) *money.Amount {
+	return &price
+}
+
+func TestRevalidateCart(t *testing.T) {
+	products := map[uint]catalog.Product{
+		1:   {ProductID: 1, Price: 100 * money.Unit},
+		2:   {ProductID: 2, Price: 40 * money.Unit},
+		101: {ProductID: 101, Price: 10 * money.Unit},
+		102: {ProductID: 102, Price: 60 * money.Unit},
+	}
+
+	Convey("TEST unchanged prices", t, func() {
+		cart := item.CartContent{
+			Items:    []item.Item{{ItemID: 1, Price: 100 * money.Unit, Quantity: 1}},
+			VasItems: map[uint][]item.ItemVasItem{1: {{ItemID: 1, VasItemID: 101, Price: 10 * money.Unit, Quantity: 1}}},
+		}
+
+		revalidated, notices := revalidateCart(cart, products, testRevalidationRules)
+		So(notices, ShouldBeEmpty)
+		So(revalidated, ShouldResemble, cart)
+	})
+
+	Convey("TEST lines get the current prices and the changes are listed", t, func() {
+		cart := item.CartContent{
+			Items: []item.Item{
+				{ItemID: 1, Price: 80 * money.Unit, Quantity: 1},
+				{ItemID: 2, Price: 50 * money.Unit, Quantity: 2},
+			},
+			VasItems: map[uint][]item.ItemVasItem{1: {{ItemID: 1, VasItemID: 101, Price: 5 * money.Unit, Quantity: 1}}},
+		}
+
+		revalidated, notices := revalidateCart(cart, products, testRevalidationRules)
+		So(revalidated.Items[0].Price, ShouldEqual, 100*money.Unit)
+		So(revalidated.Items[1].Price, ShouldEqual, 40*money.Unit)
+		So(revalidated.VasItems[1][0].Price, ShouldEqual, 10*money.Unit)
+		So(notices, ShouldResemble, []CartNotice{
+			{Type: PRICE_INCREASED_NOTICE, ItemID: 1, PreviousPrice: testPreviousPrice(80 * money.Unit), Price: 100 * money.Unit, Message: "price of item 1 rose from 80.00 to 100.00"},
+			{Type: PRICE_INCREASED_NOTICE, ItemID: 1, VasItemID: 101, PreviousPrice: testPreviousPrice(5 * money.Unit), Price: 10 * money.Unit, Message: "price of vas-item 101 of item 1 rose from 5.00 to 10.00"},
+			{Type: PRICE_DECREASED_NOTICE, ItemID: 2, PreviousPrice: testPreviousPrice(50 * money.Unit), Price: 40 * money.Unit, Message: "price of item 2 fell from 50.00 to 40.00"},
+		})
+
+		Convey("TEST the given cart is not changed", func() {
+			So(cart.Items[0].Price, ShouldEqual, 80*money.Unit)
+			So(cart.VasItems[1][0].Price, ShouldEqual, 5*money.Unit)
+		})
+	})
+
+	Convey("TEST free lines that are not free anymore are reported and checked", t, func() {
+		rules := testRevalidationRules
+		rules.MaxPriceOfCart = 150 * money.Unit
+		cart := item.CartContent{
+			Items:    []item.Item{{ItemID: 1, Price: 0, Quantity: 2}},
+			VasItems: map[uint][]item.ItemVasItem{1: {{ItemID: 1, VasItemID: 102, Price: 0, Quantity: 1}}},
+		}
+
+		revalidated, notices := revalidateCart(cart, products, rules)
+		So(revalidated.Items[0].Price, ShouldEqual, 100*money.Unit)
+		So(notices, ShouldResemble, []CartNotice{
+			{Type: PRICE_INCREASED_NOTICE, ItemID: 1, PreviousPrice: testPreviousPrice(0), Price: 100 * money.Unit, Message: "price of item 1 rose from 0.00 to 100.00"},
+			{Type: PRICE_INCREASED_NOTICE, ItemID: 1, VasItemID: 102, PreviousPrice: testPreviousPrice(0), Price: 60 * money.Unit, Message: "price of vas-item 102 of item 1 rose from 0.00 to 60.00"},
+			{Type: MAX_PRICE_OF_CART_NOTICE, Price: 260 * money.Unit, Message: fmt.Sprintf("total price of cart cannot be over %s", rules.MaxPriceOfCart)},
+		})
+	})
+
+	Convey("TEST lines not in the catalog keep their price", t, func() {
+		cart := item.CartContent{
+			Items:    []item.Item{{ItemID: 3, Price: 20 * money.Unit, Quantity: 1}},
+			VasItems: map[uint][]item.ItemVasItem{3: {{ItemID: 3, VasItemID: 103, Price: 5 * money.Unit, Quantity: 1}}},
+		}
+
+		revalidated, notices := revalidateCart(cart, products, testRevalidationRules)
+		So(revalidated, ShouldResemble, cart)
+		So(notices, ShouldResemble, []CartNotice{
+			{Type: NOT_IN_CATALOG_NOTICE, ItemID: 3, Price: 20 * money.Unit, Message: "item 3 is not in the catalog anymore"},
+			{Type: NOT_IN_CATALOG_NOTICE, ItemID: 3, VasItemID: 103, Price: 5 * money.Unit, Message: "vas-item 103 of item 3 is not in the catalog anymore"},
+		})
+	})
+
+	Convey("TEST vas-item more expensive than its item with the current prices", t, func() {
+		cart := item.CartContent{
+			Items:    []item.Item{{ItemID: 2, Price: 40 * money.Unit, Quantity: 1}},
+			VasItems: map[uint][]item.ItemVasItem{2: {{ItemID: 2, VasItemID: 102, Price: 60 * money.Unit, Quantity: 1}}},
+		}
+
+		_, notices := revalidateCart(cart, products, testRevalidationRules)
+		So(notices, ShouldResemble, []CartNotice{
+			{Type: VAS_ITEM_PRICE_NOTICE, ItemID: 2, VasItemID: 102, Price: 60 * money.Unit, Message: "price of vas-item 102 cannot be more than the price of item 2"},
+		})
+	})
+
+	Convey("TEST total price over the limit with the current prices", t, func() {
+		rules := testRevalidationRules
+		rules.MaxPriceOfCart = 150 * money.Unit
+		cart := item.CartContent{
+			Items: []item.Item{{ItemID: 1, Price: 70 * money.Unit, Quantity: 2}},
+		}
+
+		_, notices := revalidateCart(cart, products, rules)
+		So(notices, ShouldHaveLength, 2)
+		So(notices[1], ShouldResemble, CartNotice{
+			Type:    MAX_PRICE_OF_CART_NOTICE,
+			Price:   200 * money.Unit,
+			Message: fmt.Sprintf("total price of cart cannot be over %s", rules.MaxPriceOfCart),
+		})
+	})
+}
+
+func TestCartNoticeIsBlocking(t *testing.T) {
+	Convey("TEST price changes do not block the checkout", t, func() {
+		So(CartNotice{Type: PRICE_INCREASED_NOTICE}.IsBlocking(), ShouldBeFalse)
+		So(CartNotice{Type: PRICE_DECREASED_NOTICE}.IsBlocking(), ShouldBeFalse)
+	})
+
+	Convey("TEST broken rules block the checkout", t, func() {
+		So(CartNotice{Type: NOT_IN_CATALOG_NOTICE}.IsBlocking(), ShouldBeTrue)
+		So(CartNotice{Type: VAS_ITEM_PRICE_NOTICE}.IsBlocking(), ShouldBeTrue)
+		So(CartNotice{Type: MAX_PRICE_OF_CART_NOTICE}.IsBlocking(), ShouldBeTrue)
+	})
+}
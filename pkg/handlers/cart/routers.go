@@ -2,7 +2,9 @@ package cart
 
 import (
 	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/logger"
 	"checkoutProject/pkg/common/routing"
+	"checkoutProject/pkg/common/validator"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -27,6 +29,10 @@ func (ctr cartRouter) Register(group *gin.RouterGroup) {
 	cartGroup := group.Group("")
 	cartGroup.GET("", ctr.DisplayCartRoute)
 	cartGroup.DELETE("reset", ctr.ResetCartRoute)
+	cartGroup.POST("checkout", ctr.CheckoutRoute)
+	cartGroup.POST("coupon", ctr.ApplyCouponRoute)
+	cartGroup.DELETE("coupon", ctr.RemoveCouponRoute)
+	cartGroup.POST("merge", ctr.MergeCartRoute)
 }
 
 func (ctr cartRouter) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
@@ -34,7 +40,19 @@ func (ctr cartRouter) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
 }
 
 func (ctr cartRouter) DisplayCartRoute(c *gin.Context) {
-	responder, err := ctr.cartController.DisplayCart()
+	ctx := c.Request.Context()
+	log := ctr.formattedLogger(logger.FromContext(ctx)).WithField("location", "DisplayCartRoute")
+
+	var params DisplayCartParams
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := ctr.cartController.DisplayCart(ctx, params)
 	if err != nil {
 		c.JSON(apiresponse.Failed(err))
 		return
@@ -43,7 +61,117 @@ func (ctr cartRouter) DisplayCartRoute(c *gin.Context) {
 }
 
 func (ctr cartRouter) ResetCartRoute(c *gin.Context) {
-	responder, err := ctr.cartController.ResetCart()
+	ctx := c.Request.Context()
+	log := ctr.formattedLogger(logger.FromContext(ctx)).WithField("location", "ResetCartRoute")
+
+	var params ResetCartParams
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := ctr.cartController.ResetCart(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+	c.JSON(apiresponse.OK(responder))
+}
+
+func (ctr cartRouter) CheckoutRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := ctr.formattedLogger(logger.FromContext(ctx)).WithField("location", "CheckoutRoute")
+
+	var params CheckoutParams
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := ctr.cartController.Checkout(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+	c.JSON(apiresponse.Created(responder))
+}
+
+func (ctr cartRouter) ApplyCouponRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := ctr.formattedLogger(logger.FromContext(ctx)).WithField("location", "ApplyCouponRoute")
+
+	var params ApplyCouponParams
+
+	if err := c.ShouldBindUri(&params.CartUriParams); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := ctr.cartController.ApplyCoupon(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+	c.JSON(apiresponse.OK(responder))
+}
+
+func (ctr cartRouter) RemoveCouponRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := ctr.formattedLogger(logger.FromContext(ctx)).WithField("location", "RemoveCouponRoute")
+
+	var params RemoveCouponParams
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := ctr.cartController.RemoveCoupon(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+	c.JSON(apiresponse.OK(responder))
+}
+
+func (ctr cartRouter) MergeCartRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := ctr.formattedLogger(logger.FromContext(ctx)).WithField("location", "MergeCartRoute")
+
+	var params MergeCartParams
+
+	if err := c.ShouldBindUri(&params.CartUriParams); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := ctr.cartController.MergeCart(ctx, params)
 	if err != nil {
 		c.JSON(apiresponse.Failed(err))
 		return
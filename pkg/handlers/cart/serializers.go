@@ -1,8 +1,8 @@
 package cart
 
 import (
+	"checkoutProject/pkg/common/money"
 	"checkoutProject/pkg/handlers/item"
-	"math"
 )
 
 type CartResponse struct {
@@ -11,10 +11,29 @@ type CartResponse struct {
 }
 
 type CartMessageResponse struct {
-	Items              []item.ItemResponse `json:"items"`
-	TotalPrice         float64             `json:"total_price"`
-	AppliedPromotionID uint                `json:"applied_promotion_id"`
-	TotalDiscount      float64             `json:"total_discount"`
+	Items             []item.ItemResponse        `json:"items"`
+	TotalPrice        money.Amount               `json:"total_price"`
+	AppliedPromotions []AppliedPromotionResponse `json:"applied_promotions"`
+	TotalDiscount     money.Amount               `json:"total_discount"`
+	Notices           []CartNoticeResponse       `json:"notices"`
+}
+
+// CartNoticeResponse is a price change of a line or a rule the cart breaks with the current prices of the catalog, the
+// cart cannot be checked out while it has notices other than price changes. previous_price is only set for the price
+// changes, item_id and vas_item_id mark the changed line.
+type CartNoticeResponse struct {
+	Type          string        `json:"type"`
+	ItemID        uint          `json:"item_id"`
+	VasItemID     uint          `json:"vas_item_id"`
+	PreviousPrice *money.Amount `json:"previous_price,omitempty"`
+	Price         money.Amount  `json:"price"`
+	Message       string        `json:"message"`
+}
+
+type AppliedPromotionResponse struct {
+	PromotionID uint         `json:"promotion_id"`
+	CouponCode  string       `json:"coupon_code,omitempty"`
+	Discount    money.Amount `json:"discount"`
 }
 
 type CartSerializer struct {
@@ -30,10 +49,11 @@ func (s CartSerializer) Response() interface{} {
 }
 
 type CartMessageSerializer struct {
-	Items              []item.ItemSerializer
-	TotalPrice         float64
-	AppliedPromotionID uint
-	TotalDiscount      float64
+	Items             []item.ItemSerializer
+	TotalPrice        money.Amount
+	AppliedPromotions []AppliedPromotion
+	TotalDiscount     money.Amount
+	Notices           []CartNotice
 }
 
 func (s CartMessageSerializer) Response() interface{} {
@@ -42,12 +62,84 @@ func (s CartMessageSerializer) Response() interface{} {
 		cartItems = append(cartItems, itm.Response().(item.ItemResponse))
 	}
 
-	totalDiscountFormatted := math.Round(s.TotalDiscount*100) / 100
-	totalPriceFormatted := math.Round(s.TotalPrice*100) / 100
+	appliedPromotions := []AppliedPromotionResponse{}
+	for _, promotion := range s.AppliedPromotions {
+		appliedPromotions = append(appliedPromotions, AppliedPromotionResponse{
+			PromotionID: promotion.PromotionID,
+			CouponCode:  promotion.CouponCode,
+			Discount:    promotion.Discount,
+		})
+	}
+
+	notices := []CartNoticeResponse{}
+	for _, notice := range s.Notices {
+		notices = append(notices, CartNoticeResponse{
+			Type:          notice.Type,
+			ItemID:        notice.ItemID,
+			VasItemID:     notice.VasItemID,
+			PreviousPrice: notice.PreviousPrice,
+			Price:         notice.Price,
+			Message:       notice.Message,
+		})
+	}
+
 	return CartMessageResponse{
-		Items:              cartItems,
-		TotalPrice:         totalPriceFormatted,
-		AppliedPromotionID: s.AppliedPromotionID,
-		TotalDiscount:      totalDiscountFormatted,
+		Items:             cartItems,
+		TotalPrice:        s.TotalPrice,
+		AppliedPromotions: appliedPromotions,
+		TotalDiscount:     s.TotalDiscount,
+		Notices:           notices,
 	}
 }
+
+type MergeCartResponse struct {
+	Result  bool                     `json:"result"`
+	Message MergeCartMessageResponse `json:"message"`
+}
+
+type MergeCartMessageResponse struct {
+	Lines []MergeLineResponse `json:"lines"`
+}
+
+// MergeLineResponse is a line of the guest cart, vas_item_id is 0 for the items. Quantity is the quantity added to
+// the cart and reason is the rule that clamped or dropped the line.
+type MergeLineResponse struct {
+	ItemID            uint   `json:"item_id"`
+	VasItemID         uint   `json:"vas_item_id"`
+	RequestedQuantity uint   `json:"requested_quantity"`
+	Quantity          uint   `json:"quantity"`
+	Status            string `json:"status"`
+	Reason            string `json:"reason,omitempty"`
+}
+
+type MergeCartSerializer struct {
+	Result  bool
+	Message MergeCartMessageSerializer
+}
+
+func (s MergeCartSerializer) Response() interface{} {
+	return MergeCartResponse{
+		Result:  s.Result,
+		Message: s.Message.Response().(MergeCartMessageResponse),
+	}
+}
+
+type MergeCartMessageSerializer struct {
+	Lines []item.MergeResult
+}
+
+func (s MergeCartMessageSerializer) Response() interface{} {
+	lines := []MergeLineResponse{}
+	for _, line := range s.Lines {
+		lines = append(lines, MergeLineResponse{
+			ItemID:            line.ItemID,
+			VasItemID:         line.VasItemID,
+			RequestedQuantity: line.RequestedQuantity,
+			Quantity:          line.Quantity,
+			Status:            line.Status,
+			Reason:            line.Reason,
+		})
+	}
+
+	return MergeCartMessageResponse{Lines: lines}
+}
@@ -0,0 +1,13 @@
+package catalog
+
+// content types of the files the products are imported from
+const (
+	CSV_CONTENT_TYPE  = "text/csv"
+	JSON_CONTENT_TYPE = "application/json"
+)
+
+// MAX_IMPORT_SIZE is the maximum size of an imported file in bytes.
+const MAX_IMPORT_SIZE = 10 << 20
+
+// IMPORT_BATCH_SIZE is the number of products written with a single query while importing.
+const IMPORT_BATCH_SIZE = 500
@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"checkoutProject/pkg/common/apiresponse"
+	db "checkoutProject/pkg/common/database"
+	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+type CatalogController interface {
+	ImportProducts(ctx context.Context, params ImportProductsParams) (apiresponse.Responder, error)
+}
+
+type catalogController struct {
+	productManager ProductManager
+}
+
+func NewCatalogController(productManager ProductManager) CatalogController {
+	return catalogController{
+		productManager: productManager,
+	}
+}
+
+func NewDefaultCatalogController() CatalogController {
+	return NewCatalogController(NewDefaultProductManager())
+}
+
+func (c catalogController) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
+	return l.WithFields(logrus.Fields{"api_version": "1", "controller": "catalog"})
+}
+
+func (c catalogController) ImportProducts(ctx context.Context, params ImportProductsParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Import Products",
+	})
+
+	products, err := parseProducts(params.ContentType, params.File)
+	if err != nil {
+		log.WithError(err).Error("could not parse the products")
+		return nil, err
+	}
+
+	err = validateProducts(products)
+	if err != nil {
+		log.WithError(err).Error("invalid products")
+		return nil, err
+	}
+
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	err = c.productManager.WithTx(tx).Import(products)
+	if err != nil {
+		log.WithError(err).Error("error while importing the products")
+		return nil, errs.InternalServerErr
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	log.WithField("products", len(products)).Info("imported the products")
+
+	return ImportProductsSerializer{Result: true, Imported: len(products)}, nil
+}
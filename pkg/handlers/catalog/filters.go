@@ -0,0 +1,18 @@
+package catalog
+
+import "gorm.io/gorm"
+
+type ProductFilter struct {
+	ProductID  uint
+	ProductIDs []uint
+}
+
+func (f ProductFilter) ToQuery(q *gorm.DB) *gorm.DB {
+	q = q.Where(Product{ProductID: f.ProductID})
+
+	if len(f.ProductIDs) > 0 {
+		q = q.Where("products.product_id IN ?", f.ProductIDs)
+	}
+
+	return q
+}
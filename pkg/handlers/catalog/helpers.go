@@ -0,0 +1,140 @@
+package catalog
+
+import (
+	"checkoutProject/pkg/common/money"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvColumns are the columns a CSV file must have in its header, in any order.
+var csvColumns = []string{"product_id", "category_id", "seller_id", "price"}
+
+func parseProducts(contentType string, file io.Reader) ([]Product, error) {
+	switch contentType {
+	case CSV_CONTENT_TYPE:
+		return parseProductsCSV(file)
+	case JSON_CONTENT_TYPE:
+		return parseProductsJSON(file)
+	}
+
+	return nil, fmt.Errorf("unsupported content type %q, products can be imported from %s or %s", contentType, CSV_CONTENT_TYPE, JSON_CONTENT_TYPE)
+}
+
+func parseProductsCSV(file io.Reader) ([]Product, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the header: %w", err)
+	}
+
+	columnIndexes := make(map[string]int)
+	for i, column := range header {
+		columnIndexes[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range csvColumns {
+		if _, ok := columnIndexes[column]; !ok {
+			return nil, fmt.Errorf("header does not have the %s column", column)
+		}
+	}
+
+	var products []Product
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the file: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		product, err := parseProductRecord(record, columnIndexes)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+func parseProductRecord(record []string, columnIndexes map[string]int) (Product, error) {
+	ids := make(map[string]uint)
+	for _, column := range []string{"product_id", "category_id", "seller_id"} {
+		value := strings.TrimSpace(record[columnIndexes[column]])
+		id, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			return Product{}, fmt.Errorf("invalid %s %q", column, value)
+		}
+		ids[column] = uint(id)
+	}
+
+	value := strings.TrimSpace(record[columnIndexes["price"]])
+	price, err := money.Parse(value)
+	if err != nil {
+		return Product{}, fmt.Errorf("invalid price %q", value)
+	}
+
+	return Product{ProductID: ids["product_id"], CategoryID: ids["category_id"], SellerID: ids["seller_id"], Price: price}, nil
+}
+
+func parseProductsJSON(file io.Reader) ([]Product, error) {
+	var params []ProductParams
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&params); err != nil {
+		return nil, fmt.Errorf("cannot parse the file: %w", err)
+	}
+
+	products := make([]Product, 0, len(params))
+	for _, product := range params {
+		products = append(products, Product{
+			ProductID:  product.ProductID,
+			CategoryID: product.CategoryID,
+			SellerID:   product.SellerID,
+			Price:      product.Price,
+		})
+	}
+
+	return products, nil
+}
+
+// validateProducts checks the products of a file before any of them is imported, so a file is imported completely or not at all.
+func validateProducts(products []Product) error {
+	if len(products) == 0 {
+		return fmt.Errorf("file does not have any products")
+	}
+
+	productIDs := make(map[uint]bool)
+	for _, product := range products {
+		if product.ProductID == 0 {
+			return fmt.Errorf("product_id of the products is required")
+		}
+
+		if productIDs[product.ProductID] {
+			return fmt.Errorf("product %d is in the file more than once", product.ProductID)
+		}
+		productIDs[product.ProductID] = true
+
+		if product.CategoryID == 0 || product.SellerID == 0 {
+			return fmt.Errorf("product %d must have a category_id and a seller_id", product.ProductID)
+		}
+
+		// the same rule as the check constraint of the products table, free products are allowed
+		if product.Price < 0 {
+			return fmt.Errorf("price of product %d cannot be negative", product.ProductID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package catalog
+
+import (
+	"checkoutProject/pkg/common/money"
+	. "github.com/smartystreets/goconvey/convey"
+	"strings"
+	"testing"
+)
+
+func TestParseProducts(t *testing.T) {
+	Convey("TEST unsupported content type", t, func() {
+		_, err := parseProducts("text/plain", strings.NewReader("product_id"))
+		So(err.Error(), ShouldEqual, `unsupported content type "text/plain", products can be imported from text/csv or application/json`)
+	})
+
+	Convey("TEST parses a CSV file with the columns in any order", t, func() {
+		file := "price,product_id,seller_id,category_id\n10.50,1,3,1001\n 200, 2, 4, 7889\n"
+
+		products, err := parseProducts(CSV_CONTENT_TYPE, strings.NewReader(file))
+		So(err, ShouldBeNil)
+		So(products, ShouldResemble, []Product{
+			{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: 1050},
+			{ProductID: 2, CategoryID: 7889, SellerID: 4, Price: 200 * money.Unit},
+		})
+	})
+
+	Convey("TEST empty CSV file", t, func() {
+		_, err := parseProducts(CSV_CONTENT_TYPE, strings.NewReader(""))
+		So(err.Error(), ShouldEqual, "file is empty")
+	})
+
+	Convey("TEST CSV file without a column", t, func() {
+		_, err := parseProducts(CSV_CONTENT_TYPE, strings.NewReader("product_id,category_id,price\n1,1001,10\n"))
+		So(err.Error(), ShouldEqual, "header does not have the seller_id column")
+	})
+
+	Convey("TEST CSV file with an invalid value", t, func() {
+		_, err := parseProducts(CSV_CONTENT_TYPE, strings.NewReader("product_id,category_id,seller_id,price\n1,1001,3,10\n2,abc,3,10\n"))
+		So(err.Error(), ShouldEqual, `line 3: invalid category_id "abc"`)
+
+		_, err = parseProducts(CSV_CONTENT_TYPE, strings.NewReader("product_id,category_id,seller_id,price\n1,1001,3,ten\n"))
+		So(err.Error(), ShouldEqual, `line 2: invalid price "ten"`)
+	})
+
+	Convey("TEST parses a JSON file", t, func() {
+		file := `[{"product_id": 1, "category_id": 1001, "seller_id": 3, "price": 10.5}]`
+
+		products, err := parseProducts(JSON_CONTENT_TYPE, strings.NewReader(file))
+		So(err, ShouldBeNil)
+		So(products, ShouldResemble, []Product{{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: 1050}})
+	})
+
+	Convey("TEST JSON file with an unknown field", t, func() {
+		_, err := parseProducts(JSON_CONTENT_TYPE, strings.NewReader(`[{"product_id": 1, "name": "tv"}]`))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestValidateProducts(t *testing.T) {
+	Convey("TEST file without products", t, func() {
+		err := validateProducts(nil)
+		So(err.Error(), ShouldEqual, "file does not have any products")
+	})
+
+	Convey("TEST product without product_id", t, func() {
+		err := validateProducts([]Product{{CategoryID: 1001, SellerID: 3, Price: money.Unit}})
+		So(err.Error(), ShouldEqual, "product_id of the products is required")
+	})
+
+	Convey("TEST same product more than once", t, func() {
+		err := validateProducts([]Product{
+			{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: money.Unit},
+			{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: 2 * money.Unit},
+		})
+		So(err.Error(), ShouldEqual, "product 1 is in the file more than once")
+	})
+
+	Convey("TEST product without category or seller", t, func() {
+		err := validateProducts([]Product{{ProductID: 1, SellerID: 3, Price: money.Unit}})
+		So(err.Error(), ShouldEqual, "product 1 must have a category_id and a seller_id")
+	})
+
+	Convey("TEST product with a negative price", t, func() {
+		err := validateProducts([]Product{{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: -1}})
+		So(err.Error(), ShouldEqual, "price of product 1 cannot be negative")
+	})
+
+	Convey("TEST free product", t, func() {
+		err := validateProducts([]Product{{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: 0}})
+		So(err, ShouldBeNil)
+	})
+
+	Convey("TEST valid products", t, func() {
+		err := validateProducts([]Product{
+			{ProductID: 1, CategoryID: 1001, SellerID: 3, Price: money.Unit},
+			{ProductID: 2, CategoryID: 7889, SellerID: 4, Price: 2 * money.Unit},
+		})
+		So(err, ShouldBeNil)
+	})
+}
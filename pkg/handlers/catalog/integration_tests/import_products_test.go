@@ -0,0 +1,151 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/catalog"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+type importProductsTest struct {
+	Name                    string
+	UserID                  uint
+	ContentType             string
+	File                    string
+	ExpectedImported        int
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+func TestImportProducts(t *testing.T) {
+	tests := []importProductsTest{
+		{
+			Name:                    "server should return 403 if the user is not an admin",
+			UserID:                  testhelper.TEST_USER_ID,
+			ContentType:             catalog.CSV_CONTENT_TYPE,
+			File:                    "product_id,category_id,seller_id,price\n1,1001,1,1\n",
+			ExpectedResponseMessage: "forbidden",
+			WantCode:                http.StatusForbidden,
+		},
+		{
+			Name:                    "server should return 400 if the content type is not supported",
+			UserID:                  TEST_ADMIN_USER_ID,
+			ContentType:             "text/plain",
+			File:                    "product_id,category_id,seller_id,price\n1,1001,1,1\n",
+			ExpectedResponseMessage: "unsupported content type \"text/plain\", products can be imported from text/csv or application/json",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "server should return 400 and import nothing if a product of the file is invalid",
+			UserID:                  TEST_ADMIN_USER_ID,
+			ContentType:             catalog.CSV_CONTENT_TYPE,
+			File:                    "product_id,category_id,seller_id,price\n1,1001,1,1\n3,1001,1,-0.01\n",
+			ExpectedResponseMessage: "price of product 3 cannot be negative",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:             "server should return 200 and import the products of a CSV file",
+			UserID:           TEST_ADMIN_USER_ID,
+			ContentType:      catalog.CSV_CONTENT_TYPE,
+			File:             "product_id,category_id,seller_id,price\n1,1001,1,12.5\n3,7889,56,10000\n5,7889,56,0\n",
+			ExpectedImported: 3,
+			WantCode:         http.StatusOK,
+		},
+		{
+			Name:             "server should return 200 and import the products of a JSON file",
+			UserID:           TEST_ADMIN_USER_ID,
+			ContentType:      catalog.JSON_CONTENT_TYPE,
+			File:             `[{"product_id": 2, "category_id": 3242, "seller_id": 5003, "price": 7.25}, {"product_id": 4, "category_id": 3242, "seller_id": 5003, "price": 3}]`,
+			ExpectedImported: 2,
+			WantCode:         http.StatusOK,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				POST("/api/admin/catalog/products/import").
+				SetHeader(testhelper.AuthorizationHeader(tt.UserID)).
+				SetHeader(gofight.H{"Content-Type": tt.ContentType}).
+				SetBody(tt.File).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client sends a request to import products", t, func() {
+				Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+					So(response.Code, ShouldEqual, tt.WantCode)
+				})
+
+				if response.Code != http.StatusOK {
+					var res apiresponse.GenericResponse
+					err := json.Unmarshal(response.Body.Bytes(), &res)
+					So(err, ShouldBeNil)
+
+					Convey("Then response should have Message field equal to expected result message", func() {
+						So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+					})
+					return
+				}
+
+				var res catalog.ImportProductsResponse
+				err := json.Unmarshal(response.Body.Bytes(), &res)
+				So(err, ShouldBeNil)
+
+				Convey("Then response should have the number of the imported products", func() {
+					So(res.Result, ShouldBeTrue)
+					So(res.Message.Imported, ShouldEqual, tt.ExpectedImported)
+				})
+			})
+		})
+	}
+
+	Convey("When the imports are done", t, func() {
+		Convey("Then the existing products must be updated instead of being created again", func() {
+			var count int64
+			err := TestDB.Table("products").Where("products.deleted_at IS NULL").Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 5)
+
+			err = TestDB.Table("products").Where("products.product_id = ? AND products.price = ?", 1, 12.5).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+
+			err = TestDB.Table("products").Where("products.product_id = ? AND products.price = ?", 2, 7.25).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Then a free product must be imported with the price 0", func() {
+			var count int64
+			err := TestDB.Table("products").Where("products.product_id = ? AND products.price = ?", 5, 0).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("Then the products of the invalid file must not be imported", func() {
+			var count int64
+			err := TestDB.Table("products").Where("products.product_id = ? AND products.price = ?", 1, 1).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/env"
+	"gorm.io/gorm"
+	"log"
+	"os"
+	"testing"
+)
+
+// TEST_ADMIN_USER_ID can use the admin endpoints in the tests.
+const TEST_ADMIN_USER_ID = 9
+
+var TestDB *gorm.DB
+
+func TestMain(m *testing.M) {
+	err := bootstrap.Initialize()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	env.ADMIN_USER_IDS = []uint{TEST_ADMIN_USER_ID}
+
+	TestDB = database.GetInstance()
+	os.Exit(m.Run())
+}
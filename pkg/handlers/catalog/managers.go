@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductManager interface {
+	Get(filter ProductFilter) (Product, error)
+	Find(filter ProductFilter) ([]Product, error)
+	Import(products []Product) error
+	WithTx(tx *gorm.DB) ProductManager
+	WithContext(ctx context.Context) ProductManager
+}
+
+type productManager struct {
+	db.BaseManager
+}
+
+func NewDefaultProductManager() ProductManager {
+	return NewProductManager(db.GetInstance())
+}
+
+func NewProductManager(withDB *gorm.DB) ProductManager {
+	return productManager{
+		BaseManager: db.NewBaseManager(withDB),
+	}
+}
+
+func (m productManager) WithTx(tx *gorm.DB) ProductManager {
+	return productManager{
+		BaseManager: m.BaseManager.WithTx(tx),
+	}
+}
+
+func (m productManager) WithContext(ctx context.Context) ProductManager {
+	return productManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
+func (m productManager) Get(filter ProductFilter) (Product, error) {
+	var product Product
+
+	if err := filter.ToQuery(m.DB).First(&product).Error; err != nil {
+		return Product{}, err
+	}
+
+	return product, nil
+}
+
+func (m productManager) Find(filter ProductFilter) ([]Product, error) {
+	var products []Product
+
+	if err := filter.ToQuery(m.DB).Order("products.product_id").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+// Import creates the products, the products already in the catalog are updated with the new price, category and seller.
+func (m productManager) Import(products []Product) error {
+	query := m.DB.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "product_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "deleted_at IS NULL"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"updated_at", "category_id", "seller_id", "price"}),
+	})
+
+	if err := query.CreateInBatches(&products, IMPORT_BATCH_SIZE).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ProductLookup finds the products of the catalog, it returns gorm.ErrRecordNotFound for the products not in it.
+type ProductLookup interface {
+	LookupProduct(ctx context.Context, productID uint) (Product, error)
+	// LookupProducts finds the products in one query, the products not in the catalog are missing from the map.
+	LookupProducts(ctx context.Context, productIDs []uint) (map[uint]Product, error)
+}
+
+type productLookup struct {
+	productManager ProductManager
+}
+
+func NewDefaultProductLookup() ProductLookup {
+	return NewProductLookup(NewDefaultProductManager())
+}
+
+func NewProductLookup(productManager ProductManager) ProductLookup {
+	return productLookup{productManager: productManager}
+}
+
+func (l productLookup) LookupProduct(ctx context.Context, productID uint) (Product, error) {
+	return l.productManager.WithContext(ctx).Get(ProductFilter{ProductID: productID})
+}
+
+func (l productLookup) LookupProducts(ctx context.Context, productIDs []uint) (map[uint]Product, error) {
+	productsByID := make(map[uint]Product)
+	if len(productIDs) == 0 {
+		return productsByID, nil
+	}
+
+	products, err := l.productManager.WithContext(ctx).Find(ProductFilter{ProductIDs: productIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		productsByID[product.ProductID] = product
+	}
+
+	return productsByID, nil
+}
@@ -0,0 +1,55 @@
+package catalog
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type mockProductManagerImpl struct {
+	MGet         func(filter ProductFilter) (Product, error)
+	MFind        func(filter ProductFilter) ([]Product, error)
+	MImport      func(products []Product) error
+	MWithTx      func(tx *gorm.DB) ProductManager
+	MWithContext func(ctx context.Context) ProductManager
+}
+
+func NewMockProductManager() mockProductManagerImpl {
+	return mockProductManagerImpl{}
+}
+
+func (m mockProductManagerImpl) Get(filter ProductFilter) (Product, error) {
+	return m.MGet(filter)
+}
+
+func (m mockProductManagerImpl) Find(filter ProductFilter) ([]Product, error) {
+	return m.MFind(filter)
+}
+
+func (m mockProductManagerImpl) Import(products []Product) error {
+	return m.MImport(products)
+}
+
+func (m mockProductManagerImpl) WithTx(tx *gorm.DB) ProductManager {
+	return m.MWithTx(tx)
+}
+
+func (m mockProductManagerImpl) WithContext(ctx context.Context) ProductManager {
+	return m.MWithContext(ctx)
+}
+
+type mockProductLookupImpl struct {
+	MLookupProduct  func(ctx context.Context, productID uint) (Product, error)
+	MLookupProducts func(ctx context.Context, productIDs []uint) (map[uint]Product, error)
+}
+
+func NewMockProductLookup() mockProductLookupImpl {
+	return mockProductLookupImpl{}
+}
+
+func (m mockProductLookupImpl) LookupProduct(ctx context.Context, productID uint) (Product, error) {
+	return m.MLookupProduct(ctx, productID)
+}
+
+func (m mockProductLookupImpl) LookupProducts(ctx context.Context, productIDs []uint) (map[uint]Product, error) {
+	return m.MLookupProducts(ctx, productIDs)
+}
@@ -0,0 +1,16 @@
+package catalog
+
+import (
+	"checkoutProject/pkg/common/money"
+	"gorm.io/gorm"
+)
+
+// Product is an item or a vas-item that can be added to the carts, the lines of the carts take their price, category
+// and seller from it. Items and vas-items share the product IDs.
+type Product struct {
+	gorm.Model
+	ProductID  uint
+	CategoryID uint
+	SellerID   uint
+	Price      money.Amount
+}
@@ -0,0 +1,20 @@
+package catalog
+
+import (
+	"checkoutProject/pkg/common/money"
+	"io"
+)
+
+// ImportProductsParams is an uploaded file of products, ContentType is the format of the file.
+type ImportProductsParams struct {
+	ContentType string
+	File        io.Reader
+}
+
+// ProductParams is a product of an imported JSON file.
+type ProductParams struct {
+	ProductID  uint         `json:"product_id"`
+	CategoryID uint         `json:"category_id"`
+	SellerID   uint         `json:"seller_id"`
+	Price      money.Amount `json:"price"`
+}
@@ -0,0 +1,47 @@
+package catalog
+
+import (
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/routing"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type CatalogRouter interface {
+	routing.Registerer
+}
+
+type catalogRouter struct {
+	catalogController CatalogController
+}
+
+func NewCatalogRouter(catalogController CatalogController) CatalogRouter {
+	return catalogRouter{catalogController: catalogController}
+}
+
+func NewDefaultCatalogRouter() CatalogRouter {
+	return NewCatalogRouter(NewDefaultCatalogController())
+}
+
+func (ctr catalogRouter) Register(group *gin.RouterGroup) {
+	catalogGroup := group.Group("catalog")
+	catalogGroup.POST("products/import", ctr.ImportProductsRoute)
+}
+
+// ImportProductsRoute reads the file from the body of the request, the Content-Type header is the format of the file.
+func (ctr catalogRouter) ImportProductsRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	params := ImportProductsParams{
+		ContentType: c.ContentType(),
+		File:        http.MaxBytesReader(c.Writer, c.Request.Body, MAX_IMPORT_SIZE),
+	}
+
+	responder, err := ctr.catalogController.ImportProducts(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+
+	c.JSON(apiresponse.OK(responder))
+}
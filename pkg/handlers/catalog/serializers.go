@@ -0,0 +1,23 @@
+package catalog
+
+type ImportProductsResponse struct {
+	Result  bool                          `json:"result"`
+	Message ImportProductsMessageResponse `json:"message"`
+}
+
+// ImportProductsMessageResponse has the number of the products created or updated by the import.
+type ImportProductsMessageResponse struct {
+	Imported int `json:"imported"`
+}
+
+type ImportProductsSerializer struct {
+	Result   bool
+	Imported int
+}
+
+func (s ImportProductsSerializer) Response() interface{} {
+	return ImportProductsResponse{
+		Result:  s.Result,
+		Message: ImportProductsMessageResponse{Imported: s.Imported},
+	}
+}
@@ -0,0 +1,6 @@
+package health
+
+const (
+	STATUS_OK   = "ok"
+	STATUS_FAIL = "fail"
+)
@@ -0,0 +1,63 @@
+package health
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"github.com/sirupsen/logrus"
+)
+
+type HealthController interface {
+	Liveness() HealthSerializer
+	Readiness(ctx context.Context) HealthSerializer
+}
+
+type healthController struct {
+	healthManager HealthManager
+}
+
+func NewHealthController(healthManager HealthManager) HealthController {
+	return healthController{
+		healthManager: healthManager,
+	}
+}
+
+func NewDefaultHealthController() HealthController {
+	return NewHealthController(NewDefaultHealthManager())
+}
+
+func (c healthController) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
+	return l.WithFields(logrus.Fields{"api_version": "1", "controller": "health"})
+}
+
+// Liveness only shows that the process serves requests, it does not check the dependencies.
+func (c healthController) Liveness() HealthSerializer {
+	return HealthSerializer{}
+}
+
+func (c healthController) Readiness(ctx context.Context) HealthSerializer {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Readiness",
+	})
+
+	healthManager := c.healthManager.WithContext(ctx)
+
+	checks := map[string]CheckSerializer{
+		"database": databaseCheck(healthManager, log),
+	}
+
+	// the migration version cannot be read while the database is down
+	if checks["database"].Status == STATUS_OK {
+		expectedVersion, err := db.LatestMigrationVersion()
+		if err != nil {
+			log.WithError(err).Error("error while reading the embedded migrations")
+			checks["migrations"] = CheckSerializer{Status: STATUS_FAIL, Message: "cannot read the embedded migrations"}
+		} else {
+			checks["migrations"] = migrationsCheck(healthManager, log, expectedVersion)
+		}
+	} else {
+		checks["migrations"] = CheckSerializer{Status: STATUS_FAIL, Message: "database is not reachable"}
+	}
+
+	return HealthSerializer{Checks: checks}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+)
+
+func databaseCheck(healthManager HealthManager, log *logrus.Entry) CheckSerializer {
+	err := healthManager.Ping()
+	if err != nil {
+		log.WithError(err).Error("error while pinging the database")
+		return CheckSerializer{Status: STATUS_FAIL, Message: "cannot reach the database"}
+	}
+
+	return CheckSerializer{Status: STATUS_OK}
+}
+
+func migrationsCheck(healthManager HealthManager, log *logrus.Entry, expectedVersion uint) CheckSerializer {
+	version, dirty, err := healthManager.GetMigrationVersion()
+	if err != nil {
+		log.WithError(err).Error("error while querying the migration version")
+		return CheckSerializer{Status: STATUS_FAIL, Message: "cannot read the migration version"}
+	}
+
+	if dirty {
+		log.Errorf("error, migration %d of the database is dirty", version)
+		return CheckSerializer{Status: STATUS_FAIL, Message: fmt.Sprintf("migration %d failed, database is dirty", version)}
+	}
+
+	if version != expectedVersion {
+		log.Errorf("error, database is at migration %d, expected %d", version, expectedVersion)
+		return CheckSerializer{Status: STATUS_FAIL, Message: fmt.Sprintf("database is at migration %d, expected %d", version, expectedVersion)}
+	}
+
+	return CheckSerializer{Status: STATUS_OK, Message: fmt.Sprintf("database is at migration %d", version)}
+}
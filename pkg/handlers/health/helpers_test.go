@@ -0,0 +1,98 @@
+package health
+
+import (
+	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/logger"
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDatabaseCheck(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockHealthManager := NewMockHealthManager()
+
+	Convey("TEST healthManager.Ping fail", t, func() {
+		mockHealthManager.MPing = func() error {
+			return errs.InternalServerErr
+		}
+
+		check := databaseCheck(mockHealthManager, log.WithFields(logrus.Fields{}))
+		So(check, ShouldResemble, CheckSerializer{Status: STATUS_FAIL, Message: "cannot reach the database"})
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockHealthManager.MPing = func() error {
+			return nil
+		}
+
+		check := databaseCheck(mockHealthManager, log.WithFields(logrus.Fields{}))
+		So(check, ShouldResemble, CheckSerializer{Status: STATUS_OK})
+	})
+}
+
+func TestMigrationsCheck(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockHealthManager := NewMockHealthManager()
+
+	Convey("TEST healthManager.GetMigrationVersion fail", t, func() {
+		mockHealthManager.MGetMigrationVersion = func() (uint, bool, error) {
+			return 0, false, errs.InternalServerErr
+		}
+
+		check := migrationsCheck(mockHealthManager, log.WithFields(logrus.Fields{}), 7)
+		So(check, ShouldResemble, CheckSerializer{Status: STATUS_FAIL, Message: "cannot read the migration version"})
+	})
+
+	Convey("TEST dirty database error", t, func() {
+		mockHealthManager.MGetMigrationVersion = func() (uint, bool, error) {
+			return 7, true, nil
+		}
+
+		check := migrationsCheck(mockHealthManager, log.WithFields(logrus.Fields{}), 7)
+		So(check, ShouldResemble, CheckSerializer{Status: STATUS_FAIL, Message: "migration 7 failed, database is dirty"})
+	})
+
+	Convey("TEST version mismatch error", t, func() {
+		mockHealthManager.MGetMigrationVersion = func() (uint, bool, error) {
+			return 6, false, nil
+		}
+
+		check := migrationsCheck(mockHealthManager, log.WithFields(logrus.Fields{}), 7)
+		So(check, ShouldResemble, CheckSerializer{Status: STATUS_FAIL, Message: "database is at migration 6, expected 7"})
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockHealthManager.MGetMigrationVersion = func() (uint, bool, error) {
+			return 7, false, nil
+		}
+
+		check := migrationsCheck(mockHealthManager, log.WithFields(logrus.Fields{}), 7)
+		So(check, ShouldResemble, CheckSerializer{Status: STATUS_OK, Message: "database is at migration 7"})
+	})
+}
+
+func TestHealthSerializer(t *testing.T) {
+	Convey("TEST response fails when a check fails", t, func() {
+		serializer := HealthSerializer{Checks: map[string]CheckSerializer{
+			"database":   {Status: STATUS_OK},
+			"migrations": {Status: STATUS_FAIL, Message: "database is at migration 6, expected 7"},
+		}}
+
+		So(serializer.IsHealthy(), ShouldBeFalse)
+		So(serializer.Response(), ShouldResemble, HealthResponse{Status: STATUS_FAIL, Checks: map[string]CheckResponse{
+			"database":   {Status: STATUS_OK},
+			"migrations": {Status: STATUS_FAIL, Message: "database is at migration 6, expected 7"},
+		}})
+	})
+
+	Convey("TEST response without checks is ok", t, func() {
+		So(HealthSerializer{}.Response(), ShouldResemble, HealthResponse{Status: STATUS_OK})
+	})
+}
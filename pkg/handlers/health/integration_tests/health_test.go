@@ -0,0 +1,72 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/handlers/health"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+type healthTest struct {
+	Name             string
+	Path             string
+	ExpectedResponse health.HealthResponse
+	WantCode         int
+}
+
+func TestHealth(t *testing.T) {
+	expectedVersion, err := database.LatestMigrationVersion()
+	if err != nil {
+		t.Fatalf("error while reading the migrations: %v", err)
+		return
+	}
+
+	tests := []healthTest{
+		{
+			Name:             "Server should return 200 while it is alive.",
+			Path:             "/healthz",
+			ExpectedResponse: health.HealthResponse{Status: health.STATUS_OK},
+			WantCode:         http.StatusOK,
+		},
+		{
+			Name: "Server should return 200 and the status of the dependencies when it is ready.",
+			Path: "/readyz",
+			ExpectedResponse: health.HealthResponse{Status: health.STATUS_OK, Checks: map[string]health.CheckResponse{
+				"database":   {Status: health.STATUS_OK},
+				"migrations": {Status: health.STATUS_OK, Message: fmt.Sprintf("database is at migration %d", expectedVersion)},
+			}},
+			WantCode: http.StatusOK,
+		},
+	}
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				GET(tt.Path).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client checks the health of the server", t, func() {
+				Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+					So(response.Code, ShouldEqual, tt.WantCode)
+				})
+				Convey("Then server should return correct response", func() {
+					expectedResponseBytes, err := json.Marshal(tt.ExpectedResponse)
+					So(err, ShouldBeNil)
+
+					So(response.Body.String(), ShouldEqual, string(expectedResponseBytes))
+				})
+			})
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	err := bootstrap.Initialize()
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	os.Exit(m.Run())
+}
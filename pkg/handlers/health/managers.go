@@ -0,0 +1,52 @@
+package health
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+const PING_TIMEOUT = 2 * time.Second
+
+type HealthManager interface {
+	WithContext(ctx context.Context) HealthManager
+	Ping() error
+	GetMigrationVersion() (uint, bool, error)
+}
+
+type healthManager struct {
+	db.BaseManager
+}
+
+func NewDefaultHealthManager() HealthManager {
+	return NewHealthManager(db.GetInstance())
+}
+
+func NewHealthManager(withDB *gorm.DB) HealthManager {
+	return healthManager{
+		BaseManager: db.NewBaseManager(withDB),
+	}
+}
+
+func (m healthManager) WithContext(ctx context.Context) HealthManager {
+	return healthManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
+func (m healthManager) Ping() error {
+	sqlDB, err := m.DB.DB()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(m.DB.Statement.Context, PING_TIMEOUT)
+	defer cancel()
+
+	return sqlDB.PingContext(ctx)
+}
+
+func (m healthManager) GetMigrationVersion() (uint, bool, error) {
+	return db.GetMigrationVersion(m.DB)
+}
@@ -0,0 +1,25 @@
+package health
+
+import "context"
+
+type mockHealthManagerImpl struct {
+	MWithContext         func(ctx context.Context) HealthManager
+	MPing                func() error
+	MGetMigrationVersion func() (uint, bool, error)
+}
+
+func NewMockHealthManager() mockHealthManagerImpl {
+	return mockHealthManagerImpl{}
+}
+
+func (m mockHealthManagerImpl) WithContext(ctx context.Context) HealthManager {
+	return m.MWithContext(ctx)
+}
+
+func (m mockHealthManagerImpl) Ping() error {
+	return m.MPing()
+}
+
+func (m mockHealthManagerImpl) GetMigrationVersion() (uint, bool, error) {
+	return m.MGetMigrationVersion()
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"checkoutProject/pkg/common/routing"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type HealthRouter interface {
+	routing.Registerer
+}
+
+type healthRouter struct {
+	healthController HealthController
+}
+
+func NewHealthRouter(healthController HealthController) HealthRouter {
+	return healthRouter{healthController: healthController}
+}
+
+func NewDefaultHealthRouter() HealthRouter {
+	return NewHealthRouter(NewDefaultHealthController())
+}
+
+func (htr healthRouter) Register(group *gin.RouterGroup) {
+	group.GET("healthz", htr.LivenessRoute)
+	group.GET("readyz", htr.ReadinessRoute)
+}
+
+func (htr healthRouter) LivenessRoute(c *gin.Context) {
+	c.JSON(http.StatusOK, htr.healthController.Liveness().Response())
+}
+
+func (htr healthRouter) ReadinessRoute(c *gin.Context) {
+	serializer := htr.healthController.Readiness(c.Request.Context())
+	if !serializer.IsHealthy() {
+		c.JSON(http.StatusServiceUnavailable, serializer.Response())
+		return
+	}
+	c.JSON(http.StatusOK, serializer.Response())
+}
@@ -0,0 +1,52 @@
+package health
+
+type HealthResponse struct {
+	Status string                   `json:"status"`
+	Checks map[string]CheckResponse `json:"checks,omitempty"`
+}
+
+type CheckResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type HealthSerializer struct {
+	Checks map[string]CheckSerializer
+}
+
+// IsHealthy is true when every check of the response is ok.
+func (s HealthSerializer) IsHealthy() bool {
+	for _, check := range s.Checks {
+		if check.Status != STATUS_OK {
+			return false
+		}
+	}
+	return true
+}
+
+func (s HealthSerializer) Response() interface{} {
+	response := HealthResponse{Status: STATUS_OK}
+	if !s.IsHealthy() {
+		response.Status = STATUS_FAIL
+	}
+
+	if len(s.Checks) > 0 {
+		response.Checks = make(map[string]CheckResponse)
+		for name, check := range s.Checks {
+			response.Checks[name] = check.Response().(CheckResponse)
+		}
+	}
+	return response
+}
+
+type CheckSerializer struct {
+	Status  string
+	Message string
+}
+
+func (s CheckSerializer) Response() interface{} {
+	return CheckResponse{
+		Status:  s.Status,
+		Message: s.Message,
+	}
+}
@@ -0,0 +1,8 @@
+package idempotency
+
+const (
+	IDEMPOTENCY_KEY_HEADER     = "Idempotency-Key"
+	IDEMPOTENT_REPLAYED_HEADER = "Idempotent-Replayed"
+	MAX_KEY_LENGTH             = 255
+	JSON_CONTENT_TYPE          = "application/json; charset=utf-8"
+)
@@ -0,0 +1,31 @@
+package idempotency
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type IdempotencyKeyFilter struct {
+	ID           uint
+	UserID       uint
+	GuestSession string
+	Key          string
+	ExpiredAt    time.Time
+}
+
+func (f IdempotencyKeyFilter) ToQuery(q *gorm.DB) *gorm.DB {
+	q = q.Where(IdempotencyKey{
+		Model: gorm.Model{ID: f.ID},
+	})
+
+	// keys are unique per user and guest session, guests share the user ID 0 and users have no guest session
+	if f.Key != "" {
+		q = q.Where("idempotency_keys.user_id = ? AND idempotency_keys.guest_session = ? AND idempotency_keys.key = ?", f.UserID, f.GuestSession, f.Key)
+	}
+
+	if !f.ExpiredAt.IsZero() {
+		q = q.Where("idempotency_keys.expires_at <= ?", f.ExpiredAt)
+	}
+
+	return q
+}
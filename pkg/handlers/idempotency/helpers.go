@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"checkoutProject/pkg/common/apiresponse"
+	errs "checkoutProject/pkg/common/errors"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+var (
+	KeyReusedErr     = errors.New("idempotency key was already used with a different request")
+	KeyInProgressErr = errors.New("a request with the same idempotency key is still being processed")
+)
+
+// hashRequest identifies the request a key is used for, a key sent again with another path or body is rejected.
+func hashRequest(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// reserveKey reserves the key for the request and returns false, when the same request was already handled with the
+// key it returns the stored response and true instead. The keys of the guests are kept per guest session.
+func reserveKey(manager IdempotencyKeyManager, log *logrus.Entry, userID uint, guestSession string, key string, requestHash string, now time.Time, ttl time.Duration) (IdempotencyKey, bool, error) {
+	err := manager.Delete(IdempotencyKeyFilter{ExpiredAt: now})
+	if err != nil {
+		log.WithError(err).Error("error while deleting the expired idempotency keys")
+		return IdempotencyKey{}, false, errs.InternalServerErr
+	}
+
+	isCreated, err := manager.Create(IdempotencyKey{UserID: userID, GuestSession: guestSession, Key: key, RequestHash: requestHash, ExpiresAt: now.Add(ttl)})
+	if err != nil {
+		log.WithError(err).Error("error while creating the idempotency key")
+		return IdempotencyKey{}, false, errs.InternalServerErr
+	}
+
+	if isCreated {
+		return IdempotencyKey{}, false, nil
+	}
+
+	storedKey, err := manager.Get(IdempotencyKeyFilter{UserID: userID, GuestSession: guestSession, Key: key})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// the other request failed and released the key after our insert, the client can retry right away
+		log.Error("idempotency key was released while it was being reserved")
+		return IdempotencyKey{}, false, KeyInProgressErr
+	}
+	if err != nil {
+		log.WithError(err).Error("error while getting the idempotency key")
+		return IdempotencyKey{}, false, errs.InternalServerErr
+	}
+
+	if storedKey.RequestHash != requestHash {
+		log.Error("idempotency key was already used with a different request")
+		return IdempotencyKey{}, false, KeyReusedErr
+	}
+
+	if !storedKey.isCompleted() {
+		log.Error("request with the same idempotency key is still being processed")
+		return IdempotencyKey{}, false, KeyInProgressErr
+	}
+
+	return storedKey, true, nil
+}
+
+// releaseKey deletes the key of a request that could not be handled, so the client can retry it with the same key.
+func releaseKey(manager IdempotencyKeyManager, log *logrus.Entry, userID uint, guestSession string, key string) {
+	err := manager.Delete(IdempotencyKeyFilter{UserID: userID, GuestSession: guestSession, Key: key})
+	if err != nil {
+		log.WithError(err).Error("error while releasing the idempotency key")
+	}
+}
+
+func failed(err error) (int, interface{}) {
+	responseCode, response := apiresponse.Failed(err)
+
+	if errors.Is(err, KeyReusedErr) {
+		responseCode = http.StatusUnprocessableEntity
+	}
+
+	if errors.Is(err, KeyInProgressErr) {
+		responseCode = http.StatusConflict
+	}
+
+	return responseCode, response
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package idempotency
+
+import (
+	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/logger"
+	"github.com/sirupsen/logrus"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestReserveKey(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	now := time.Now()
+	requestHash := hashRequest(http.MethodPost, "/api/carts/1/items", []byte(`{"item_id":1}`))
+
+	mockIdempotencyKeyManager := NewMockIdempotencyKeyManager()
+	mockIdempotencyKeyManager.MDelete = func(filter IdempotencyKeyFilter) error {
+		return nil
+	}
+
+	Convey("TEST idempotencyKeyManager.Delete fail", t, func() {
+		mockIdempotencyKeyManager := mockIdempotencyKeyManager
+		mockIdempotencyKeyManager.MDelete = func(filter IdempotencyKeyFilter) error {
+			return errs.InternalServerErr
+		}
+
+		_, _, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST idempotencyKeyManager.Create fail", t, func() {
+		mockIdempotencyKeyManager.MCreate = func(key IdempotencyKey) (bool, error) {
+			return false, errs.InternalServerErr
+		}
+
+		_, _, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST new key is reserved until it expires", t, func() {
+		var createdKey IdempotencyKey
+		mockIdempotencyKeyManager.MCreate = func(key IdempotencyKey) (bool, error) {
+			createdKey = key
+			return true, nil
+		}
+
+		_, isHandled, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldBeNil)
+		So(isHandled, ShouldBeFalse)
+		So(createdKey, ShouldResemble, IdempotencyKey{UserID: 1, Key: "key", RequestHash: requestHash, ExpiresAt: now.Add(time.Hour)})
+	})
+
+	Convey("TEST key of a guest is reserved in its guest session", t, func() {
+		var createdKey IdempotencyKey
+		mockIdempotencyKeyManager.MCreate = func(key IdempotencyKey) (bool, error) {
+			createdKey = key
+			return true, nil
+		}
+
+		_, isHandled, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 0, "session", "key", requestHash, now, time.Hour)
+		So(err, ShouldBeNil)
+		So(isHandled, ShouldBeFalse)
+		So(createdKey, ShouldResemble, IdempotencyKey{GuestSession: "session", Key: "key", RequestHash: requestHash, ExpiresAt: now.Add(time.Hour)})
+	})
+
+	mockIdempotencyKeyManager.MCreate = func(key IdempotencyKey) (bool, error) {
+		return false, nil
+	}
+
+	Convey("TEST idempotencyKeyManager.Get fail", t, func() {
+		mockIdempotencyKeyManager.MGet = func(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+			return IdempotencyKey{}, errs.InternalServerErr
+		}
+
+		_, _, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST key released while reserving error", t, func() {
+		mockIdempotencyKeyManager.MGet = func(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+			return IdempotencyKey{}, gorm.ErrRecordNotFound
+		}
+
+		_, _, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldEqual, KeyInProgressErr)
+	})
+
+	Convey("TEST key reused with a different request error", t, func() {
+		mockIdempotencyKeyManager.MGet = func(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+			return IdempotencyKey{Key: "key", RequestHash: hashRequest(http.MethodPost, "/api/carts/1/items", []byte(`{"item_id":2}`))}, nil
+		}
+
+		_, _, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldEqual, KeyReusedErr)
+	})
+
+	Convey("TEST request with the same key in progress error", t, func() {
+		mockIdempotencyKeyManager.MGet = func(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+			return IdempotencyKey{Key: "key", RequestHash: requestHash}, nil
+		}
+
+		_, _, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldEqual, KeyInProgressErr)
+	})
+
+	Convey("TEST stored response is returned for a handled request", t, func() {
+		storedKey := IdempotencyKey{Key: "key", RequestHash: requestHash, StatusCode: http.StatusCreated, ResponseBody: `{"result":true}`}
+		mockIdempotencyKeyManager.MGet = func(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+			return storedKey, nil
+		}
+
+		key, isHandled, err := reserveKey(mockIdempotencyKeyManager, log.WithFields(logrus.Fields{}), 1, "", "key", requestHash, now, time.Hour)
+		So(err, ShouldBeNil)
+		So(isHandled, ShouldBeTrue)
+		So(key, ShouldResemble, storedKey)
+	})
+}
+
+func TestHashRequest(t *testing.T) {
+	Convey("TEST same request has the same hash", t, func() {
+		So(hashRequest(http.MethodPost, "/api/carts/1/items", []byte(`{}`)), ShouldEqual, hashRequest(http.MethodPost, "/api/carts/1/items", []byte(`{}`)))
+	})
+
+	Convey("TEST path and body change the hash", t, func() {
+		hash := hashRequest(http.MethodPost, "/api/carts/1/items", []byte(`{}`))
+
+		So(hashRequest(http.MethodPost, "/api/carts/2/items", []byte(`{}`)), ShouldNotEqual, hash)
+		So(hashRequest(http.MethodPost, "/api/carts/1/items", []byte(`{"item_id":1}`)), ShouldNotEqual, hash)
+	})
+}
@@ -0,0 +1,88 @@
+package idempotency
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyManager interface {
+	WithContext(ctx context.Context) IdempotencyKeyManager
+	Create(key IdempotencyKey) (bool, error)
+	Get(filter IdempotencyKeyFilter) (IdempotencyKey, error)
+	SaveResponse(filter IdempotencyKeyFilter, statusCode int, responseBody string) error
+	Delete(filter IdempotencyKeyFilter) error
+}
+
+type idempotencyKeyManager struct {
+	db.BaseManager
+}
+
+func NewDefaultIdempotencyKeyManager() IdempotencyKeyManager {
+	return NewIdempotencyKeyManager(db.GetInstance())
+}
+
+func NewIdempotencyKeyManager(withDB *gorm.DB) IdempotencyKeyManager {
+	return idempotencyKeyManager{
+		BaseManager: db.NewBaseManager(withDB),
+	}
+}
+
+func (m idempotencyKeyManager) WithContext(ctx context.Context) IdempotencyKeyManager {
+	return idempotencyKeyManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
+// Create returns false without an error when the key is already reserved by another request.
+func (m idempotencyKeyManager) Create(key IdempotencyKey) (bool, error) {
+	query := m.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "guest_session"}, {Name: "key"}},
+		DoNothing: true,
+	}).Create(&key)
+
+	if err := query.Error; err != nil {
+		return false, err
+	}
+
+	return query.RowsAffected > 0, nil
+}
+
+func (m idempotencyKeyManager) Get(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+	var key IdempotencyKey
+	query := filter.ToQuery(m.DB)
+
+	if err := query.Model(&IdempotencyKey{}).First(&key).Error; err != nil {
+		return IdempotencyKey{}, err
+	}
+
+	return key, nil
+}
+
+func (m idempotencyKeyManager) SaveResponse(filter IdempotencyKeyFilter, statusCode int, responseBody string) error {
+
+	query := filter.ToQuery(m.DB)
+
+	err := query.Model(&IdempotencyKey{}).Updates(map[string]interface{}{
+		"status_code":   statusCode,
+		"response_body": responseBody,
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes the keys for good, the unique index on the key column does not ignore soft deleted rows.
+func (m idempotencyKeyManager) Delete(filter IdempotencyKeyFilter) error {
+
+	query := filter.ToQuery(m.DB)
+
+	if err := query.Unscoped().Delete(&IdempotencyKey{}).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package idempotency
+
+import (
+	"bytes"
+	"checkoutProject/pkg/common/auth"
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net/http"
+	"time"
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func NewDefaultMiddleware() gin.HandlerFunc {
+	return NewMiddleware(NewDefaultIdempotencyKeyManager(), env.SERVER.IdempotencyKeyTTL)
+}
+
+// NewMiddleware stores the response of a mutating request sent with an Idempotency-Key header and replays it when the
+// request is retried with the same key until the key expires. Responses with a 5xx status are not stored, the key is
+// released instead so the retry runs the request again.
+func NewMiddleware(manager IdempotencyKeyManager, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IDEMPOTENCY_KEY_HEADER)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		log := logger.FromContext(ctx).WithField("middleware", "idempotency")
+
+		if len(key) > MAX_KEY_LENGTH {
+			log.Error("idempotency key is too long")
+			c.AbortWithStatusJSON(failed(fmt.Errorf("idempotency key cannot be longer than %d characters", MAX_KEY_LENGTH)))
+			return
+		}
+		log = log.WithField("idempotency_key", key)
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			log.WithError(err).Error("error while reading the request body")
+			c.AbortWithStatusJSON(failed(fmt.Errorf("cannot read the request body")))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// keys are kept per user and per guest session, the middleware runs after auth.Middleware. A guest retries with
+		// the guest token of the first response, so the retry is in the same guest session
+		userID, _ := auth.UserIDFromContext(ctx)
+
+		var guestSession string
+		if userID == auth.GUEST_USER_ID {
+			guestSession, _ = auth.GuestSessionFromContext(ctx)
+		}
+
+		requestHash := hashRequest(c.Request.Method, c.Request.URL.Path, body)
+		storedKey, isHandled, err := reserveKey(manager.WithContext(ctx), log, userID, guestSession, key, requestHash, time.Now(), ttl)
+		if err != nil {
+			c.AbortWithStatusJSON(failed(err))
+			return
+		}
+
+		if isHandled {
+			log.Info("replaying the stored response of the idempotency key")
+			c.Header(IDEMPOTENT_REPLAYED_HEADER, "true")
+			c.Data(storedKey.StatusCode, JSON_CONTENT_TYPE, []byte(storedKey.ResponseBody))
+			c.Abort()
+			return
+		}
+
+		// the key is completed or released even if the request timed out, otherwise it would stay reserved until it expires
+		keyManager := manager.WithContext(context.WithoutCancel(ctx))
+		isCompleted := false
+		defer func() {
+			if !isCompleted {
+				releaseKey(keyManager, log, userID, guestSession, key)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		err = keyManager.SaveResponse(IdempotencyKeyFilter{UserID: userID, GuestSession: guestSession, Key: key}, status, recorder.body.String())
+		if err != nil {
+			log.WithError(err).WithFields(logrus.Fields{"status": status}).Error("error while saving the response of the idempotency key")
+			return
+		}
+		isCompleted = true
+	}
+}
@@ -0,0 +1,195 @@
+package idempotency
+
+import (
+	"checkoutProject/pkg/common/auth"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newInMemoryManager keeps the keys in a map, it does not expire them.
+func newInMemoryManager() mockIdempotencyKeyManagerImpl {
+	var mu sync.Mutex
+	keys := make(map[string]IdempotencyKey)
+	id := func(userID uint, guestSession string, key string) string {
+		return fmt.Sprintf("%d/%s/%s", userID, guestSession, key)
+	}
+
+	manager := NewMockIdempotencyKeyManager()
+	manager.MCreate = func(key IdempotencyKey) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if _, ok := keys[id(key.UserID, key.GuestSession, key.Key)]; ok {
+			return false, nil
+		}
+		keys[id(key.UserID, key.GuestSession, key.Key)] = key
+		return true, nil
+	}
+	manager.MGet = func(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key, ok := keys[id(filter.UserID, filter.GuestSession, filter.Key)]
+		if !ok {
+			return IdempotencyKey{}, gorm.ErrRecordNotFound
+		}
+		return key, nil
+	}
+	manager.MSaveResponse = func(filter IdempotencyKeyFilter, statusCode int, responseBody string) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		key := keys[id(filter.UserID, filter.GuestSession, filter.Key)]
+		key.StatusCode = statusCode
+		key.ResponseBody = responseBody
+		keys[id(filter.UserID, filter.GuestSession, filter.Key)] = key
+		return nil
+	}
+	manager.MDelete = func(filter IdempotencyKeyFilter) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		delete(keys, id(filter.UserID, filter.GuestSession, filter.Key))
+		return nil
+	}
+	manager.MWithContext = func(ctx context.Context) IdempotencyKeyManager {
+		return manager
+	}
+	return manager
+}
+
+func TestMiddleware(t *testing.T) {
+	_, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		userID, _ := strconv.ParseUint(c.GetHeader("X-User-ID"), 10, 0)
+		ctx := auth.NewContext(c.Request.Context(), uint(userID))
+		if guestToken := c.GetHeader(auth.GUEST_TOKEN_HEADER); guestToken != "" {
+			ctx = auth.NewGuestContext(ctx, auth.GuestSession(guestToken))
+		}
+		c.Request = c.Request.WithContext(ctx)
+	}, NewMiddleware(newInMemoryManager(), time.Hour))
+
+	calls := 0
+	r.POST("/items", func(c *gin.Context) {
+		calls++
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusCreated, gin.H{"call": calls, "body": string(body)})
+	})
+	r.POST("/fail", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusInternalServerError, gin.H{"call": calls})
+	})
+
+	sendAs := func(userID string, path string, key string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		request.Header.Set("X-User-ID", userID)
+		if key != "" {
+			request.Header.Set(IDEMPOTENCY_KEY_HEADER, key)
+		}
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder
+	}
+	sendAsGuest := func(guestToken string, key string, body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(body))
+		request.Header.Set("X-User-ID", "0")
+		request.Header.Set(auth.GUEST_TOKEN_HEADER, guestToken)
+		request.Header.Set(IDEMPOTENCY_KEY_HEADER, key)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder
+	}
+	send := func(path string, key string, body string) *httptest.ResponseRecorder {
+		return sendAs("1", path, key, body)
+	}
+
+	Convey("TEST requests without a key are not stored", t, func() {
+		calls = 0
+
+		send("/items", "", `{"item_id":1}`)
+		send("/items", "", `{"item_id":1}`)
+		So(calls, ShouldEqual, 2)
+	})
+
+	Convey("TEST retry with the same key replays the first response", t, func() {
+		calls = 0
+
+		first := send("/items", "replay-key", `{"item_id":1}`)
+		retry := send("/items", "replay-key", `{"item_id":1}`)
+		So(calls, ShouldEqual, 1)
+		So(retry.Code, ShouldEqual, http.StatusCreated)
+		So(retry.Body.String(), ShouldEqual, first.Body.String())
+		So(retry.Header().Get(IDEMPOTENT_REPLAYED_HEADER), ShouldEqual, "true")
+		So(first.Header().Get(IDEMPOTENT_REPLAYED_HEADER), ShouldBeEmpty)
+	})
+
+	Convey("TEST same key of another user is not replayed", t, func() {
+		calls = 0
+
+		send("/items", "user-key", `{"item_id":1}`)
+		response := sendAs("2", "/items", "user-key", `{"item_id":1}`)
+		So(calls, ShouldEqual, 2)
+		So(response.Code, ShouldEqual, http.StatusCreated)
+		So(response.Header().Get(IDEMPOTENT_REPLAYED_HEADER), ShouldBeEmpty)
+	})
+
+	Convey("TEST same key of another guest session is not replayed or rejected", t, func() {
+		calls = 0
+
+		first := sendAsGuest("guest-token", "guest-key", `{"item_id":1}`)
+		retry := sendAsGuest("guest-token", "guest-key", `{"item_id":1}`)
+		sameBody := sendAsGuest("another-guest-token", "guest-key", `{"item_id":1}`)
+		otherBody := sendAsGuest("third-guest-token", "guest-key", `{"item_id":2}`)
+		So(calls, ShouldEqual, 3)
+		So(retry.Body.String(), ShouldEqual, first.Body.String())
+		So(retry.Header().Get(IDEMPOTENT_REPLAYED_HEADER), ShouldEqual, "true")
+		So(sameBody.Code, ShouldEqual, http.StatusCreated)
+		So(sameBody.Header().Get(IDEMPOTENT_REPLAYED_HEADER), ShouldBeEmpty)
+		So(otherBody.Code, ShouldEqual, http.StatusCreated)
+	})
+
+	Convey("TEST key reused with a different body error", t, func() {
+		calls = 0
+
+		send("/items", "reused-key", `{"item_id":1}`)
+		response := send("/items", "reused-key", `{"item_id":2}`)
+		So(calls, ShouldEqual, 1)
+		So(response.Code, ShouldEqual, http.StatusUnprocessableEntity)
+		So(response.Body.String(), ShouldContainSubstring, KeyReusedErr.Error())
+	})
+
+	Convey("TEST key of a failed request is released", t, func() {
+		calls = 0
+
+		send("/fail", "failed-key", `{}`)
+		response := send("/fail", "failed-key", `{}`)
+		So(calls, ShouldEqual, 2)
+		So(response.Header().Get(IDEMPOTENT_REPLAYED_HEADER), ShouldBeEmpty)
+	})
+
+	Convey("TEST too long key error", t, func() {
+		calls = 0
+
+		response := send("/items", strings.Repeat("k", MAX_KEY_LENGTH+1), `{}`)
+		So(calls, ShouldEqual, 0)
+		So(response.Code, ShouldEqual, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,35 @@
+package idempotency
+
+import "context"
+
+type mockIdempotencyKeyManagerImpl struct {
+	MWithContext  func(ctx context.Context) IdempotencyKeyManager
+	MCreate       func(key IdempotencyKey) (bool, error)
+	MGet          func(filter IdempotencyKeyFilter) (IdempotencyKey, error)
+	MSaveResponse func(filter IdempotencyKeyFilter, statusCode int, responseBody string) error
+	MDelete       func(filter IdempotencyKeyFilter) error
+}
+
+func NewMockIdempotencyKeyManager() mockIdempotencyKeyManagerImpl {
+	return mockIdempotencyKeyManagerImpl{}
+}
+
+func (m mockIdempotencyKeyManagerImpl) WithContext(ctx context.Context) IdempotencyKeyManager {
+	return m.MWithContext(ctx)
+}
+
+func (m mockIdempotencyKeyManagerImpl) Create(key IdempotencyKey) (bool, error) {
+	return m.MCreate(key)
+}
+
+func (m mockIdempotencyKeyManagerImpl) Get(filter IdempotencyKeyFilter) (IdempotencyKey, error) {
+	return m.MGet(filter)
+}
+
+func (m mockIdempotencyKeyManagerImpl) SaveResponse(filter IdempotencyKeyFilter, statusCode int, responseBody string) error {
+	return m.MSaveResponse(filter, statusCode, responseBody)
+}
+
+func (m mockIdempotencyKeyManagerImpl) Delete(filter IdempotencyKeyFilter) error {
+	return m.MDelete(filter)
+}
@@ -0,0 +1,24 @@
+package idempotency
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// IdempotencyKey is reserved with a zero StatusCode when the first request starts, the response is stored once the
+// request is handled. Keys belong to the user who sent them, users cannot see the responses of each other. The guests
+// share the user ID 0, their keys belong to their guest session.
+type IdempotencyKey struct {
+	gorm.Model
+	UserID       uint
+	GuestSession string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody string
+	ExpiresAt    time.Time
+}
+
+func (k IdempotencyKey) isCompleted() bool {
+	return k.StatusCode != 0
+}
@@ -1,14 +1,37 @@
 package item
 
 const (
-	MAX_DEFAULT_ITEMS           = 30
-	MAX_DIGITAL_ITEMS           = 5
-	MAX_UNIQUE_ITEMS            = 10
-	DIGITAL_ITEM_CATEGORY_ID    = 7889
-	MAX_PRICE_OF_CART           = 500000.0
-	FURNITIRE_CATEGORY_ID       = 1001
-	ELECTRONIC_CATEGORY_ID      = 3004
-	VAS_ITEM_SELLER_ID          = 5003
-	VAS_ITEM_CATEGORY_ID        = 3242
-	MAX_VAS_ITEM_ON_SINGLE_ITEM = 3
+	DIGITAL_ITEM_CATEGORY_ID = 7889
+	VAS_ITEM_CATEGORY_ID     = 3242
+)
+
+// price changes of the lines of a cart since they were added
+const (
+	PRICE_INCREASED = "increased"
+	PRICE_DECREASED = "decreased"
+)
+
+// MAX_ITEM_QUANTITY is the quantity a line of an item can have, AddItemParams and UpdateItemParams bind the same maximum.
+const MAX_ITEM_QUANTITY = 10
+
+// CART_LOCK_NAMESPACE is the first key of the advisory locks of the carts, the cart ID is the second one.
+const CART_LOCK_NAMESPACE = 1
+
+// rules reported in the cart_item_add_rejections_total metric
+const (
+	RULE_DIGITAL_ITEM_WITH_DEFAULT_ITEM = "digital_item_with_default_item"
+	RULE_DEFAULT_ITEM_WITH_DIGITAL_ITEM = "default_item_with_digital_item"
+	RULE_MAX_DIGITAL_ITEMS              = "max_digital_items"
+	RULE_MAX_DEFAULT_ITEMS              = "max_default_items"
+	RULE_MAX_UNIQUE_ITEMS               = "max_unique_items"
+	RULE_MAX_PRICE_OF_CART              = "max_price_of_cart"
+	RULE_ITEM_EXISTS                    = "item_exists"
+	RULE_UNKNOWN_PRODUCT                = "unknown_product"
+	RULE_VAS_ITEM_EXISTS_IN_ITEM        = "vas_item_exists_in_item"
+	RULE_VAS_ITEM_CATEGORY              = "vas_item_category"
+	RULE_VAS_ITEM_SELLER                = "vas_item_seller"
+	RULE_VAS_ITEM_ITEM_NOT_FOUND        = "vas_item_item_not_found"
+	RULE_VAS_ITEM_ITEM_CATEGORY         = "vas_item_item_category"
+	RULE_MAX_VAS_ITEM_ON_SINGLE_ITEM    = "max_vas_item_on_single_item"
+	RULE_VAS_ITEM_PRICE_OVER_ITEM_PRICE = "vas_item_price_over_item_price"
 )
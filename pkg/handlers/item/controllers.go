@@ -3,99 +3,204 @@ package item
 import (
 	"checkoutProject/pkg/common/apiresponse"
 	db "checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/env"
 	errs "checkoutProject/pkg/common/errors"
 	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/metrics"
+	"checkoutProject/pkg/handlers/catalog"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type ItemController interface {
-	AddItem(params AddItemParams) (apiresponse.Responder, error)
-	RemoveItem(params RemoveItemParams) (apiresponse.Responder, error)
+	AddItem(ctx context.Context, params AddItemParams) (apiresponse.Responder, error)
+	UpdateItem(ctx context.Context, params UpdateItemParams) (apiresponse.Responder, error)
+	RemoveItem(ctx context.Context, params RemoveItemParams) (apiresponse.Responder, error)
 }
 
 type itemController struct {
-	itemManager ItemManager
+	itemManager   ItemManager
+	productLookup catalog.ProductLookup
+	rules         env.Rules
 }
 
-func NewItemController(itemManager ItemManager) ItemController {
+func NewItemController(itemManager ItemManager, productLookup catalog.ProductLookup, rules env.Rules) ItemController {
 	return itemController{
-		itemManager: itemManager,
+		itemManager:   itemManager,
+		productLookup: productLookup,
+		rules:         rules,
 	}
 }
 
 func NewDefaultItemController() ItemController {
-	return NewItemController(NewDefaultItemManager())
+	return NewItemController(NewDefaultItemManager(), catalog.NewDefaultProductLookup(), env.RULES)
 }
 
 func (c itemController) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
 	return l.WithFields(logrus.Fields{"api_version": "1", "controller": "item"})
 }
 
-func (c itemController) AddItem(params AddItemParams) (apiresponse.Responder, error) {
-	log := c.formattedLogger(logger.GetInstance()).WithFields(logrus.Fields{
+func (c itemController) AddItem(ctx context.Context, params AddItemParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
 		"location": "Add Item",
 	})
 
-	if params.CategoryID == VAS_ITEM_CATEGORY_ID {
-		return nil, fmt.Errorf("cannot add vas-item from this endpoint")
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	itemManager := c.itemManager.WithTx(tx)
+
+	// the checks read the cart, so concurrent requests to the same cart must not run them at the same time
+	err := lockCart(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the first item of a cart makes the user its owner, the carts of other users cannot be changed
+	err = claimCart(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
 	}
 
-	itemManager := c.itemManager
+	product, err := lookupProduct(ctx, c.productLookup, log, params.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.CategoryID == VAS_ITEM_CATEGORY_ID {
+		return nil, fmt.Errorf("cannot add vas-item from this endpoint")
+	}
 
 	item := Item{
+		CartID:     params.CartID,
 		ItemID:     params.ItemID,
-		SellerID:   params.SellerID,
-		CategoryID: params.CategoryID,
-		Price:      params.Price,
+		SellerID:   product.SellerID,
+		CategoryID: product.CategoryID,
+		Price:      product.Price,
 		Quantity:   params.Quantity,
 	}
 
-	err := addItemIsItemExistsChecks(itemManager, log, item)
+	err = addItemIsItemExistsChecks(itemManager, log, item)
 	if err != nil {
 		return nil, err
 	}
 
 	if item.isDigitalItem() {
-		err = addDigitalItemChecks(itemManager, log, item)
+		err = addDigitalItemChecks(itemManager, log, c.rules, item)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if item.isDefaultItem() {
-		err = addDefaultItemChecks(itemManager, log)
+		err = addDefaultItemChecks(itemManager, log, item)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	err = addItemPriceChecks(itemManager, log, item)
+	cart, err := findCatalogCart(ctx, itemManager, c.productLookup, log, params.CartID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = addItemNumberChecks(itemManager, log, item)
+	err = addItemPriceChecks(log, c.rules, cart.TotalPrice(), item)
+	if err != nil {
+		return nil, err
+	}
+
+	err = addItemNumberChecks(itemManager, log, c.rules, item)
 	if err != nil {
 		return nil, err
 	}
 
 	_, err = itemManager.Create(item)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		log.WithError(err).Error("item is already in the cart")
+		return nil, err
+	}
 	if err != nil {
 		log.WithError(err).Error("error while creating item")
 		return nil, errs.InternalServerErr
 	}
 
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	metrics.ItemsAdded(metrics.ITEM_TYPE, item.Quantity)
+
 	return apiresponse.GenericResponseSerializer{Result: true, Message: "item added successfully"}, nil
 }
 
-func (c itemController) RemoveItem(params RemoveItemParams) (apiresponse.Responder, error) {
+func (c itemController) UpdateItem(ctx context.Context, params UpdateItemParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Update Item",
+	})
 
-	log := c.formattedLogger(logger.GetInstance()).WithFields(logrus.Fields{
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	itemManager := c.itemManager.WithTx(tx)
+
+	err := lockCart(itemManager, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := updateItemIsItemExistsChecks(itemManager, log, params.CartID, params.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := findCatalogCart(ctx, itemManager, c.productLookup, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the increase is priced like the line is revalidated, with the current catalog price of the item
+	if line, ok := cart.FindItem(item.ItemID); ok {
+		item.Price = line.Price
+	}
+
+	err = updateItemQuantityChecks(itemManager, log, c.rules, cart.TotalPrice(), item, params.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = itemManager.UpdateQuantity(ItemFilter{CartID: params.CartID, ItemID: params.ItemID}, params.Quantity)
+	if err != nil {
+		log.WithError(err).Error("error while updating the quantity of the item")
+		return nil, errs.InternalServerErr
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	return apiresponse.GenericResponseSerializer{Result: true, Message: "item updated successfully"}, nil
+}
+
+func (c itemController) RemoveItem(ctx context.Context, params RemoveItemParams) (apiresponse.Responder, error) {
+
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
 		"location": "Remove Item",
 	})
 
-	tx := db.NewTransaction()
+	tx := db.NewTransaction(ctx)
 	defer func() {
 		if err := db.RollbackTransaction(tx); err != nil {
 			log.WithError(err).Error("error while rolling back transaction")
@@ -104,18 +209,18 @@ func (c itemController) RemoveItem(params RemoveItemParams) (apiresponse.Respond
 
 	itemManager := c.itemManager.WithTx(tx)
 
-	err := deleteItemIsItemExistsChecks(itemManager, log, params.ItemID)
+	err := deleteItemIsItemExistsChecks(itemManager, log, params.CartID, params.ItemID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = itemManager.DeleteVasItemsOfItem(params.ItemID)
+	err = itemManager.DeleteVasItemsOfItem(ItemVasItemFilter{CartID: params.CartID, ItemID: params.ItemID})
 	if err != nil {
 		log.WithError(err).Error("error while deleting the vas-items of the item")
 		return nil, errs.InternalServerErr
 	}
 
-	err = itemManager.Delete(ItemFilter{ItemID: params.ItemID})
+	err = itemManager.Delete(ItemFilter{CartID: params.CartID, ItemID: params.ItemID})
 	if err != nil {
 		log.WithError(err).Error("error while deleting the item")
 		return nil, errs.InternalServerErr
@@ -131,35 +236,40 @@ func (c itemController) RemoveItem(params RemoveItemParams) (apiresponse.Respond
 
 // vas-item controller
 type VasItemController interface {
-	AddVasItem(params AddVasItemParams) (apiresponse.Responder, error)
+	AddVasItem(ctx context.Context, params AddVasItemParams) (apiresponse.Responder, error)
+	RemoveVasItem(ctx context.Context, params RemoveVasItemParams) (apiresponse.Responder, error)
 }
 
 type vasItemController struct {
 	vasItemManager VasItemManager
 	itemManager    ItemManager
+	productLookup  catalog.ProductLookup
+	rules          env.Rules
 }
 
-func NewVasItemController(vasItemManager VasItemManager, itemManager ItemManager) VasItemController {
+func NewVasItemController(vasItemManager VasItemManager, itemManager ItemManager, productLookup catalog.ProductLookup, rules env.Rules) VasItemController {
 	return vasItemController{
 		vasItemManager: vasItemManager,
 		itemManager:    itemManager,
+		productLookup:  productLookup,
+		rules:          rules,
 	}
 }
 
 func NewDefaultVasItemController() VasItemController {
-	return NewVasItemController(NewDefaultVasItemManager(), NewDefaultItemManager())
+	return NewVasItemController(NewDefaultVasItemManager(), NewDefaultItemManager(), catalog.NewDefaultProductLookup(), env.RULES)
 }
 
 func (c vasItemController) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
 	return l.WithFields(logrus.Fields{"api_version": "1", "controller": "vas_item"})
 }
 
-func (c vasItemController) AddVasItem(params AddVasItemParams) (apiresponse.Responder, error) {
-	log := c.formattedLogger(logger.GetInstance()).WithFields(logrus.Fields{
+func (c vasItemController) AddVasItem(ctx context.Context, params AddVasItemParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
 		"location": "Add vas item",
 	})
 
-	tx := db.NewTransaction()
+	tx := db.NewTransaction(ctx)
 	defer func() {
 		if err := db.RollbackTransaction(tx); err != nil {
 			log.WithError(err).Error("error while rolling back transaction")
@@ -167,57 +277,68 @@ func (c vasItemController) AddVasItem(params AddVasItemParams) (apiresponse.Resp
 	}()
 
 	vasItemManager := c.vasItemManager.WithTx(tx)
-	itemManager := c.itemManager
+	itemManager := c.itemManager.WithTx(tx)
 
-	err := addVasItemIsVasItemExistsInItemChecks(vasItemManager, log, params.VasItemID, params.ItemID)
+	err := lockCart(itemManager, log, params.CartID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = addVasItemCategoryAndSellerChecks(log, params.CategoryID, params.SellerID)
+	err = addVasItemIsVasItemExistsInItemChecks(vasItemManager, log, params.CartID, params.VasItemID, params.ItemID)
 	if err != nil {
 		return nil, err
 	}
 
-	item, err := addVasItemIsItemExistsAndSuitableChecks(itemManager, log, params.ItemID)
+	product, err := lookupProduct(ctx, c.productLookup, log, params.VasItemID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = addVasItemNumberOfVasItemsChecks(itemManager, log, params.ItemID, params.Quantity)
+	err = addVasItemCategoryAndSellerChecks(log, c.rules, product.CategoryID, product.SellerID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = addVasItemPriceChecks(itemManager, log, params.Quantity, params.Price, item.Price)
+	item, err := addVasItemIsItemExistsAndSuitableChecks(itemManager, log, c.rules, params.CartID, params.ItemID)
 	if err != nil {
 		return nil, err
 	}
 
-	isVasItemExists, err := vasItemManager.IsExists(VasItemFilter{VasItemID: params.VasItemID})
+	err = addVasItemNumberOfVasItemsChecks(itemManager, log, c.rules, params.CartID, params.ItemID, params.Quantity)
 	if err != nil {
-		log.WithError(err).Error("error while querying the vas-item in database")
-		return nil, errs.InternalServerErr
+		return nil, err
 	}
 
-	if !isVasItemExists {
-		vasItem := VasItem{
-			VasItemID:  params.VasItemID,
-			SellerID:   params.SellerID,
-			CategoryID: params.CategoryID,
-			Price:      params.Price,
-			Quantity:   params.Quantity,
-		}
-		_, err := vasItemManager.CreateNewVasItem(vasItem)
-		if err != nil {
-			log.WithError(err).Error("error while creating new vas-item")
-			return nil, errs.InternalServerErr
-		}
+	cart, err := findCatalogCart(ctx, itemManager, c.productLookup, log, params.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the vas-item is compared with the current catalog price of the item, like the cart is revalidated
+	if line, ok := cart.FindItem(item.ItemID); ok {
+		item.Price = line.Price
 	}
 
-	itemVasItem := ItemVasItem{VasItemID: params.VasItemID, ItemID: params.ItemID}
+	err = addVasItemPriceChecks(log, c.rules, cart.TotalPrice(), params.Quantity, product.Price, item.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	itemVasItem := ItemVasItem{
+		CartID:     params.CartID,
+		ItemID:     params.ItemID,
+		VasItemID:  params.VasItemID,
+		SellerID:   product.SellerID,
+		CategoryID: product.CategoryID,
+		Price:      product.Price,
+		Quantity:   params.Quantity,
+	}
 
 	_, err = vasItemManager.CreateItemVasItem(itemVasItem)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		log.WithError(err).Error("item already has the vas-item")
+		return nil, err
+	}
 	if err != nil {
 		log.WithError(err).Error("error while creating the item_vas_item")
 		return nil, errs.InternalServerErr
@@ -228,5 +349,40 @@ func (c vasItemController) AddVasItem(params AddVasItemParams) (apiresponse.Resp
 		return nil, errs.InternalServerErr
 	}
 
+	metrics.ItemsAdded(metrics.VAS_ITEM_TYPE, params.Quantity)
+
 	return apiresponse.GenericResponseSerializer{Result: true, Message: "vas-item added successfully"}, nil
 }
+
+func (c vasItemController) RemoveVasItem(ctx context.Context, params RemoveVasItemParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Remove vas item",
+	})
+
+	tx := db.NewTransaction(ctx)
+	defer func() {
+		if err := db.RollbackTransaction(tx); err != nil {
+			log.WithError(err).Error("error while rolling back transaction")
+		}
+	}()
+
+	vasItemManager := c.vasItemManager.WithTx(tx)
+
+	err := removeVasItemIsVasItemExistsInItemChecks(vasItemManager, log, params.CartID, params.VasItemID, params.ItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = vasItemManager.DeleteItemVasItem(ItemVasItemFilter{CartID: params.CartID, VasItemID: params.VasItemID, ItemID: params.ItemID})
+	if err != nil {
+		log.WithError(err).Error("error while deleting the item_vas_item")
+		return nil, errs.InternalServerErr
+	}
+
+	if err = db.CommitTransaction(tx); err != nil {
+		log.WithError(err).Error("error while committing the transaction")
+		return nil, errs.InternalServerErr
+	}
+
+	return apiresponse.GenericResponseSerializer{Result: true, Message: "vas-item removed successfully"}, nil
+}
@@ -1,26 +1,40 @@
 package item
 
-import "gorm.io/gorm"
+import (
+	db "checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/money"
+	"gorm.io/gorm"
+)
 
 type ItemFilter struct {
 	ID            uint
+	CartID        uint
 	ItemID        uint
+	ItemIDNot     uint
 	CategoryID    uint
 	CategoryIDNot uint
 	SellerID      uint
-	Price         float64
+	Price         money.Amount
 	Quantity      uint
 }
 
 func (f ItemFilter) ToQuery(q *gorm.DB) *gorm.DB {
-	q = q.Where(Item{
+	q = db.OwnedCarts(q, "items").Where(Item{
 		Model: gorm.Model{ID: f.ID},
 	})
 
+	if f.CartID != 0 {
+		q = q.Where("items.cart_id = ?", f.CartID)
+	}
+
 	if f.ItemID != 0 {
 		q = q.Where("items.item_id = ?", f.ItemID)
 	}
 
+	if f.ItemIDNot != 0 {
+		q = q.Where("items.item_id <> ?", f.ItemIDNot)
+	}
+
 	if f.CategoryID != 0 {
 		q = q.Where("items.category_id = ?", f.CategoryID)
 	}
@@ -44,59 +58,22 @@ func (f ItemFilter) ToQuery(q *gorm.DB) *gorm.DB {
 	return q
 }
 
-type VasItemFilter struct {
-	ID            uint
-	VasItemID     uint
-	CategoryID    uint
-	CategoryIDNot uint
-	SellerID      uint
-	Price         float64
-	Quantity      uint
-}
-
-func (f VasItemFilter) ToQuery(q *gorm.DB) *gorm.DB {
-	q = q.Where(VasItem{
-		Model: gorm.Model{ID: f.ID},
-	})
-
-	if f.VasItemID != 0 {
-		q = q.Where("vas_items.vas_item_id = ?", f.VasItemID)
-	}
-
-	if f.CategoryID != 0 {
-		q = q.Where("vas_items.category_id = ?", f.CategoryID)
-	}
-
-	if f.CategoryIDNot != 0 {
-		q = q.Where("vas_items.category_id <> ?", f.CategoryIDNot)
-	}
-
-	if f.SellerID != 0 {
-		q = q.Where("vas_items.seller_id = ?", f.SellerID)
-	}
-
-	if f.Price != 0 {
-		q = q.Where("vas_items.price = ?", f.Price)
-	}
-
-	if f.Quantity != 0 {
-		q = q.Where("vas_items.quantity = ?", f.Quantity)
-	}
-
-	return q
-}
-
 type ItemVasItemFilter struct {
 	ID        uint
+	CartID    uint
 	VasItemID uint
 	ItemID    uint
 }
 
 func (f ItemVasItemFilter) ToQuery(q *gorm.DB) *gorm.DB {
-	q = q.Where(ItemVasItem{
+	q = db.OwnedCarts(q, "item_vas_items").Where(ItemVasItem{
 		Model: gorm.Model{ID: f.ID},
 	})
 
+	if f.CartID != 0 {
+		q = q.Where("item_vas_items.cart_id = ?", f.CartID)
+	}
+
 	if f.VasItemID != 0 {
 		q = q.Where("item_vas_items.vas_item_id = ?", f.VasItemID)
 	}
@@ -1,15 +1,77 @@
 package item
 
 import (
+	"checkoutProject/pkg/common/env"
 	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/metrics"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func addDigitalItemChecks(itemManager ItemManager, log *logrus.Entry, item Item) error {
-	isNonDigitalExists, err := itemManager.IsExists(ItemFilter{CategoryIDNot: DIGITAL_ITEM_CATEGORY_ID})
+func lockCart(itemManager ItemManager, log *logrus.Entry, cartID uint) error {
+	err := itemManager.LockCart(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while locking the cart")
+		return errs.InternalServerErr
+	}
+	return nil
+}
+
+// lookupProduct returns the item or the vas-item from the catalog, the clients do not send their prices.
+func lookupProduct(ctx context.Context, productLookup catalog.ProductLookup, log *logrus.Entry, productID uint) (catalog.Product, error) {
+	product, err := productLookup.LookupProduct(ctx, productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Errorf("product %d is not in the catalog", productID)
+		metrics.ItemAddRejected(RULE_UNKNOWN_PRODUCT)
+		return catalog.Product{}, fmt.Errorf("product %d is not in the catalog", productID)
+	}
+
+	if err != nil {
+		log.WithError(err).Error("error while querying the product")
+		return catalog.Product{}, errs.InternalServerErr
+	}
+	return product, nil
+}
+
+// findCatalogCart returns the content of the cart with the current catalog prices, the limits on the price of the cart
+// are checked with the same prices the cart is revalidated with on display and checkout.
+func findCatalogCart(ctx context.Context, itemManager ItemManager, productLookup catalog.ProductLookup, log *logrus.Entry, cartID uint) (CartContent, error) {
+	cart, err := itemManager.FindCartContent(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while finding the content of the cart")
+		return CartContent{}, errs.InternalServerErr
+	}
+
+	products, err := productLookup.LookupProducts(ctx, cart.ProductIDs())
+	if err != nil {
+		log.WithError(err).Error("error while querying the products of the cart")
+		return CartContent{}, errs.InternalServerErr
+	}
+
+	return cart.WithCatalogPrices(products), nil
+}
+
+func claimCart(itemManager ItemManager, log *logrus.Entry, cartID uint) error {
+	isOwner, err := itemManager.ClaimCart(cartID)
+	if err != nil {
+		log.WithError(err).Error("error while claiming the cart")
+		return errs.InternalServerErr
+	}
+
+	if !isOwner {
+		log.Errorf("cart %d belongs to another user", cartID)
+		return errs.RecordNotFoundErr
+	}
+	return nil
+}
+
+func addDigitalItemChecks(itemManager ItemManager, log *logrus.Entry, rules env.Rules, item Item) error {
+	isNonDigitalExists, err := itemManager.IsExists(ItemFilter{CartID: item.CartID, CategoryIDNot: DIGITAL_ITEM_CATEGORY_ID})
 	if err != nil {
 		log.WithError(err).Error("error while querying the items")
 		return errs.InternalServerErr
@@ -17,24 +79,26 @@ func addDigitalItemChecks(itemManager ItemManager, log *logrus.Entry, item Item)
 
 	if isNonDigitalExists {
 		log.Error("cannot add a digital item if default item exists in cart")
+		metrics.ItemAddRejected(RULE_DIGITAL_ITEM_WITH_DEFAULT_ITEM)
 		return fmt.Errorf("cannot add a digital item if default item exists in cart")
 	}
 
-	numberOfDigitalItem, err := itemManager.GetTotalItemCount(ItemFilter{CategoryID: DIGITAL_ITEM_CATEGORY_ID})
+	numberOfDigitalItem, err := itemManager.GetTotalItemCount(ItemFilter{CartID: item.CartID, CategoryID: DIGITAL_ITEM_CATEGORY_ID})
 	if err != nil {
 		log.WithError(err).Error("error while finding the number of digital items")
 		return errs.InternalServerErr
 	}
 
-	if numberOfDigitalItem+item.Quantity > MAX_DIGITAL_ITEMS {
-		log.WithError(err).Errorf("error, total number of ditial items cannot be over %d", MAX_DIGITAL_ITEMS)
-		return fmt.Errorf("total number of digital items cannot be over %d", MAX_DIGITAL_ITEMS)
+	if numberOfDigitalItem+item.Quantity > rules.MaxDigitalItems {
+		log.WithError(err).Errorf("error, total number of ditial items cannot be over %d", rules.MaxDigitalItems)
+		metrics.ItemAddRejected(RULE_MAX_DIGITAL_ITEMS)
+		return fmt.Errorf("total number of digital items cannot be over %d", rules.MaxDigitalItems)
 	}
 	return nil
 }
 
-func addDefaultItemChecks(itemManager ItemManager, log *logrus.Entry) error {
-	isDigitalItemExists, err := itemManager.IsExists(ItemFilter{CategoryID: DIGITAL_ITEM_CATEGORY_ID})
+func addDefaultItemChecks(itemManager ItemManager, log *logrus.Entry, item Item) error {
+	isDigitalItemExists, err := itemManager.IsExists(ItemFilter{CartID: item.CartID, CategoryID: DIGITAL_ITEM_CATEGORY_ID})
 	if err != nil {
 		log.WithError(err).Error("error while querying the items")
 		return errs.InternalServerErr
@@ -42,53 +106,53 @@ func addDefaultItemChecks(itemManager ItemManager, log *logrus.Entry) error {
 
 	if isDigitalItemExists {
 		log.Error("cannot add a default item if digital item exists in cart")
+		metrics.ItemAddRejected(RULE_DEFAULT_ITEM_WITH_DIGITAL_ITEM)
 		return fmt.Errorf("cannot add a default item if digital item exists in cart")
 	}
 
 	return nil
 }
 
-func addItemPriceChecks(itemManager ItemManager, log *logrus.Entry, item Item) error {
-	totalPrice, err := itemManager.GetTotalPrice()
-	if err != nil {
-		log.WithError(err).Error("error while finding the total price of items")
-		return errs.InternalServerErr
-	}
-
-	if totalPrice+item.OrderPrice() > MAX_PRICE_OF_CART {
-		log.WithError(err).Errorf("total price of cart cannot be over %f", MAX_PRICE_OF_CART)
-		return fmt.Errorf("total price of cart cannot be over %.2f", MAX_PRICE_OF_CART)
+// addItemPriceChecks checks the item against the total price of the cart with the catalog prices, see findCatalogCart.
+func addItemPriceChecks(log *logrus.Entry, rules env.Rules, totalPrice money.Amount, item Item) error {
+	if totalPrice+item.OrderPrice() > rules.MaxPriceOfCart {
+		log.Errorf("total price of cart cannot be over %s", rules.MaxPriceOfCart)
+		metrics.ItemAddRejected(RULE_MAX_PRICE_OF_CART)
+		return fmt.Errorf("total price of cart cannot be over %s", rules.MaxPriceOfCart)
 	}
 	return nil
 }
 
-func addItemNumberChecks(itemManager ItemManager, log *logrus.Entry, item Item) error {
-	numberOfItem, err := itemManager.GetTotalItemCount(ItemFilter{})
+func addItemNumberChecks(itemManager ItemManager, log *logrus.Entry, rules env.Rules, item Item) error {
+	numberOfItem, err := itemManager.GetTotalItemCount(ItemFilter{CartID: item.CartID})
 	if err != nil {
 		log.WithError(err).Error("error while finding the number of items")
 		return errs.InternalServerErr
 	}
 
-	if item.Quantity+numberOfItem > MAX_DEFAULT_ITEMS {
-		log.WithError(err).Errorf("error, total number of items cannot be over %d", MAX_DEFAULT_ITEMS)
-		return fmt.Errorf("total number of items cannot be over %d", MAX_DEFAULT_ITEMS)
+	if item.Quantity+numberOfItem > rules.MaxDefaultItems {
+		log.WithError(err).Errorf("error, total number of items cannot be over %d", rules.MaxDefaultItems)
+		metrics.ItemAddRejected(RULE_MAX_DEFAULT_ITEMS)
+		return fmt.Errorf("total number of items cannot be over %d", rules.MaxDefaultItems)
 	}
 
-	numberOfUniqueItem, err := itemManager.GetUniqueItemCount()
+	// the item itself is not counted, so increasing the quantity of an existing item is not blocked by this check
+	numberOfUniqueItem, err := itemManager.GetUniqueItemCount(ItemFilter{CartID: item.CartID, ItemIDNot: item.ItemID})
 	if err != nil {
 		log.WithError(err).Error("error while finding the number of unique items")
 		return errs.InternalServerErr
 	}
 
-	if numberOfUniqueItem >= 10 {
-		log.WithError(err).Errorf("error, number of unique items cannot be over %d", MAX_UNIQUE_ITEMS)
-		return fmt.Errorf("total number of unique items cannot be over %d", MAX_UNIQUE_ITEMS)
+	if numberOfUniqueItem >= int64(rules.MaxUniqueItems) {
+		log.WithError(err).Errorf("error, number of unique items cannot be over %d", rules.MaxUniqueItems)
+		metrics.ItemAddRejected(RULE_MAX_UNIQUE_ITEMS)
+		return fmt.Errorf("total number of unique items cannot be over %d", rules.MaxUniqueItems)
 	}
 	return nil
 }
 
 func addItemIsItemExistsChecks(itemManager ItemManager, log *logrus.Entry, item Item) error {
-	isItemExists, err := itemManager.IsExists(ItemFilter{ItemID: item.ItemID})
+	isItemExists, err := itemManager.IsExists(ItemFilter{CartID: item.CartID, ItemID: item.ItemID})
 	if err != nil {
 		log.WithError(err).Error("error while querying the item in database")
 		return errs.InternalServerErr
@@ -96,13 +160,14 @@ func addItemIsItemExistsChecks(itemManager ItemManager, log *logrus.Entry, item
 
 	if isItemExists {
 		log.Error("error, item with same item id already exists")
+		metrics.ItemAddRejected(RULE_ITEM_EXISTS)
 		return fmt.Errorf("item with ID %d already exists. Please choose a different item ID", item.ItemID)
 	}
 	return nil
 }
 
-func deleteItemIsItemExistsChecks(itemManager ItemManager, log *logrus.Entry, itemID uint) error {
-	isItemExists, err := itemManager.IsExists(ItemFilter{ItemID: itemID})
+func deleteItemIsItemExistsChecks(itemManager ItemManager, log *logrus.Entry, cartID uint, itemID uint) error {
+	isItemExists, err := itemManager.IsExists(ItemFilter{CartID: cartID, ItemID: itemID})
 	if err != nil {
 		log.WithError(err).Error("error while querying the item")
 		return errs.InternalServerErr
@@ -115,8 +180,46 @@ func deleteItemIsItemExistsChecks(itemManager ItemManager, log *logrus.Entry, it
 	return nil
 }
 
-func addVasItemIsVasItemExistsInItemChecks(vasItemManager VasItemManager, log *logrus.Entry, vasItemID uint, itemID uint) error {
-	isVasItemExistsInItem, err := vasItemManager.IsExistsInItem(ItemVasItemFilter{VasItemID: vasItemID, ItemID: itemID})
+func updateItemIsItemExistsChecks(itemManager ItemManager, log *logrus.Entry, cartID uint, itemID uint) (Item, error) {
+	item, err := itemManager.Get(ItemFilter{CartID: cartID, ItemID: itemID})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("record not found")
+		return Item{}, errs.RecordNotFoundErr
+	}
+
+	if err != nil {
+		log.WithError(err).Error("error while querying the item")
+		return Item{}, errs.InternalServerErr
+	}
+	return item, nil
+}
+
+func updateItemQuantityChecks(itemManager ItemManager, log *logrus.Entry, rules env.Rules, totalPrice money.Amount, item Item, quantity uint) error {
+	if quantity <= item.Quantity {
+		return nil
+	}
+
+	// only the increase is checked against the limits since the current quantity is already in the cart
+	increase := item
+	increase.Quantity = quantity - item.Quantity
+
+	if increase.isDigitalItem() {
+		err := addDigitalItemChecks(itemManager, log, rules, increase)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := addItemPriceChecks(log, rules, totalPrice, increase)
+	if err != nil {
+		return err
+	}
+
+	return addItemNumberChecks(itemManager, log, rules, increase)
+}
+
+func addVasItemIsVasItemExistsInItemChecks(vasItemManager VasItemManager, log *logrus.Entry, cartID uint, vasItemID uint, itemID uint) error {
+	isVasItemExistsInItem, err := vasItemManager.IsExistsInItem(ItemVasItemFilter{CartID: cartID, VasItemID: vasItemID, ItemID: itemID})
 	if err != nil {
 		log.WithError(err).Error("error while querying the item_vas_item in database")
 		return errs.InternalServerErr
@@ -124,26 +227,43 @@ func addVasItemIsVasItemExistsInItemChecks(vasItemManager VasItemManager, log *l
 
 	if isVasItemExistsInItem {
 		log.Error("error, this item already has this vas-item")
+		metrics.ItemAddRejected(RULE_VAS_ITEM_EXISTS_IN_ITEM)
 		return fmt.Errorf("item already has this vas-item, cannot add same vas-item multiple times to a single item")
 	}
 	return nil
 }
 
-func addVasItemCategoryAndSellerChecks(log *logrus.Entry, categoryID uint, sellerID uint) error {
+func removeVasItemIsVasItemExistsInItemChecks(vasItemManager VasItemManager, log *logrus.Entry, cartID uint, vasItemID uint, itemID uint) error {
+	isVasItemExistsInItem, err := vasItemManager.IsExistsInItem(ItemVasItemFilter{CartID: cartID, VasItemID: vasItemID, ItemID: itemID})
+	if err != nil {
+		log.WithError(err).Error("error while querying the item_vas_item in database")
+		return errs.InternalServerErr
+	}
+
+	if !isVasItemExistsInItem {
+		log.Error("record not found")
+		return errs.RecordNotFoundErr
+	}
+	return nil
+}
+
+func addVasItemCategoryAndSellerChecks(log *logrus.Entry, rules env.Rules, categoryID uint, sellerID uint) error {
 	if categoryID != VAS_ITEM_CATEGORY_ID {
 		log.Errorf("cannot add vas-item with category id %d", categoryID)
+		metrics.ItemAddRejected(RULE_VAS_ITEM_CATEGORY)
 		return fmt.Errorf("cannot add vas-item with category id %d", categoryID)
 	}
 
-	if sellerID != VAS_ITEM_SELLER_ID {
+	if sellerID != rules.VasItemSellerID {
 		log.Errorf("cannot add vas-item with seller id %d", sellerID)
+		metrics.ItemAddRejected(RULE_VAS_ITEM_SELLER)
 		return fmt.Errorf("cannot add vas-item with seller id %d", sellerID)
 	}
 	return nil
 }
 
-func addVasItemIsItemExistsAndSuitableChecks(itemManager ItemManager, log *logrus.Entry, itemID uint) (Item, error) {
-	item, err := itemManager.Get(ItemFilter{ItemID: itemID})
+func addVasItemIsItemExistsAndSuitableChecks(itemManager ItemManager, log *logrus.Entry, rules env.Rules, cartID uint, itemID uint) (Item, error) {
+	item, err := itemManager.Get(ItemFilter{CartID: cartID, ItemID: itemID})
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.WithError(err).Error("error while querying the item")
 		return Item{}, errs.InternalServerErr
@@ -151,44 +271,45 @@ func addVasItemIsItemExistsAndSuitableChecks(itemManager ItemManager, log *logru
 
 	if item.ItemID == 0 {
 		log.Error("error, item to add vas-item does not exists")
+		metrics.ItemAddRejected(RULE_VAS_ITEM_ITEM_NOT_FOUND)
 		return Item{}, fmt.Errorf("cannot add vas-item, item %d does not exist", itemID)
 	}
 
-	if !item.isApplicableForVasItems() {
+	if !item.isApplicableForVasItems(rules.VasItemEligibleCategoryIDs) {
 		log.Error("error, item category is not suitable to add vas-items")
+		metrics.ItemAddRejected(RULE_VAS_ITEM_ITEM_CATEGORY)
 		return Item{}, fmt.Errorf("item category is not suitable to add vas-items")
 	}
 	return item, nil
 }
 
-func addVasItemNumberOfVasItemsChecks(itemManager ItemManager, log *logrus.Entry, itemID uint, quantity uint) error {
-	numberOfVasItemsInItem, err := itemManager.GetTotalVasItemCount(ItemVasItemFilter{ItemID: itemID})
+func addVasItemNumberOfVasItemsChecks(itemManager ItemManager, log *logrus.Entry, rules env.Rules, cartID uint, itemID uint, quantity uint) error {
+	numberOfVasItemsInItem, err := itemManager.GetTotalVasItemCount(ItemVasItemFilter{CartID: cartID, ItemID: itemID})
 	if err != nil {
 		log.WithError(err).Error("error while finding the number of vas-items in an item")
 		return errs.InternalServerErr
 	}
 
-	if numberOfVasItemsInItem+quantity > MAX_VAS_ITEM_ON_SINGLE_ITEM {
-		log.Errorf("error, cannot add more than %d vas-items to the same item", MAX_VAS_ITEM_ON_SINGLE_ITEM)
-		return fmt.Errorf("item %d has already %d vas-items, cannot add more than %d vas-items to the same item", itemID, numberOfVasItemsInItem, MAX_VAS_ITEM_ON_SINGLE_ITEM)
+	if numberOfVasItemsInItem+quantity > rules.MaxVasItemOnSingleItem {
+		log.Errorf("error, cannot add more than %d vas-items to the same item", rules.MaxVasItemOnSingleItem)
+		metrics.ItemAddRejected(RULE_MAX_VAS_ITEM_ON_SINGLE_ITEM)
+		return fmt.Errorf("item %d has already %d vas-items, cannot add more than %d vas-items to the same item", itemID, numberOfVasItemsInItem, rules.MaxVasItemOnSingleItem)
 	}
 	return nil
 }
 
-func addVasItemPriceChecks(itemManager ItemManager, log *logrus.Entry, quantity uint, vasItemPrice float64, itemPrice float64) error {
-	totalPrice, err := itemManager.GetTotalPrice()
-	if err != nil {
-		log.WithError(err).Error("error while finding the total price of the cart")
-		return errs.InternalServerErr
-	}
-
-	if totalPrice+float64(quantity)*vasItemPrice > MAX_PRICE_OF_CART {
+// addVasItemPriceChecks checks the vas-item against the total price of the cart and the price of the item with the
+// catalog prices, see findCatalogCart.
+func addVasItemPriceChecks(log *logrus.Entry, rules env.Rules, totalPrice money.Amount, quantity uint, vasItemPrice money.Amount, itemPrice money.Amount) error {
+	if totalPrice+vasItemPrice.Mul(quantity) > rules.MaxPriceOfCart {
 		log.Error("error, vas-items price cannot be more than items price")
-		return fmt.Errorf("total price of the cart cannot be ovwer %.2f", MAX_PRICE_OF_CART)
+		metrics.ItemAddRejected(RULE_MAX_PRICE_OF_CART)
+		return fmt.Errorf("total price of the cart cannot be ovwer %s", rules.MaxPriceOfCart)
 	}
 
 	if itemPrice < vasItemPrice {
 		log.Error("error, vas-items price cannot be more than items price")
+		metrics.ItemAddRejected(RULE_VAS_ITEM_PRICE_OVER_ITEM_PRICE)
 		return fmt.Errorf("error, sinlge vas-item's price cannot be more than single item's price")
 	}
 	return nil
@@ -1,8 +1,12 @@
 package item
 
 import (
+	"checkoutProject/pkg/common/env"
 	errs "checkoutProject/pkg/common/errors"
 	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	"context"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	. "github.com/smartystreets/goconvey/convey"
@@ -10,6 +14,165 @@ import (
 	"testing"
 )
 
+var testRules = env.DefaultRules()
+
+func TestLockCart(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := NewMockItemManager()
+
+	Convey("TEST itemManager.LockCart fail", t, func() {
+		mockItemManager.MLockCart = func(cartID uint) error {
+			return gorm.ErrInvalidTransaction
+		}
+
+		err := lockCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST locks the given cart", t, func() {
+		var lockedCartID uint
+		mockItemManager.MLockCart = func(cartID uint) error {
+			lockedCartID = cartID
+			return nil
+		}
+
+		err := lockCart(mockItemManager, log.WithFields(logrus.Fields{}), 7)
+		So(err, ShouldBeNil)
+		So(lockedCartID, ShouldEqual, 7)
+	})
+}
+
+func TestClaimCart(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := NewMockItemManager()
+
+	Convey("TEST itemManager.ClaimCart fail", t, func() {
+		mockItemManager.MClaimCart = func(cartID uint) (bool, error) {
+			return false, gorm.ErrInvalidTransaction
+		}
+
+		err := claimCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST cart of another user error", t, func() {
+		mockItemManager.MClaimCart = func(cartID uint) (bool, error) {
+			return false, nil
+		}
+
+		err := claimCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.RecordNotFoundErr)
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockItemManager.MClaimCart = func(cartID uint) (bool, error) {
+			return true, nil
+		}
+
+		err := claimCart(mockItemManager, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestLookupProduct(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockProductLookup := catalog.NewMockProductLookup()
+
+	Convey("TEST productLookup.LookupProduct fail", t, func() {
+		mockProductLookup.MLookupProduct = func(ctx context.Context, productID uint) (catalog.Product, error) {
+			return catalog.Product{}, gorm.ErrInvalidTransaction
+		}
+
+		_, err := lookupProduct(context.Background(), mockProductLookup, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST product is not in the catalog", t, func() {
+		mockProductLookup.MLookupProduct = func(ctx context.Context, productID uint) (catalog.Product, error) {
+			return catalog.Product{}, gorm.ErrRecordNotFound
+		}
+
+		_, err := lookupProduct(context.Background(), mockProductLookup, log.WithFields(logrus.Fields{}), 5)
+		So(err.Error(), ShouldEqual, "product 5 is not in the catalog")
+	})
+
+	Convey("TEST returns the product of the catalog", t, func() {
+		var lookedUpProductID uint
+		mockProductLookup.MLookupProduct = func(ctx context.Context, productID uint) (catalog.Product, error) {
+			lookedUpProductID = productID
+			return catalog.Product{ProductID: productID, CategoryID: 1001, SellerID: 3, Price: 10 * money.Unit}, nil
+		}
+
+		product, err := lookupProduct(context.Background(), mockProductLookup, log.WithFields(logrus.Fields{}), 7)
+		So(err, ShouldBeNil)
+		So(lookedUpProductID, ShouldEqual, 7)
+		So(product, ShouldResemble, catalog.Product{ProductID: 7, CategoryID: 1001, SellerID: 3, Price: 10 * money.Unit})
+	})
+}
+
+func TestFindCatalogCart(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := NewMockItemManager()
+	mockProductLookup := catalog.NewMockProductLookup()
+
+	cart := CartContent{
+		Items:    []Item{{CartID: 1, ItemID: 1, Price: 100 * money.Unit, Quantity: 2}, {CartID: 1, ItemID: 2, Price: 50 * money.Unit, Quantity: 1}},
+		VasItems: map[uint][]ItemVasItem{1: {{CartID: 1, ItemID: 1, VasItemID: 10, Price: 0, Quantity: 1}}},
+	}
+
+	Convey("TEST itemManager.FindCartContent fail", t, func() {
+		mockItemManager.MFindCartContent = func(cartID uint) (CartContent, error) {
+			return CartContent{}, gorm.ErrInvalidTransaction
+		}
+
+		_, err := findCatalogCart(context.Background(), mockItemManager, mockProductLookup, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST productLookup.LookupProducts fail", t, func() {
+		mockItemManager.MFindCartContent = func(cartID uint) (CartContent, error) {
+			return cart, nil
+		}
+		mockProductLookup.MLookupProducts = func(ctx context.Context, productIDs []uint) (map[uint]catalog.Product, error) {
+			return nil, gorm.ErrInvalidTransaction
+		}
+
+		_, err := findCatalogCart(context.Background(), mockItemManager, mockProductLookup, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST the total price is calculated with the catalog prices", t, func() {
+		var lookedUpProductIDs []uint
+		mockItemManager.MFindCartContent = func(cartID uint) (CartContent, error) {
+			return cart, nil
+		}
+		mockProductLookup.MLookupProducts = func(ctx context.Context, productIDs []uint) (map[uint]catalog.Product, error) {
+			lookedUpProductIDs = productIDs
+			return map[uint]catalog.Product{
+				1:  {ProductID: 1, Price: 200 * money.Unit},
+				10: {ProductID: 10, Price: 30 * money.Unit},
+			}, nil
+		}
+
+		catalogCart, err := findCatalogCart(context.Background(), mockItemManager, mockProductLookup, log.WithFields(logrus.Fields{}), 1)
+		So(err, ShouldBeNil)
+		So(lookedUpProductIDs, ShouldResemble, []uint{1, 10, 2})
+		So(catalogCart.TotalPrice(), ShouldEqual, 480*money.Unit)
+	})
+}
+
 func TestAddDigitalItemChecks(t *testing.T) {
 	log, err := logger.Initialize()
 	if err != nil {
@@ -22,7 +185,7 @@ func TestAddDigitalItemChecks(t *testing.T) {
 			return false, errs.InternalServerErr
 		}
 
-		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
+		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -36,7 +199,7 @@ func TestAddDigitalItemChecks(t *testing.T) {
 			return 0, errs.InternalServerErr
 		}
 
-		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
+		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -46,7 +209,7 @@ func TestAddDigitalItemChecks(t *testing.T) {
 			return true, nil
 		}
 
-		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
+		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
 		So(err, ShouldEqual, fmt.Errorf("cannot add a digital item if default item exists in cart"))
 	})
 
@@ -60,8 +223,8 @@ func TestAddDigitalItemChecks(t *testing.T) {
 			return 3, nil
 		}
 
-		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{Quantity: 3})
-		So(err, ShouldEqual, fmt.Errorf("total number of digital items cannot be over %d", MAX_DIGITAL_ITEMS))
+		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{Quantity: 3})
+		So(err, ShouldEqual, fmt.Errorf("total number of digital items cannot be over %d", testRules.MaxDigitalItems))
 	})
 
 	Convey("TEST succeed and return without error", t, func() {
@@ -74,7 +237,7 @@ func TestAddDigitalItemChecks(t *testing.T) {
 			return 2, nil
 		}
 
-		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{Quantity: 1})
+		err := addDigitalItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{Quantity: 1})
 		So(err, ShouldBeNil)
 	})
 
@@ -92,7 +255,7 @@ func TestAddDefaultItemChecks(t *testing.T) {
 			return false, errs.InternalServerErr
 		}
 
-		err := addDefaultItemChecks(mockItemManager, log.WithFields(logrus.Fields{}))
+		err := addDefaultItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -102,7 +265,7 @@ func TestAddDefaultItemChecks(t *testing.T) {
 			return true, nil
 		}
 
-		err := addDefaultItemChecks(mockItemManager, log.WithFields(logrus.Fields{}))
+		err := addDefaultItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
 		So(err, ShouldEqual, fmt.Errorf("cannot add a default item if digital item exists in cart"))
 	})
 
@@ -112,7 +275,7 @@ func TestAddDefaultItemChecks(t *testing.T) {
 			return false, nil
 		}
 
-		err := addDefaultItemChecks(mockItemManager, log.WithFields(logrus.Fields{}))
+		err := addDefaultItemChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
 		So(err, ShouldEqual, nil)
 	})
 }
@@ -122,32 +285,19 @@ func TestAddItemPriceChecks(t *testing.T) {
 	if err != nil {
 		t.Fail()
 	}
-	mockItemManager := NewMockItemManager()
-
-	Convey("TEST itemManager.getTotalPrice fail", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 0, errs.InternalServerErr
-		}
-
-		err := addItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
-		So(err, ShouldEqual, errs.InternalServerErr)
-	})
 
 	Convey("TEST total price exceeds limit error", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 300000, nil
-		}
+		err := addItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, 300000*money.Unit, Item{Quantity: 2, Price: 100001 * money.Unit})
+		So(err, ShouldEqual, fmt.Errorf("total price of cart cannot be over %s", testRules.MaxPriceOfCart))
+	})
 
-		err := addItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{Quantity: 2, Price: 100001})
-		So(err, ShouldEqual, fmt.Errorf("total price of cart cannot be over %.2f", MAX_PRICE_OF_CART))
+	Convey("TEST total price exceeds limit by a cent error", t, func() {
+		err := addItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, money.FromFloat(499999.99), Item{Quantity: 2, Price: money.FromFloat(0.01)})
+		So(err, ShouldEqual, fmt.Errorf("total price of cart cannot be over %s", testRules.MaxPriceOfCart))
 	})
 
 	Convey("TEST succeed without error", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 300000, nil
-		}
-
-		err := addItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{Quantity: 2, Price: 100000})
+		err := addItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, 300000*money.Unit, Item{Quantity: 2, Price: 100000 * money.Unit})
 		So(err, ShouldEqual, nil)
 	})
 }
@@ -164,7 +314,7 @@ func TestAddItemNumberNumberChecks(t *testing.T) {
 			return 0, errs.InternalServerErr
 		}
 
-		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -173,11 +323,11 @@ func TestAddItemNumberNumberChecks(t *testing.T) {
 			return 25, nil
 		}
 
-		mockItemManager.MGetUniqueItemCount = func() (int64, error) {
+		mockItemManager.MGetUniqueItemCount = func(filter ItemFilter) (int64, error) {
 			return 0, errs.InternalServerErr
 		}
 
-		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -186,8 +336,8 @@ func TestAddItemNumberNumberChecks(t *testing.T) {
 			return 25, nil
 		}
 
-		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{Quantity: 6})
-		So(err, ShouldEqual, fmt.Errorf("total number of items cannot be over %d", MAX_DEFAULT_ITEMS))
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{Quantity: 6})
+		So(err, ShouldEqual, fmt.Errorf("total number of items cannot be over %d", testRules.MaxDefaultItems))
 	})
 
 	Convey("TEST number of unique item exceeds limit error", t, func() {
@@ -195,12 +345,28 @@ func TestAddItemNumberNumberChecks(t *testing.T) {
 			return 20, nil
 		}
 
-		mockItemManager.MGetUniqueItemCount = func() (int64, error) {
+		mockItemManager.MGetUniqueItemCount = func(filter ItemFilter) (int64, error) {
 			return 10, nil
 		}
 
-		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
-		So(err, ShouldEqual, fmt.Errorf("total number of unique items cannot be over %d", MAX_UNIQUE_ITEMS))
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
+		So(err, ShouldEqual, fmt.Errorf("total number of unique items cannot be over %d", testRules.MaxUniqueItems))
+	})
+
+	Convey("TEST number of unique item exceeds configured limit error", t, func() {
+		mockItemManager.MGetTotalItemCount = func(filter ItemFilter) (uint, error) {
+			return 20, nil
+		}
+
+		mockItemManager.MGetUniqueItemCount = func(filter ItemFilter) (int64, error) {
+			return 5, nil
+		}
+
+		rules := env.DefaultRules()
+		rules.MaxUniqueItems = 5
+
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), rules, Item{})
+		So(err, ShouldEqual, fmt.Errorf("total number of unique items cannot be over %d", 5))
 	})
 
 	Convey("TEST succeed without error", t, func() {
@@ -208,13 +374,31 @@ func TestAddItemNumberNumberChecks(t *testing.T) {
 			return 20, nil
 		}
 
-		mockItemManager.MGetUniqueItemCount = func() (int64, error) {
+		mockItemManager.MGetUniqueItemCount = func(filter ItemFilter) (int64, error) {
 			return 5, nil
 		}
 
-		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), Item{})
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{})
 		So(err, ShouldEqual, nil)
 	})
+
+	Convey("TEST counts only the items of the given cart", t, func() {
+		var totalCountFilter, uniqueCountFilter ItemFilter
+		mockItemManager.MGetTotalItemCount = func(filter ItemFilter) (uint, error) {
+			totalCountFilter = filter
+			return 20, nil
+		}
+
+		mockItemManager.MGetUniqueItemCount = func(filter ItemFilter) (int64, error) {
+			uniqueCountFilter = filter
+			return 5, nil
+		}
+
+		err := addItemNumberChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, Item{CartID: 7})
+		So(err, ShouldBeNil)
+		So(totalCountFilter.CartID, ShouldEqual, 7)
+		So(uniqueCountFilter.CartID, ShouldEqual, 7)
+	})
 }
 
 func TestAddItemIsItemExistsChecks(t *testing.T) {
@@ -264,7 +448,7 @@ func TestDeleteItemIsItemExistsChecks(t *testing.T) {
 			return false, errs.InternalServerErr
 		}
 
-		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 3)
+		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 1, 3)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -273,7 +457,7 @@ func TestDeleteItemIsItemExistsChecks(t *testing.T) {
 			return false, nil
 		}
 
-		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 3)
+		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 1, 3)
 		So(err, ShouldEqual, errs.RecordNotFoundErr)
 	})
 
@@ -282,8 +466,109 @@ func TestDeleteItemIsItemExistsChecks(t *testing.T) {
 			return true, nil
 		}
 
-		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 3)
+		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 1, 3)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("TEST looks for the item in the given cart", t, func() {
+		var isExistsFilter ItemFilter
+		mockItemManager.MIsExists = func(filter ItemFilter) (bool, error) {
+			isExistsFilter = filter
+			return true, nil
+		}
+
+		err := deleteItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 4, 3)
+		So(err, ShouldBeNil)
+		So(isExistsFilter.CartID, ShouldEqual, 4)
+		So(isExistsFilter.ItemID, ShouldEqual, 3)
+	})
+}
+
+func TestUpdateItemIsItemExistsChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := NewMockItemManager()
+
+	Convey("TEST itemManager.Get fail", t, func() {
+		mockItemManager.MGet = func(filter ItemFilter) (Item, error) {
+			return Item{}, errs.InternalServerErr
+		}
+
+		_, err := updateItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 1, 3)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST item not found error", t, func() {
+		mockItemManager.MGet = func(filter ItemFilter) (Item, error) {
+			return Item{}, gorm.ErrRecordNotFound
+		}
+
+		_, err := updateItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 1, 3)
+		So(err, ShouldEqual, errs.RecordNotFoundErr)
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		mockItemManager.MGet = func(filter ItemFilter) (Item, error) {
+			return Item{CartID: filter.CartID, ItemID: filter.ItemID, Quantity: 2}, nil
+		}
+
+		item, err := updateItemIsItemExistsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 1, 3)
+		So(err, ShouldBeNil)
+		So(item.ItemID, ShouldEqual, 3)
+		So(item.Quantity, ShouldEqual, 2)
+	})
+}
+
+func TestUpdateItemQuantityChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockItemManager := NewMockItemManager()
+
+	Convey("TEST decreasing the quantity skips the limit checks", t, func() {
+		mockItemManager.MGetTotalItemCount = func(filter ItemFilter) (uint, error) {
+			return 0, errs.InternalServerErr
+		}
+
+		err := updateItemQuantityChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 500000*money.Unit, Item{ItemID: 3, Price: money.Unit, Quantity: 5}, 2)
+		So(err, ShouldBeNil)
+	})
+
+	Convey("TEST total price exceeds limit error with the increased quantity", t, func() {
+		err := updateItemQuantityChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 400000*money.Unit, Item{ItemID: 3, Price: 50000 * money.Unit, Quantity: 1}, 4)
+		So(err, ShouldEqual, fmt.Errorf("total price of cart cannot be over %s", testRules.MaxPriceOfCart))
+	})
+
+	Convey("TEST number of digital items exceeds limit error with the increased quantity", t, func() {
+		mockItemManager.MIsExists = func(filter ItemFilter) (bool, error) {
+			return false, nil
+		}
+
+		mockItemManager.MGetTotalItemCount = func(filter ItemFilter) (uint, error) {
+			return 4, nil
+		}
+
+		err := updateItemQuantityChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 0, Item{ItemID: 3, CategoryID: DIGITAL_ITEM_CATEGORY_ID, Quantity: 2}, 4)
+		So(err, ShouldEqual, fmt.Errorf("total number of digital items cannot be over %d", testRules.MaxDigitalItems))
+	})
+
+	Convey("TEST succeed without counting the item itself as a new unique item", t, func() {
+		var uniqueCountFilter ItemFilter
+		mockItemManager.MGetTotalItemCount = func(filter ItemFilter) (uint, error) {
+			return 20, nil
+		}
+
+		mockItemManager.MGetUniqueItemCount = func(filter ItemFilter) (int64, error) {
+			uniqueCountFilter = filter
+			return 9, nil
+		}
+
+		err := updateItemQuantityChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1000*money.Unit, Item{CartID: 1, ItemID: 3, Price: 10 * money.Unit, Quantity: 2}, 5)
 		So(err, ShouldBeNil)
+		So(uniqueCountFilter.ItemIDNot, ShouldEqual, 3)
 	})
 }
 
@@ -299,7 +584,7 @@ func TestAddVasItemIsVasItemExistsInItemChecks(t *testing.T) {
 			return false, errs.InternalServerErr
 		}
 
-		err := addVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 2, 3)
+		err := addVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 1, 2, 3)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -308,7 +593,7 @@ func TestAddVasItemIsVasItemExistsInItemChecks(t *testing.T) {
 			return true, nil
 		}
 
-		err := addVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 2, 3)
+		err := addVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 1, 2, 3)
 		So(err, ShouldEqual, fmt.Errorf("item already has this vas-item, cannot add same vas-item multiple times to a single item"))
 	})
 
@@ -317,11 +602,49 @@ func TestAddVasItemIsVasItemExistsInItemChecks(t *testing.T) {
 			return false, nil
 		}
 
-		err := addVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 2, 3)
+		err := addVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 1, 2, 3)
 		So(err, ShouldEqual, nil)
 	})
 }
 
+func TestRemoveVasItemIsVasItemExistsInItemChecks(t *testing.T) {
+	log, err := logger.Initialize()
+	if err != nil {
+		t.Fail()
+	}
+	mockVasItemManager := NewMockVasItemManager()
+
+	Convey("TEST vasItemManager.isExistsInItem fail", t, func() {
+		mockVasItemManager.MIsExistsInItem = func(filter ItemVasItemFilter) (bool, error) {
+			return false, errs.InternalServerErr
+		}
+
+		err := removeVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 1, 2, 3)
+		So(err, ShouldEqual, errs.InternalServerErr)
+	})
+
+	Convey("TEST vas item not found in item error", t, func() {
+		mockVasItemManager.MIsExistsInItem = func(filter ItemVasItemFilter) (bool, error) {
+			return false, nil
+		}
+
+		err := removeVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 1, 2, 3)
+		So(err, ShouldEqual, errs.RecordNotFoundErr)
+	})
+
+	Convey("TEST succeed without error", t, func() {
+		var isExistsInItemFilter ItemVasItemFilter
+		mockVasItemManager.MIsExistsInItem = func(filter ItemVasItemFilter) (bool, error) {
+			isExistsInItemFilter = filter
+			return true, nil
+		}
+
+		err := removeVasItemIsVasItemExistsInItemChecks(mockVasItemManager, log.WithFields(logrus.Fields{}), 1, 2, 3)
+		So(err, ShouldBeNil)
+		So(isExistsInItemFilter, ShouldResemble, ItemVasItemFilter{CartID: 1, VasItemID: 2, ItemID: 3})
+	})
+}
+
 func TestAddVasItemCategoryAndSellerChecks(t *testing.T) {
 	log, err := logger.Initialize()
 	if err != nil {
@@ -329,17 +652,17 @@ func TestAddVasItemCategoryAndSellerChecks(t *testing.T) {
 	}
 
 	Convey("TEST category_id error", t, func() {
-		err := addVasItemCategoryAndSellerChecks(log.WithFields(logrus.Fields{}), 2, 3)
+		err := addVasItemCategoryAndSellerChecks(log.WithFields(logrus.Fields{}), testRules, 2, 3)
 		So(err, ShouldEqual, fmt.Errorf("cannot add vas-item with category id 2"))
 	})
 
 	Convey("TEST seller_id error", t, func() {
-		err := addVasItemCategoryAndSellerChecks(log.WithFields(logrus.Fields{}), VAS_ITEM_CATEGORY_ID, 3)
+		err := addVasItemCategoryAndSellerChecks(log.WithFields(logrus.Fields{}), testRules, VAS_ITEM_CATEGORY_ID, 3)
 		So(err, ShouldEqual, fmt.Errorf("cannot add vas-item with seller id 3"))
 	})
 
 	Convey("TEST succeed withour error", t, func() {
-		err := addVasItemCategoryAndSellerChecks(log.WithFields(logrus.Fields{}), VAS_ITEM_CATEGORY_ID, VAS_ITEM_SELLER_ID)
+		err := addVasItemCategoryAndSellerChecks(log.WithFields(logrus.Fields{}), testRules, VAS_ITEM_CATEGORY_ID, testRules.VasItemSellerID)
 		So(err, ShouldEqual, nil)
 	})
 }
@@ -357,7 +680,7 @@ func TestAddVasItemIsItemExistsAndSuitableChecks(t *testing.T) {
 			return Item{}, errs.InternalServerErr
 		}
 
-		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2)
+		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -366,7 +689,7 @@ func TestAddVasItemIsItemExistsAndSuitableChecks(t *testing.T) {
 			return Item{}, gorm.ErrRecordNotFound
 		}
 
-		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2)
+		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2)
 		So(err, ShouldEqual, fmt.Errorf("cannot add vas-item, item 2 does not exist"))
 	})
 
@@ -375,18 +698,30 @@ func TestAddVasItemIsItemExistsAndSuitableChecks(t *testing.T) {
 			return Item{ItemID: 2, CategoryID: 2}, nil
 		}
 
-		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2)
+		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2)
 		So(err, ShouldEqual, fmt.Errorf("item category is not suitable to add vas-items"))
 	})
 
 	Convey("TEST succeed without error", t, func() {
 		mockItemManager.MGet = func(filter ItemFilter) (Item, error) {
-			return Item{ItemID: 2, CategoryID: FURNITIRE_CATEGORY_ID}, nil
+			return Item{ItemID: 2, CategoryID: 1001}, nil
 		}
 
-		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2)
+		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2)
 		So(err, ShouldEqual, nil)
 	})
+
+	Convey("TEST item category is not in configured categories error", t, func() {
+		mockItemManager.MGet = func(filter ItemFilter) (Item, error) {
+			return Item{ItemID: 2, CategoryID: 1001}, nil
+		}
+
+		rules := env.DefaultRules()
+		rules.VasItemEligibleCategoryIDs = []uint{3004}
+
+		_, err := addVasItemIsItemExistsAndSuitableChecks(mockItemManager, log.WithFields(logrus.Fields{}), rules, 1, 2)
+		So(err, ShouldEqual, fmt.Errorf("item category is not suitable to add vas-items"))
+	})
 }
 
 func TestAddVasItemNumberOfVasItemsChecks(t *testing.T) {
@@ -402,7 +737,7 @@ func TestAddVasItemNumberOfVasItemsChecks(t *testing.T) {
 			return 0, errs.InternalServerErr
 		}
 
-		err := addVasItemNumberOfVasItemsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 2)
+		err := addVasItemNumberOfVasItemsChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2, 2)
 		So(err, ShouldEqual, errs.InternalServerErr)
 	})
 
@@ -411,8 +746,8 @@ func TestAddVasItemNumberOfVasItemsChecks(t *testing.T) {
 			return 2, nil
 		}
 
-		err := addVasItemNumberOfVasItemsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 2)
-		So(err, ShouldEqual, fmt.Errorf("item 2 has already 2 vas-items, cannot add more than %d vas-items to the same item", MAX_VAS_ITEM_ON_SINGLE_ITEM))
+		err := addVasItemNumberOfVasItemsChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2, 2)
+		So(err, ShouldEqual, fmt.Errorf("item 2 has already 2 vas-items, cannot add more than %d vas-items to the same item", testRules.MaxVasItemOnSingleItem))
 	})
 
 	Convey("TEST succeed without error", t, func() {
@@ -420,7 +755,7 @@ func TestAddVasItemNumberOfVasItemsChecks(t *testing.T) {
 			return 2, nil
 		}
 
-		err := addVasItemNumberOfVasItemsChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 1)
+		err := addVasItemNumberOfVasItemsChecks(mockItemManager, log.WithFields(logrus.Fields{}), testRules, 1, 2, 1)
 		So(err, ShouldEqual, nil)
 	})
 }
@@ -431,50 +766,23 @@ func TestAddVasItemPriceChecks(t *testing.T) {
 		t.Fail()
 	}
 
-	mockItemManager := NewMockItemManager()
-
-	Convey("TEST itemManager.Get fail", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 0, errs.InternalServerErr
-		}
-
-		err := addVasItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 300, 500)
-		So(err, ShouldEqual, errs.InternalServerErr)
-	})
-
 	Convey("TEST cart total price exceeds limit error", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 400000, nil
-		}
-
-		err := addVasItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 150000, 160000)
-		So(err, ShouldEqual, fmt.Errorf("total price of the cart cannot be ovwer %.2f", MAX_PRICE_OF_CART))
+		err := addVasItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, 400000*money.Unit, 2, 150000*money.Unit, 160000*money.Unit)
+		So(err, ShouldEqual, fmt.Errorf("total price of the cart cannot be ovwer %s", testRules.MaxPriceOfCart))
 	})
 
 	Convey("TEST cart total price exceeds limit error", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 400000, nil
-		}
-
-		err := addVasItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 150000, 160000)
-		So(err, ShouldEqual, fmt.Errorf("total price of the cart cannot be ovwer %.2f", MAX_PRICE_OF_CART))
+		err := addVasItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, 400000*money.Unit, 2, 150000*money.Unit, 160000*money.Unit)
+		So(err, ShouldEqual, fmt.Errorf("total price of the cart cannot be ovwer %s", testRules.MaxPriceOfCart))
 	})
 
 	Convey("TEST vas items price bigger than items price error", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 100000, nil
-		}
-
-		err := addVasItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 10, 5)
+		err := addVasItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, 100000*money.Unit, 2, 10*money.Unit, 5*money.Unit)
 		So(err, ShouldEqual, fmt.Errorf("error, sinlge vas-item's price cannot be more than single item's price"))
 	})
 
 	Convey("TEST succeed without error", t, func() {
-		mockItemManager.MGetTotalPrice = func() (float64, error) {
-			return 100000, nil
-		}
-
-		err := addVasItemPriceChecks(mockItemManager, log.WithFields(logrus.Fields{}), 2, 4, 5)
+		err := addVasItemPriceChecks(log.WithFields(logrus.Fields{}), testRules, 100000*money.Unit, 2, 4*money.Unit, 5*money.Unit)
 		So(err, ShouldBeNil)
 	})
 }
@@ -3,8 +3,8 @@ package integration_tests
 import (
 	"checkoutProject/pkg/bootstrap"
 	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/env"
 	"checkoutProject/pkg/common/testhelper"
-	itm "checkoutProject/pkg/handlers/item"
 	"encoding/json"
 	"fmt"
 	"github.com/appleboy/gofight/v2"
@@ -16,10 +16,8 @@ import (
 
 type addItemTest struct {
 	Name                    string
+	CartID                  uint
 	ItemID                  uint
-	CategoryID              uint
-	SellerID                uint
-	Price                   float64
 	Quantity                uint
 	ExpectedResponseResult  bool
 	ExpectedResponseMessage string
@@ -30,10 +28,8 @@ func TestAddItemForCartWithDefaultItems(t *testing.T) {
 	tests := []addItemTest{
 		{
 			Name:                    "server should return 400 if item already exists",
+			CartID:                  1,
 			ItemID:                  1,
-			CategoryID:              1,
-			SellerID:                1,
-			Price:                   16.34,
 			Quantity:                3,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "item with ID 1 already exists. Please choose a different item ID",
@@ -41,10 +37,8 @@ func TestAddItemForCartWithDefaultItems(t *testing.T) {
 		},
 		{
 			Name:                    "server should return 400 if client tries to add digital item to cart with default items",
+			CartID:                  1,
 			ItemID:                  100,
-			CategoryID:              itm.DIGITAL_ITEM_CATEGORY_ID,
-			SellerID:                1,
-			Price:                   10000,
 			Quantity:                3,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "cannot add a digital item if default item exists in cart",
@@ -52,46 +46,56 @@ func TestAddItemForCartWithDefaultItems(t *testing.T) {
 		},
 		{
 			Name:                    "server should return 400 if client tries to add items that make the carts total price bigger than the limit",
+			CartID:                  1,
 			ItemID:                  101,
-			CategoryID:              10,
-			SellerID:                1,
-			Price:                   25000,
 			Quantity:                2,
 			ExpectedResponseResult:  false,
-			ExpectedResponseMessage: fmt.Sprintf("total price of cart cannot be over %.2f", itm.MAX_PRICE_OF_CART),
+			ExpectedResponseMessage: fmt.Sprintf("total price of cart cannot be over %s", env.RULES.MaxPriceOfCart),
 			WantCode:                http.StatusBadRequest,
 		},
 		{
 			Name:                    "server should return 400 if client tries to add more than 30 items in cart",
+			CartID:                  1,
 			ItemID:                  102,
-			CategoryID:              1,
-			SellerID:                1,
-			Price:                   50.7,
 			Quantity:                8,
 			ExpectedResponseResult:  false,
-			ExpectedResponseMessage: fmt.Sprintf("total number of items cannot be over %d", itm.MAX_DEFAULT_ITEMS),
+			ExpectedResponseMessage: fmt.Sprintf("total number of items cannot be over %d", env.RULES.MaxDefaultItems),
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "server should return 400 if the item is not in the catalog",
+			CartID:                  1,
+			ItemID:                  999,
+			Quantity:                1,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "product 999 is not in the catalog",
 			WantCode:                http.StatusBadRequest,
 		},
 		{
 			Name:                    "server should return 201 if item added successfully",
+			CartID:                  1,
 			ItemID:                  103,
-			CategoryID:              1,
-			SellerID:                1,
-			Price:                   10000,
 			Quantity:                1,
 			ExpectedResponseResult:  true,
 			ExpectedResponseMessage: "item added successfully",
 			WantCode:                http.StatusCreated,
 		},
+		{
+			Name:                    "server should return 201 if an item with an existing ID is added to another cart",
+			CartID:                  2,
+			ItemID:                  1,
+			Quantity:                3,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "item added successfully",
+			WantCode:                http.StatusCreated,
+		},
 		{
 			Name:                    "server should return 400 if client tries to add more than 10 unique items in cart",
+			CartID:                  1,
 			ItemID:                  104,
-			CategoryID:              1,
-			SellerID:                1,
-			Price:                   10000,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
-			ExpectedResponseMessage: fmt.Sprintf("total number of unique items cannot be over %d", itm.MAX_UNIQUE_ITEMS),
+			ExpectedResponseMessage: fmt.Sprintf("total number of unique items cannot be over %d", env.RULES.MaxUniqueItems),
 			WantCode:                http.StatusBadRequest,
 		},
 	}
@@ -115,10 +119,8 @@ func TestAddItemForCartWithDigitalItems(t *testing.T) {
 	tests := []addItemTest{
 		{
 			Name:                    "server should return 400 if client try to add default item to the cart with digital item(s).",
+			CartID:                  1,
 			ItemID:                  10,
-			CategoryID:              1,
-			SellerID:                1,
-			Price:                   16.34,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "cannot add a default item if digital item exists in cart",
@@ -126,21 +128,17 @@ func TestAddItemForCartWithDigitalItems(t *testing.T) {
 		},
 		{
 			Name:                    "server should return 400 if client try to add more than 5 digital items to cart.",
+			CartID:                  1,
 			ItemID:                  20,
-			CategoryID:              itm.DIGITAL_ITEM_CATEGORY_ID,
-			SellerID:                1,
-			Price:                   16.34,
 			Quantity:                2,
 			ExpectedResponseResult:  false,
-			ExpectedResponseMessage: fmt.Sprintf("total number of digital items cannot be over %d", itm.MAX_DIGITAL_ITEMS),
+			ExpectedResponseMessage: fmt.Sprintf("total number of digital items cannot be over %d", env.RULES.MaxDigitalItems),
 			WantCode:                http.StatusBadRequest,
 		},
 		{
 			Name:                    "server should return 201 if item added successfully.",
+			CartID:                  1,
 			ItemID:                  11,
-			CategoryID:              itm.DIGITAL_ITEM_CATEGORY_ID,
-			SellerID:                56,
-			Price:                   10000,
 			Quantity:                1,
 			ExpectedResponseResult:  true,
 			ExpectedResponseMessage: fmt.Sprintf("item added successfully"),
@@ -150,16 +148,15 @@ func TestAddItemForCartWithDigitalItems(t *testing.T) {
 		//I added some detailed/rare test cases here to avoid extending the code. (not the best practices)
 		{
 			Name:                    "server should return 400 and give details about missing fields.",
+			CartID:                  1,
 			ExpectedResponseResult:  false,
-			ExpectedResponseMessage: "{\"CategoryID\":\"This field is required\",\"ItemID\":\"This field is required\",\"Price\":\"This field is required\",\"Quantity\":\"This field is required\",\"SellerID\":\"This field is required\"}",
+			ExpectedResponseMessage: "{\"ItemID\":\"This field is required\",\"Quantity\":\"This field is required\"}",
 			WantCode:                http.StatusBadRequest,
 		},
 		{
 			Name:                    "server should return 400 and give details about failed min-max binding checks",
+			CartID:                  1,
 			ItemID:                  15,
-			CategoryID:              itm.DIGITAL_ITEM_CATEGORY_ID,
-			SellerID:                56,
-			Price:                   10000,
 			Quantity:                63,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "{\"Quantity\":\"This fields maximum value is 10\"}",
@@ -187,13 +184,11 @@ func runAddItemTestCase(t *testing.T, tt addItemTest, r *gin.Engine) {
 
 	t.Run(tt.Name, func(t *testing.T) {
 		gofight.New().
-			POST("/api/cart/items").
+			POST(fmt.Sprintf("/api/carts/%d/items", tt.CartID)).
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
 			SetJSON(gofight.D{
-				"item_id":     tt.ItemID,
-				"category_id": tt.CategoryID,
-				"seller_id":   tt.SellerID,
-				"price":       tt.Price,
-				"quantity":    tt.Quantity,
+				"item_id":  tt.ItemID,
+				"quantity": tt.Quantity,
 			}).
 			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
 				response = r
@@ -217,10 +212,13 @@ func runAddItemTestCase(t *testing.T, tt addItemTest, r *gin.Engine) {
 			})
 
 			if response.Code == http.StatusCreated {
-				Convey(fmt.Sprintf("Then item must be created in test db if operation is successful"), func() {
+				Convey(fmt.Sprintf("Then item must be created with the price, category and seller of the catalog in test db if operation is successful"), func() {
 
 					var count int64
-					err := TestDB.Table("items").Where("items.item_id = ?", tt.ItemID).Count(&count).Error
+					err := TestDB.Table("items").Joins("JOIN products ON products.product_id = items.item_id AND products.deleted_at IS NULL").
+						Where("items.item_id = ? AND items.cart_id = ?", tt.ItemID, tt.CartID).
+						Where("items.price = products.price AND items.category_id = products.category_id AND items.seller_id = products.seller_id").
+						Count(&count).Error
 					So(err, ShouldBeNil)
 					So(count, ShouldEqual, 1)
 				})
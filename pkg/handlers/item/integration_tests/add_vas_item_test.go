@@ -17,9 +17,6 @@ type addVasItemTest struct {
 	Name                    string
 	VasItemID               uint
 	ItemID                  uint
-	CategoryID              uint
-	SellerID                uint
-	Price                   float64
 	Quantity                uint
 	ExpectedResponseResult  bool
 	ExpectedResponseMessage string
@@ -32,9 +29,6 @@ func TestAddVasItem(t *testing.T) {
 			Name:                    "server should return 400 if item_vas_item already exists",
 			ItemID:                  1,
 			VasItemID:               1,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   16.34,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "item already has this vas-item, cannot add same vas-item multiple times to a single item",
@@ -43,10 +37,7 @@ func TestAddVasItem(t *testing.T) {
 		{
 			Name:                    "server should return 400 category_id is not true",
 			ItemID:                  2,
-			VasItemID:               2,
-			CategoryID:              5,
-			SellerID:                5003,
-			Price:                   16.34,
+			VasItemID:               20,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "cannot add vas-item with category id 5",
@@ -55,10 +46,7 @@ func TestAddVasItem(t *testing.T) {
 		{
 			Name:                    "server should return 400 if seller_id is not true",
 			ItemID:                  2,
-			VasItemID:               2,
-			CategoryID:              3242,
-			SellerID:                3,
-			Price:                   16.34,
+			VasItemID:               21,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "cannot add vas-item with seller id 3",
@@ -68,9 +56,6 @@ func TestAddVasItem(t *testing.T) {
 			Name:                    "server should return 400 if default item does not exists",
 			ItemID:                  9999,
 			VasItemID:               2,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   16.34,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "cannot add vas-item, item 9999 does not exist",
@@ -80,9 +65,6 @@ func TestAddVasItem(t *testing.T) {
 			Name:                    "server should return 400 if item category is not suitable to add vas-items",
 			ItemID:                  3,
 			VasItemID:               2,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   16.34,
 			Quantity:                1,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "item category is not suitable to add vas-items",
@@ -92,9 +74,6 @@ func TestAddVasItem(t *testing.T) {
 			Name:                    "server should return 400 if number of vas-items on single item limit exceeded",
 			ItemID:                  4,
 			VasItemID:               3,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   16.34,
 			Quantity:                2,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "item 4 has already 2 vas-items, cannot add more than 3 vas-items to the same item",
@@ -104,33 +83,33 @@ func TestAddVasItem(t *testing.T) {
 			Name:                    "server should return 400 if the price of single vas-item is greater than the default item's price",
 			ItemID:                  5,
 			VasItemID:               7,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   16.34,
 			Quantity:                2,
 			ExpectedResponseResult:  false,
 			ExpectedResponseMessage: "error, sinlge vas-item's price cannot be more than single item's price",
 			WantCode:                http.StatusBadRequest,
 		},
 		{
-			Name:                    "server should return 201 and create new vas-item and item_vas_item",
+			Name:                    "server should return 400 if the vas-item is not in the catalog",
+			ItemID:                  6,
+			VasItemID:               999,
+			Quantity:                1,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "product 999 is not in the catalog",
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "server should return 201 and create new item_vas_item",
 			ItemID:                  6,
 			VasItemID:               10,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   5,
 			Quantity:                2,
 			ExpectedResponseResult:  true,
 			ExpectedResponseMessage: "vas-item added successfully",
 			WantCode:                http.StatusCreated,
 		},
 		{
-			Name:                    "server should return 201 and not create new vas-item since it existed before but create new item_vas_item",
+			Name:                    "server should return 201 and keep the price and quantity of the vas-item on each item",
 			ItemID:                  5,
 			VasItemID:               2,
-			CategoryID:              3242,
-			SellerID:                5003,
-			Price:                   3.6,
 			Quantity:                2,
 			ExpectedResponseResult:  true,
 			ExpectedResponseMessage: "vas-item added successfully",
@@ -158,12 +137,10 @@ func runAddVasItemTestCase(t *testing.T, tt addVasItemTest, r *gin.Engine) {
 
 	t.Run(tt.Name, func(t *testing.T) {
 		gofight.New().
-			POST(fmt.Sprintf("/api/cart/items/%d/vas-items", tt.ItemID)).
+			POST(fmt.Sprintf("/api/carts/%d/items/%d/vas-items", testCartID, tt.ItemID)).
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
 			SetJSON(gofight.D{
 				"vas_item_id": tt.VasItemID,
-				"category_id": tt.CategoryID,
-				"seller_id":   tt.SellerID,
-				"price":       tt.Price,
 				"quantity":    tt.Quantity,
 			}).
 			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
@@ -188,16 +165,19 @@ func runAddVasItemTestCase(t *testing.T, tt addVasItemTest, r *gin.Engine) {
 			})
 
 			if response.Code == http.StatusCreated {
-				Convey(fmt.Sprintf("Then vas-item must be created or exist before in test db if operation is successful"), func() {
+				Convey(fmt.Sprintf("Then item_vas_item must be created with the price of the catalog in test db if operation is successful"), func() {
 					var count int64
-					err := TestDB.Table("vas_items").Where("vas_items.vas_item_id = ?", tt.VasItemID).Count(&count).Error
+					err := TestDB.Table("item_vas_items").Joins("JOIN products ON products.product_id = item_vas_items.vas_item_id AND products.deleted_at IS NULL").
+						Where("item_vas_items.vas_item_id = ? AND item_vas_items.item_id = ? AND item_vas_items.cart_id = ?", tt.VasItemID, tt.ItemID, testCartID).
+						Where("item_vas_items.price = products.price AND item_vas_items.quantity = ?", tt.Quantity).Count(&count).Error
 					So(err, ShouldBeNil)
 					So(count, ShouldEqual, 1)
 				})
 
-				Convey(fmt.Sprintf("Then item_vas_item must be created in test db if operation is successful"), func() {
+				Convey(fmt.Sprintf("Then the same vas-item on other items must keep its own price and quantity"), func() {
 					var count int64
-					err := TestDB.Table("item_vas_items").Where("item_vas_items.vas_item_id = ? AND item_vas_items.item_id = ?", tt.VasItemID, tt.ItemID).Count(&count).Error
+					err := TestDB.Table("item_vas_items").Where("item_vas_items.vas_item_id = ? AND item_vas_items.item_id = ? AND item_vas_items.cart_id = ?", 2, 4, testCartID).
+						Where("item_vas_items.price = ? AND item_vas_items.quantity = ?", 30.50, 2).Count(&count).Error
 					So(err, ShouldBeNil)
 					So(count, ShouldEqual, 1)
 				})
@@ -0,0 +1,130 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/common/testhelper"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+const concurrentTestCartID = 900
+
+type concurrentAddItemRequest struct {
+	ItemID   uint
+	Quantity uint
+}
+
+// sendConcurrently sends all add item requests at the same time and returns the number of created items.
+func sendConcurrently(r *gin.Engine, requests []concurrentAddItemRequest) int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+
+	start := make(chan struct{})
+	for _, request := range requests {
+		wg.Add(1)
+		go func(request concurrentAddItemRequest) {
+			defer wg.Done()
+			<-start
+
+			gofight.New().
+				POST(fmt.Sprintf("/api/carts/%d/items", concurrentTestCartID)).
+				SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+				SetJSON(gofight.D{
+					"item_id":  request.ItemID,
+					"quantity": request.Quantity,
+				}).
+				Run(r, func(r gofight.HTTPResponse, rq gofight.HTTPRequest) {
+					if r.Code == http.StatusCreated {
+						mu.Lock()
+						created++
+						mu.Unlock()
+					}
+				})
+		}(request)
+	}
+
+	close(start)
+	wg.Wait()
+	return created
+}
+
+func TestConcurrentAddItem(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	r := bootstrap.SetupRouter()
+
+	Convey("When clients add the same item to a cart at the same time", t, func() {
+		testhelper.LoadFixtures(testhelper.DefaultItemsFixturePath, t, db)
+
+		requests := make([]concurrentAddItemRequest, 8)
+		for i := range requests {
+			requests[i] = concurrentAddItemRequest{ItemID: 1, Quantity: 1}
+		}
+
+		created := sendConcurrently(r, requests)
+
+		Convey("Then the item should be created only once", func() {
+			So(created, ShouldEqual, 1)
+
+			var count int64
+			err := TestDB.Table("items").Where("cart_id = ? AND deleted_at IS NULL", concurrentTestCartID).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When clients add more unique items than allowed to a cart at the same time", t, func() {
+		testhelper.LoadFixtures(testhelper.DefaultItemsFixturePath, t, db)
+
+		requests := make([]concurrentAddItemRequest, env.RULES.MaxUniqueItems+5)
+		for i := range requests {
+			requests[i] = concurrentAddItemRequest{ItemID: uint(i + 1), Quantity: 1}
+		}
+
+		created := sendConcurrently(r, requests)
+
+		Convey("Then only the allowed number of unique items should be created", func() {
+			So(created, ShouldEqual, env.RULES.MaxUniqueItems)
+
+			var count int64
+			err := TestDB.Table("items").Where("cart_id = ? AND deleted_at IS NULL", concurrentTestCartID).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, env.RULES.MaxUniqueItems)
+		})
+	})
+
+	Convey("When clients add items over the price limit to a cart at the same time", t, func() {
+		testhelper.LoadFixtures(testhelper.DefaultItemsFixturePath, t, db)
+
+		// every request is allowed alone, but only two of them fit into the cart together, the products 201-205 of the
+		// catalog cost two fifths of the limit
+		requests := make([]concurrentAddItemRequest, 5)
+		for i := range requests {
+			requests[i] = concurrentAddItemRequest{ItemID: uint(i + 201), Quantity: 1}
+		}
+
+		created := sendConcurrently(r, requests)
+
+		Convey("Then the total price of the cart should not exceed the limit", func() {
+			So(created, ShouldEqual, 2)
+
+			var totalPrice money.Amount
+			err := TestDB.Table("items").Select("COALESCE(SUM(price * quantity), 0)").
+				Where("cart_id = ? AND deleted_at IS NULL", concurrentTestCartID).Scan(&totalPrice).Error
+			So(err, ShouldBeNil)
+			So(totalPrice, ShouldBeLessThanOrEqualTo, env.RULES.MaxPriceOfCart)
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/item"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorm.io/gorm"
+	"testing"
+)
+
+func TestDatabaseConstraints(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.AddVasItemFixturesPath, t, db)
+
+	Convey("When an item is inserted twice to the same cart", t, func() {
+		err := TestDB.Create(&item.Item{CartID: testCartID, ItemID: 1, CategoryID: 1001, SellerID: 1, Price: money.Unit, Quantity: 1}).Error
+
+		Convey("Then database should return a duplicated key error", func() {
+			So(err, ShouldEqual, gorm.ErrDuplicatedKey)
+		})
+	})
+
+	Convey("When a vas-item is inserted twice to the same item", t, func() {
+		err := TestDB.Create(&item.ItemVasItem{CartID: testCartID, ItemID: 1, VasItemID: 1, Price: money.Unit, Quantity: 1}).Error
+
+		Convey("Then database should return a duplicated key error", func() {
+			So(err, ShouldEqual, gorm.ErrDuplicatedKey)
+		})
+	})
+
+	Convey("When a soft-deleted vas-item is added to the item again", t, func() {
+		err := TestDB.Where("cart_id = ? AND item_id = ? AND vas_item_id = ?", testCartID, 4, 2).Delete(&item.ItemVasItem{}).Error
+		So(err, ShouldBeNil)
+
+		err = TestDB.Create(&item.ItemVasItem{CartID: testCartID, ItemID: 4, VasItemID: 2, Price: money.Unit, Quantity: 1}).Error
+
+		Convey("Then database should accept it", func() {
+			So(err, ShouldBeNil)
+		})
+	})
+
+	Convey("When an item is inserted with a negative price or without quantity", t, func() {
+		negativePriceErr := TestDB.Create(&item.Item{CartID: testCartID, ItemID: 100, CategoryID: 1001, SellerID: 1, Price: -money.Unit, Quantity: 1}).Error
+		noQuantityErr := TestDB.Create(&item.Item{CartID: testCartID, ItemID: 101, CategoryID: 1001, SellerID: 1, Price: money.Unit}).Error
+
+		Convey("Then database should reject it", func() {
+			So(negativePriceErr, ShouldNotBeNil)
+			So(noQuantityErr, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When a vas-item is inserted for an item that is not in the cart", t, func() {
+		err := TestDB.Create(&item.ItemVasItem{CartID: testCartID, ItemID: 999, VasItemID: 1, Price: money.Unit, Quantity: 1}).Error
+
+		Convey("Then database should reject it", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When an item is removed without its vas-items", t, func() {
+		err := TestDB.Where("cart_id = ? AND item_id = ?", testCartID, 1).Delete(&item.Item{}).Error
+
+		Convey("Then database should reject it", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("When an item is removed together with its vas-items", t, func() {
+		err := TestDB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("cart_id = ? AND item_id = ?", testCartID, 1).Delete(&item.ItemVasItem{}).Error; err != nil {
+				return err
+			}
+
+			return tx.Where("cart_id = ? AND item_id = ?", testCartID, 1).Delete(&item.Item{}).Error
+		})
+
+		Convey("Then database should accept it", func() {
+			So(err, ShouldBeNil)
+		})
+	})
+}
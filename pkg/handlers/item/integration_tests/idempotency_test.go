@@ -0,0 +1,112 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/idempotency"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+func sendWithIdempotencyKey(r *gin.Engine, path string, key string, body gofight.D) gofight.HTTPResponse {
+	var response gofight.HTTPResponse
+
+	header := testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)
+	header[idempotency.IDEMPOTENCY_KEY_HEADER] = key
+
+	gofight.New().
+		POST(path).
+		SetHeader(header).
+		SetJSON(body).
+		Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+			response = r
+		})
+
+	return response
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.IdempotencyFixturesPath, t, db)
+
+	r := bootstrap.SetupRouter()
+	itemsPath := fmt.Sprintf("/api/carts/%d/items", testCartID)
+	addItemBody := gofight.D{"item_id": 2, "quantity": 1}
+
+	Convey("When client retries adding an item with the same idempotency key", t, func() {
+		first := sendWithIdempotencyKey(r, itemsPath, "add-item-key", addItemBody)
+		retry := sendWithIdempotencyKey(r, itemsPath, "add-item-key", addItemBody)
+
+		Convey("Then server should return the response of the first request", func() {
+			So(first.Code, ShouldEqual, http.StatusCreated)
+			So(retry.Code, ShouldEqual, http.StatusCreated)
+			So(retry.Body.String(), ShouldEqual, first.Body.String())
+			So(retry.HeaderMap.Get(idempotency.IDEMPOTENT_REPLAYED_HEADER), ShouldEqual, "true")
+		})
+
+		Convey("Then item should be added once", func() {
+			var quantity uint
+			err := TestDB.Table("items").Select("quantity").Where("items.deleted_at IS NULL AND items.cart_id = ? AND items.item_id = ?", testCartID, 2).Row().Scan(&quantity)
+			So(err, ShouldBeNil)
+			So(quantity, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client reuses the idempotency key with a different body", t, func() {
+		response := sendWithIdempotencyKey(r, itemsPath, "add-item-key", gofight.D{"item_id": 3, "quantity": 1})
+
+		Convey("Then server should return 422", func() {
+			So(response.Code, ShouldEqual, http.StatusUnprocessableEntity)
+
+			var res apiresponse.GenericResponse
+			So(json.Unmarshal(response.Body.Bytes(), &res), ShouldBeNil)
+			So(res.Message, ShouldEqual, idempotency.KeyReusedErr.Error())
+		})
+	})
+
+	Convey("When client retries adding a vas-item with the same idempotency key", t, func() {
+		vasItemsPath := fmt.Sprintf("/api/carts/%d/items/%d/vas-items", testCartID, 1)
+		addVasItemBody := gofight.D{"vas_item_id": 20, "quantity": 1}
+
+		first := sendWithIdempotencyKey(r, vasItemsPath, "add-vas-item-key", addVasItemBody)
+		retry := sendWithIdempotencyKey(r, vasItemsPath, "add-vas-item-key", addVasItemBody)
+
+		Convey("Then server should return the response of the first request", func() {
+			So(first.Code, ShouldEqual, http.StatusCreated)
+			So(retry.Code, ShouldEqual, http.StatusCreated)
+			So(retry.Body.String(), ShouldEqual, first.Body.String())
+		})
+
+		Convey("Then vas-item should be attached once", func() {
+			var count int64
+			err := TestDB.Table("item_vas_items").Where("item_vas_items.deleted_at IS NULL AND item_vas_items.cart_id = ? AND item_vas_items.item_id = ? AND item_vas_items.vas_item_id = ?", testCartID, 1, 20).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+
+	Convey("When client sends a request with an expired idempotency key", t, func() {
+		response := sendWithIdempotencyKey(r, itemsPath, "expired-key", gofight.D{"item_id": 4, "quantity": 1})
+
+		Convey("Then server should handle the request again", func() {
+			So(response.Code, ShouldEqual, http.StatusCreated)
+			So(response.HeaderMap.Get(idempotency.IDEMPOTENT_REPLAYED_HEADER), ShouldBeEmpty)
+
+			var count int64
+			err := TestDB.Table("items").Where("items.deleted_at IS NULL AND items.cart_id = ? AND items.item_id = ?", testCartID, 4).Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
@@ -11,6 +11,8 @@ import (
 
 var TestDB *gorm.DB
 
+const testCartID = 1
+
 func TestMain(m *testing.M) {
 	err := bootstrap.Initialize()
 	if err != nil {
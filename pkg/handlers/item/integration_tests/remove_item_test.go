@@ -59,7 +59,8 @@ func runRemoveItemTestCase(t *testing.T, tt removeItemTest, r *gin.Engine) {
 
 	t.Run(tt.Name, func(t *testing.T) {
 		gofight.New().
-			DELETE(fmt.Sprintf("/api/cart/items/%d", tt.ItemID)).
+			DELETE(fmt.Sprintf("/api/carts/%d/items/%d", testCartID, tt.ItemID)).
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
 			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
 				response = r
 			})
@@ -83,14 +84,14 @@ func runRemoveItemTestCase(t *testing.T, tt removeItemTest, r *gin.Engine) {
 			if response.Code == http.StatusOK {
 				Convey(fmt.Sprintf("Then item must be deleted in test db if operation is successful"), func() {
 					var count int64
-					err := TestDB.Table("items").Where("items.item_id = ?", tt.ItemID).Where("items.deleted_at IS NULL").Count(&count).Error
+					err := TestDB.Table("items").Where("items.item_id = ? AND items.cart_id = ?", tt.ItemID, testCartID).Where("items.deleted_at IS NULL").Count(&count).Error
 					So(err, ShouldBeNil)
 					So(count, ShouldEqual, 0)
 				})
 
 				Convey(fmt.Sprintf("Then vas-items related with this items must be deleted if operation is successfull"), func() {
 					var count int64
-					err := TestDB.Table("item_vas_items").Where("item_vas_items.item_id = ?", tt.ItemID).Where("item_vas_items.deleted_at IS NULL").Count(&count).Error
+					err := TestDB.Table("item_vas_items").Where("item_vas_items.item_id = ? AND item_vas_items.cart_id = ?", tt.ItemID, testCartID).Where("item_vas_items.deleted_at IS NULL").Count(&count).Error
 					So(err, ShouldBeNil)
 					So(count, ShouldEqual, 0)
 				})
@@ -0,0 +1,116 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/testhelper"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+type removeVasItemTest struct {
+	Name                    string
+	ItemID                  uint
+	VasItemID               uint
+	ExpectedOtherItems      int64
+	ExpectedResponseResult  bool
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+func TestRemoveVasItem(t *testing.T) {
+	tests := []removeVasItemTest{
+		{
+			Name:                    "Server should return 404 if item does not have the vas-item.",
+			ItemID:                  2,
+			VasItemID:               1,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+		{
+			Name:                    "Server should return 200 and delete the vas-item of the item.",
+			ItemID:                  1,
+			VasItemID:               1,
+			ExpectedOtherItems:      0,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "vas-item removed successfully",
+			WantCode:                http.StatusOK,
+		},
+		{
+			Name:                    "Server should return 200 and keep the vas-item on the other items.",
+			ItemID:                  1,
+			VasItemID:               2,
+			ExpectedOtherItems:      1,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "vas-item removed successfully",
+			WantCode:                http.StatusOK,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.RemoveVasItemFixturesPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, test := range tests {
+		runRemoveVasItemTestCase(t, test, r)
+	}
+}
+
+func runRemoveVasItemTestCase(t *testing.T, tt removeVasItemTest, r *gin.Engine) {
+	var response gofight.HTTPResponse
+
+	t.Run(tt.Name, func(t *testing.T) {
+		gofight.New().
+			DELETE(fmt.Sprintf("/api/carts/%d/items/%d/vas-items/%d", testCartID, tt.ItemID, tt.VasItemID)).
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("When client sends a request to remove the vas-item from the given item", t, func() {
+			Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+				So(response.Code, ShouldEqual, tt.WantCode)
+			})
+
+			var res apiresponse.GenericResponse
+			err := json.Unmarshal(response.Body.Bytes(), &res)
+			So(err, ShouldBeNil)
+
+			Convey(fmt.Sprintf("Then response should have Result field equal to expected result value"), func() {
+				So(res.Result, ShouldEqual, tt.ExpectedResponseResult)
+			})
+
+			Convey(fmt.Sprintf("Then response should have Message field equal to expected result message"), func() {
+				So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+			})
+
+			if response.Code == http.StatusOK {
+				Convey(fmt.Sprintf("Then item_vas_item must be deleted in test db if operation is successful"), func() {
+					var count int64
+					err := TestDB.Table("item_vas_items").Where("item_vas_items.item_id = ? AND item_vas_items.vas_item_id = ? AND item_vas_items.cart_id = ?", tt.ItemID, tt.VasItemID, testCartID).Where("item_vas_items.deleted_at IS NULL").Count(&count).Error
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, 0)
+				})
+
+				Convey(fmt.Sprintf("Then the vas-item must stay on the other items"), func() {
+					var count int64
+					err := TestDB.Table("item_vas_items").Where("item_vas_items.vas_item_id = ? AND item_vas_items.item_id <> ? AND item_vas_items.cart_id = ?", tt.VasItemID, tt.ItemID, testCartID).Where("item_vas_items.deleted_at IS NULL").Count(&count).Error
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, tt.ExpectedOtherItems)
+				})
+			}
+		})
+	})
+}
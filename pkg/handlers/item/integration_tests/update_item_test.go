@@ -0,0 +1,203 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/testhelper"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+)
+
+type updateItemTest struct {
+	Name                    string
+	ItemID                  uint
+	Quantity                uint
+	ExpectedResponseResult  bool
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+func TestUpdateItemForCartWithDefaultItems(t *testing.T) {
+	tests := []updateItemTest{
+		{
+			Name:                    "server should return 404 if item does not exists in cart",
+			ItemID:                  99,
+			Quantity:                2,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+		{
+			Name:                    "server should return 400 if the new quantity makes the carts total price bigger than the limit",
+			ItemID:                  8,
+			Quantity:                2,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: fmt.Sprintf("total price of cart cannot be over %s", env.RULES.MaxPriceOfCart),
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "server should return 400 if the new quantity makes the cart have more than 30 items",
+			ItemID:                  1,
+			Quantity:                5,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: fmt.Sprintf("total number of items cannot be over %d", env.RULES.MaxDefaultItems),
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "server should return 200 if quantity increased successfully",
+			ItemID:                  3,
+			Quantity:                3,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "item updated successfully",
+			WantCode:                http.StatusOK,
+		},
+		{
+			Name:                    "server should return 200 if quantity decreased successfully",
+			ItemID:                  5,
+			Quantity:                1,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "item updated successfully",
+			WantCode:                http.StatusOK,
+		},
+		{
+			Name:                    "server should return 400 and give details about failed min-max binding checks",
+			ItemID:                  5,
+			Quantity:                11,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: "{\"Quantity\":\"This fields maximum value is 10\"}",
+			WantCode:                http.StatusBadRequest,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultItemsFixturePath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, test := range tests {
+		runUpdateItemTestCase(t, test, r)
+	}
+}
+
+func TestUpdateItemForCartWithDigitalItems(t *testing.T) {
+	tests := []updateItemTest{
+		{
+			Name:                    "server should return 400 if the new quantity makes the cart have more than 5 digital items",
+			ItemID:                  1,
+			Quantity:                3,
+			ExpectedResponseResult:  false,
+			ExpectedResponseMessage: fmt.Sprintf("total number of digital items cannot be over %d", env.RULES.MaxDigitalItems),
+			WantCode:                http.StatusBadRequest,
+		},
+		{
+			Name:                    "server should return 200 if digital items quantity updated successfully",
+			ItemID:                  1,
+			Quantity:                2,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "item updated successfully",
+			WantCode:                http.StatusOK,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DigitalItemFixturesPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, test := range tests {
+		runUpdateItemTestCase(t, test, r)
+	}
+}
+
+func TestUpdateItemKeepsVasItems(t *testing.T) {
+	tests := []updateItemTest{
+		{
+			Name:                    "server should return 200 and keep the vas-items of the item",
+			ItemID:                  1,
+			Quantity:                3,
+			ExpectedResponseResult:  true,
+			ExpectedResponseMessage: "item updated successfully",
+			WantCode:                http.StatusOK,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.AddVasItemFixturesPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, test := range tests {
+		runUpdateItemTestCase(t, test, r)
+
+		Convey("Then vas-items of the updated item must not be deleted", t, func() {
+			var count int64
+			err := TestDB.Table("item_vas_items").Where("item_vas_items.item_id = ? AND item_vas_items.cart_id = ?", test.ItemID, testCartID).Where("item_vas_items.deleted_at IS NULL").Count(&count).Error
+			So(err, ShouldBeNil)
+			So(count, ShouldEqual, 1)
+		})
+	}
+}
+
+func runUpdateItemTestCase(t *testing.T, tt updateItemTest, r *gin.Engine) {
+	var response gofight.HTTPResponse
+
+	t.Run(tt.Name, func(t *testing.T) {
+		gofight.New().
+			PATCH(fmt.Sprintf("/api/carts/%d/items/%d", testCartID, tt.ItemID)).
+			SetHeader(testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)).
+			SetJSON(gofight.D{
+				"quantity": tt.Quantity,
+			}).
+			Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+				response = r
+			})
+
+		Convey("When client sends a request to update the quantity of the given item", t, func() {
+			Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+				So(response.Code, ShouldEqual, tt.WantCode)
+			})
+
+			var res apiresponse.GenericResponse
+			err := json.Unmarshal(response.Body.Bytes(), &res)
+			So(err, ShouldBeNil)
+
+			Convey(fmt.Sprintf("Then response should have Result field equal to expected result value"), func() {
+				So(res.Result, ShouldEqual, tt.ExpectedResponseResult)
+			})
+
+			Convey(fmt.Sprintf("Then response should have Message field equal to expected result message"), func() {
+				So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+			})
+
+			if response.Code == http.StatusOK {
+				Convey(fmt.Sprintf("Then item quantity must be updated in test db if operation is successful"), func() {
+					var quantity uint
+					err := TestDB.Table("items").Select("quantity").Where("items.item_id = ? AND items.cart_id = ?", tt.ItemID, testCartID).Where("items.deleted_at IS NULL").Row().Scan(&quantity)
+					So(err, ShouldBeNil)
+					So(quantity, ShouldEqual, tt.Quantity)
+				})
+			}
+		})
+	})
+}
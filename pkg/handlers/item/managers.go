@@ -1,24 +1,32 @@
 package item
 
 import (
+	"checkoutProject/pkg/common/auth"
 	db "checkoutProject/pkg/common/database"
+	"checkoutProject/pkg/common/money"
+	"context"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ItemManager interface {
 	Create(item Item) (Item, error)
 	WithTx(tx *gorm.DB) ItemManager
+	WithContext(ctx context.Context) ItemManager
 	Get(filter ItemFilter) (Item, error)
 	Find(filter ItemFilter) ([]Item, error)
 	Delete(filter ItemFilter) error
+	UpdateQuantity(filter ItemFilter, quantity uint) error
 	IsExists(filter ItemFilter) (bool, error)
 	GetTotalItemCount(filter ItemFilter) (uint, error)
-	GetUniqueItemCount() (int64, error)
-	GetTotalPrice() (float64, error)
+	GetUniqueItemCount(filter ItemFilter) (int64, error)
+	GetTotalPrice(filter ItemFilter) (money.Amount, error)
 	GetTotalVasItemCount(filter ItemVasItemFilter) (uint, error)
-	DeleteVasItemsOfItem(itemID uint) error
-	AreAllItemsFromSameSeller() (bool, error)
-	DeleteAllItems() error
+	DeleteVasItemsOfItem(filter ItemVasItemFilter) error
+	DeleteAllItems(cartID uint) error
+	LockCart(cartID uint) error
+	ClaimCart(cartID uint) (bool, error)
+	FindCartContent(cartID uint) (CartContent, error)
 }
 
 type itemManager struct {
@@ -41,6 +49,12 @@ func (m itemManager) WithTx(tx *gorm.DB) ItemManager {
 	}
 }
 
+func (m itemManager) WithContext(ctx context.Context) ItemManager {
+	return itemManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
 func (m itemManager) Create(item Item) (Item, error) {
 
 	if err := m.DB.Create(&item).Error; err != nil {
@@ -61,6 +75,17 @@ func (m itemManager) Delete(filter ItemFilter) error {
 	return nil
 }
 
+func (m itemManager) UpdateQuantity(filter ItemFilter, quantity uint) error {
+
+	query := filter.ToQuery(m.DB)
+
+	if err := query.Model(&Item{}).Update("quantity", quantity).Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m itemManager) Get(filter ItemFilter) (Item, error) {
 	var item Item
 	query := filter.ToQuery(m.DB)
@@ -105,22 +130,22 @@ func (m itemManager) GetTotalItemCount(filter ItemFilter) (uint, error) {
 	return totalQuantity, nil
 }
 
-func (m itemManager) GetTotalPrice() (float64, error) {
-	var totalItemPrice float64
-	var totalVasItemPrice float64
+// the filter is applied to the items, vas-items are counted for the matching items only
+func (m itemManager) GetTotalPrice(filter ItemFilter) (money.Amount, error) {
+	var totalItemPrice money.Amount
+	var totalVasItemPrice money.Amount
 
-	queryItem := m.DB.Model(&Item{}).
-		Where("deleted_at IS NULL").
-		Select("COALESCE(SUM(quantity * price), 0)").
+	queryItem := filter.ToQuery(m.DB).Model(&Item{}).
+		Select("COALESCE(SUM(items.quantity * items.price), 0)").
 		Row()
 	if err := queryItem.Scan(&totalItemPrice); err != nil {
 		return 0, err
 	}
 
-	queryVasItem := m.DB.Model(&VasItem{}).
-		Joins("JOIN item_vas_items ON vas_items.vas_item_id = item_vas_items.vas_item_id").
-		Where("vas_items.deleted_at IS NULL AND item_vas_items.deleted_at IS NULL").
-		Select("COALESCE(SUM(vas_items.quantity * vas_items.price), 0)").
+	queryVasItem := filter.ToQuery(m.DB).Model(&Item{}).
+		Joins("JOIN item_vas_items ON items.item_id = item_vas_items.item_id AND items.cart_id = item_vas_items.cart_id").
+		Where("item_vas_items.deleted_at IS NULL").
+		Select("COALESCE(SUM(item_vas_items.quantity * item_vas_items.price), 0)").
 		Row()
 	if err := queryVasItem.Scan(&totalVasItemPrice); err != nil {
 		return 0, err
@@ -131,10 +156,10 @@ func (m itemManager) GetTotalPrice() (float64, error) {
 	return totalPrice, nil
 }
 
-func (m itemManager) GetUniqueItemCount() (int64, error) {
+func (m itemManager) GetUniqueItemCount(filter ItemFilter) (int64, error) {
 	var count int64
 
-	if err := m.DB.Model(&Item{}).Count(&count).Error; err != nil {
+	if err := filter.ToQuery(m.DB).Model(&Item{}).Count(&count).Error; err != nil {
 		return 0, err
 	}
 
@@ -144,8 +169,7 @@ func (m itemManager) GetUniqueItemCount() (int64, error) {
 func (m itemManager) GetTotalVasItemCount(filter ItemVasItemFilter) (uint, error) {
 	var totalQuantity uint
 	query := filter.ToQuery(m.DB).Model(&ItemVasItem{}).
-		Joins("JOIN vas_items ON item_vas_items.vas_item_id = vas_items.vas_item_id").
-		Select("COALESCE(SUM(vas_items.quantity), 0)").
+		Select("COALESCE(SUM(item_vas_items.quantity), 0)").
 		Row()
 
 	if err := query.Scan(&totalQuantity); err != nil {
@@ -155,29 +179,42 @@ func (m itemManager) GetTotalVasItemCount(filter ItemVasItemFilter) (uint, error
 	return totalQuantity, nil
 }
 
-// this function is not deleting entries from vas_items table, but from item_vas_items (pivot table)
-func (m itemManager) DeleteVasItemsOfItem(itemID uint) error {
+func (m itemManager) DeleteVasItemsOfItem(filter ItemVasItemFilter) error {
 
-	if err := m.DB.Where("item_id = ?", itemID).Delete(&ItemVasItem{}).Error; err != nil {
+	if err := filter.ToQuery(m.DB).Delete(&ItemVasItem{}).Error; err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (m itemManager) AreAllItemsFromSameSeller() (bool, error) {
-	var distinctSellerCount int64
-	query := m.DB.Model(&Item{}).Select("seller_id").Group("seller_id").Count(&distinctSellerCount)
+// FindCartContent loads the items of the cart and the vas-items of all items with two queries, the totals and the
+// promotions of the cart are calculated from it without querying every item.
+func (m itemManager) FindCartContent(cartID uint) (CartContent, error) {
+	items, err := m.Find(ItemFilter{CartID: cartID})
+	if err != nil {
+		return CartContent{}, err
+	}
 
-	if query.Error != nil {
-		return false, query.Error
+	var vasItems []ItemVasItem
+
+	query := ItemVasItemFilter{CartID: cartID}.ToQuery(m.DB.Model(&ItemVasItem{})).
+		Order("item_vas_items.id, item_vas_items.vas_item_id")
+
+	if err := query.Find(&vasItems).Error; err != nil {
+		return CartContent{}, err
+	}
+
+	content := CartContent{Items: items, VasItems: make(map[uint][]ItemVasItem)}
+	for _, vasItem := range vasItems {
+		content.VasItems[vasItem.ItemID] = append(content.VasItems[vasItem.ItemID], vasItem)
 	}
 
-	return distinctSellerCount == 1, nil
+	return content, nil
 }
 
-func (m itemManager) DeleteAllItems() error {
-	err := m.DB.Exec("UPDATE items SET deleted_at = NOW() WHERE deleted_at IS NULL").Error
+func (m itemManager) DeleteAllItems(cartID uint) error {
+	err := db.OwnedCarts(m.DB, "items").Where("items.cart_id = ?", cartID).Delete(&Item{}).Error
 	if err != nil {
 		return err
 	}
@@ -185,14 +222,12 @@ func (m itemManager) DeleteAllItems() error {
 }
 
 type VasItemManager interface {
-	CreateNewVasItem(vasItem VasItem) (VasItem, error)
 	CreateItemVasItem(itemVasItem ItemVasItem) (ItemVasItem, error)
+	DeleteItemVasItem(filter ItemVasItemFilter) error
 	WithTx(tx *gorm.DB) VasItemManager
-	IsExists(filter VasItemFilter) (bool, error)
+	WithContext(ctx context.Context) VasItemManager
 	IsExistsInItem(filter ItemVasItemFilter) (bool, error)
-	GetVasItemsOfAnItem(filter ItemVasItemFilter) ([]VasItem, error)
-	DeleteAllItemVasItems() error
-	DeleteAllVasItems() error
+	DeleteAllItemVasItems(cartID uint) error
 }
 
 type vasItemManager struct {
@@ -215,13 +250,10 @@ func (m vasItemManager) WithTx(tx *gorm.DB) VasItemManager {
 	}
 }
 
-func (m vasItemManager) CreateNewVasItem(vasItem VasItem) (VasItem, error) {
-
-	if err := m.DB.Create(&vasItem).Error; err != nil {
-		return VasItem{}, err
+func (m vasItemManager) WithContext(ctx context.Context) VasItemManager {
+	return vasItemManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
 	}
-
-	return vasItem, nil
 }
 
 func (m vasItemManager) CreateItemVasItem(itemVasItem ItemVasItem) (ItemVasItem, error) {
@@ -233,15 +265,13 @@ func (m vasItemManager) CreateItemVasItem(itemVasItem ItemVasItem) (ItemVasItem,
 	return itemVasItem, nil
 }
 
-func (m vasItemManager) IsExists(filter VasItemFilter) (bool, error) {
-	var count int64
-	query := filter.ToQuery(m.DB.Model(&VasItem{})).Count(&count)
+func (m vasItemManager) DeleteItemVasItem(filter ItemVasItemFilter) error {
 
-	if query.Error != nil {
-		return false, query.Error
+	if err := filter.ToQuery(m.DB).Delete(&ItemVasItem{}).Error; err != nil {
+		return err
 	}
 
-	return count > 0, nil
+	return nil
 }
 
 func (m vasItemManager) IsExistsInItem(filter ItemVasItemFilter) (bool, error) {
@@ -256,34 +286,46 @@ func (m vasItemManager) IsExistsInItem(filter ItemVasItemFilter) (bool, error) {
 	return count > 0, nil
 }
 
-func (m vasItemManager) GetVasItemsOfAnItem(filter ItemVasItemFilter) ([]VasItem, error) {
-	var vasItems []VasItem
-
-	query := filter.ToQuery(m.DB.Model(&ItemVasItem{}))
+func (m vasItemManager) DeleteAllItemVasItems(cartID uint) error {
+	err := db.OwnedCarts(m.DB, "item_vas_items").Where("item_vas_items.cart_id = ?", cartID).Delete(&ItemVasItem{}).Error
+	if err != nil {
+		return err
+	}
+	return nil
+}
 
-	query = query.Joins("JOIN vas_items ON item_vas_items.vas_item_id = vas_items.vas_item_id").
-		Where("vas_items.deleted_at IS NULL").
-		Select("vas_items.*")
+// LockCart takes a transaction level advisory lock of the cart, so the requests that read the cart to check the rules
+// and then change it run one after another for the same cart. It must be called in a transaction, the lock is
+// released when the transaction ends.
+func (m itemManager) LockCart(cartID uint) error {
+	return m.DB.Exec("SELECT pg_advisory_xact_lock(?, ?)", CART_LOCK_NAMESPACE, cartID).Error
+}
 
-	if err := query.Find(&vasItems).Error; err != nil {
-		return nil, err
+// ClaimCart makes the user of the request the owner of the cart if the cart has no owner yet, it returns false when the
+// cart belongs to another user. The carts of the guests belong to the guest session of the request, a guest cannot use
+// the cart of another guest session.
+func (m itemManager) ClaimCart(cartID uint) (bool, error) {
+	userID, ok := auth.UserIDFromContext(m.DB.Statement.Context)
+	if !ok {
+		return false, nil
 	}
 
-	return vasItems, nil
-}
+	cart := Cart{Model: gorm.Model{ID: cartID}, UserID: userID}
+	if userID == auth.GUEST_USER_ID {
+		cart.GuestSession, ok = auth.GuestSessionFromContext(m.DB.Statement.Context)
+		if !ok {
+			return false, nil
+		}
+	}
 
-func (m vasItemManager) DeleteAllVasItems() error {
-	err := m.DB.Exec("UPDATE vas_items SET deleted_at = NOW() WHERE deleted_at IS NULL").Error
-	if err != nil {
-		return err
+	if err := m.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&cart).Error; err != nil {
+		return false, err
 	}
-	return nil
-}
 
-func (m vasItemManager) DeleteAllItemVasItems() error {
-	err := m.DB.Exec("UPDATE item_vas_items SET deleted_at = NOW() WHERE deleted_at IS NULL").Error
-	if err != nil {
-		return err
+	var owner Cart
+	if err := m.DB.Model(&Cart{}).Where("carts.id = ?", cartID).First(&owner).Error; err != nil {
+		return false, err
 	}
-	return nil
+
+	return owner.UserID == cart.UserID && owner.GuestSession == cart.GuestSession, nil
 }
@@ -0,0 +1,321 @@
+package item
+
+import (
+	"checkoutProject/pkg/common/env"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	"fmt"
+	"sort"
+)
+
+// statuses of the lines of a merge
+const (
+	MERGE_STATUS_MERGED  = "merged"
+	MERGE_STATUS_CLAMPED = "clamped"
+	MERGE_STATUS_DROPPED = "dropped"
+)
+
+// MergeResult is what happened to a line of the merged cart, VasItemID is 0 for the items. Quantity is the quantity
+// added to the cart, Reason is the rule that clamped or dropped the line.
+type MergeResult struct {
+	ItemID            uint
+	VasItemID         uint
+	RequestedQuantity uint
+	Quantity          uint
+	Status            string
+	Reason            string
+}
+
+// MergePlan is the changes that merge a cart into another one, UpdatedItems are the items of the cart with their new
+// quantities.
+type MergePlan struct {
+	UpdatedItems    []Item
+	NewItems        []Item
+	NewItemVasItems []ItemVasItem
+	Results         []MergeResult
+}
+
+// mergedCart is the cart while the lines of the other cart are merged into it, the vas-items of every item are kept by
+// their IDs with their own price and quantity.
+type mergedCart struct {
+	rules         env.Rules
+	products      map[uint]catalog.Product
+	items         map[uint]*Item
+	itemVasItems  map[uint]map[uint]ItemVasItem
+	existingItems map[uint]bool
+	updatedItems  map[uint]bool
+}
+
+// PlanMerge merges the content of the other cart into the cart with the same rules the items and vas-items are added
+// with. Items with the same item ID are merged into one line by summing their quantities and their vas-items are
+// unioned. Lines that would break a limit are clamped to what is left of it, or dropped when nothing is left. Both carts
+// are priced with the current catalog prices, lines of the other cart that are not in the catalog anymore are dropped.
+func PlanMerge(cartID uint, cart CartContent, other CartContent, products map[uint]catalog.Product, rules env.Rules) MergePlan {
+	cart = cart.WithCatalogPrices(products)
+	other = other.WithCatalogPrices(products)
+
+	merged := mergedCart{
+		rules:         rules,
+		products:      products,
+		items:         make(map[uint]*Item),
+		itemVasItems:  make(map[uint]map[uint]ItemVasItem),
+		existingItems: make(map[uint]bool),
+		updatedItems:  make(map[uint]bool),
+	}
+
+	for _, itm := range cart.Items {
+		itm := itm
+		merged.items[itm.ItemID] = &itm
+		merged.existingItems[itm.ItemID] = true
+		merged.itemVasItems[itm.ItemID] = make(map[uint]ItemVasItem)
+
+		for _, vasItem := range cart.VasItems[itm.ItemID] {
+			merged.itemVasItems[itm.ItemID][vasItem.VasItemID] = vasItem
+		}
+	}
+
+	otherItems := append([]Item{}, other.Items...)
+	sort.Slice(otherItems, func(i, j int) bool {
+		return otherItems[i].ItemID < otherItems[j].ItemID
+	})
+
+	var plan MergePlan
+	for _, itm := range otherItems {
+		result := merged.mergeItem(cartID, itm)
+		plan.Results = append(plan.Results, result)
+
+		otherVasItems := append([]ItemVasItem{}, other.VasItems[itm.ItemID]...)
+		sort.Slice(otherVasItems, func(i, j int) bool {
+			return otherVasItems[i].VasItemID < otherVasItems[j].VasItemID
+		})
+
+		// the vas-items are still attached when only the quantity of an item already in the cart is dropped
+		_, isItemInCart := merged.items[itm.ItemID]
+
+		for _, vasItem := range otherVasItems {
+			if !isItemInCart {
+				plan.Results = append(plan.Results, MergeResult{
+					ItemID:            itm.ItemID,
+					VasItemID:         vasItem.VasItemID,
+					RequestedQuantity: vasItem.Quantity,
+					Status:            MERGE_STATUS_DROPPED,
+					Reason:            fmt.Sprintf("item %d is not in the cart", itm.ItemID),
+				})
+				continue
+			}
+
+			vasItemResult, newItemVasItem := merged.mergeVasItem(cartID, itm.ItemID, vasItem)
+			plan.Results = append(plan.Results, vasItemResult)
+
+			if newItemVasItem != nil {
+				plan.NewItemVasItems = append(plan.NewItemVasItems, *newItemVasItem)
+			}
+		}
+	}
+
+	for _, itm := range otherItems {
+		line, ok := merged.items[itm.ItemID]
+		if !ok {
+			continue
+		}
+
+		if merged.existingItems[itm.ItemID] && merged.updatedItems[itm.ItemID] {
+			plan.UpdatedItems = append(plan.UpdatedItems, *line)
+		}
+
+		if !merged.existingItems[itm.ItemID] {
+			plan.NewItems = append(plan.NewItems, *line)
+		}
+	}
+
+	return plan
+}
+
+func (m *mergedCart) mergeItem(cartID uint, itm Item) MergeResult {
+	result := MergeResult{ItemID: itm.ItemID, RequestedQuantity: itm.Quantity, Status: MERGE_STATUS_MERGED}
+
+	if _, ok := m.products[itm.ItemID]; !ok {
+		return dropped(result, fmt.Sprintf("item %d is not in the catalog", itm.ItemID))
+	}
+
+	// a merged line keeps the category of the item already in the cart, both carts have the catalog prices
+	line, ok := m.items[itm.ItemID]
+	if !ok {
+		line = &Item{
+			CartID:     cartID,
+			ItemID:     itm.ItemID,
+			CategoryID: itm.CategoryID,
+			SellerID:   itm.SellerID,
+			Price:      itm.Price,
+		}
+	}
+
+	for itemID, other := range m.items {
+		if itemID == line.ItemID {
+			continue
+		}
+
+		if line.isDigitalItem() && other.isDefaultItem() {
+			return dropped(result, "cannot add a digital item if default item exists in cart")
+		}
+
+		if line.isDefaultItem() && other.isDigitalItem() {
+			return dropped(result, "cannot add a default item if digital item exists in cart")
+		}
+	}
+
+	if !ok && uint(len(m.items)) >= m.rules.MaxUniqueItems {
+		return dropped(result, fmt.Sprintf("total number of unique items cannot be over %d", m.rules.MaxUniqueItems))
+	}
+
+	quantity := clamp(&result, itm.Quantity, remaining(MAX_ITEM_QUANTITY, line.Quantity),
+		fmt.Sprintf("quantity of item %d cannot be over %d", itm.ItemID, MAX_ITEM_QUANTITY))
+	totalItemCount := m.totalItemCount()
+
+	// digital and default items are not mixed, so every item of the cart is digital when the line is digital
+	if line.isDigitalItem() {
+		quantity = clamp(&result, quantity, remaining(m.rules.MaxDigitalItems, totalItemCount),
+			fmt.Sprintf("total number of digital items cannot be over %d", m.rules.MaxDigitalItems))
+	}
+
+	quantity = clamp(&result, quantity, remaining(m.rules.MaxDefaultItems, totalItemCount),
+		fmt.Sprintf("total number of items cannot be over %d", m.rules.MaxDefaultItems))
+
+	quantity = clamp(&result, quantity, m.affordableQuantity(line.Price),
+		fmt.Sprintf("total price of cart cannot be over %s", m.rules.MaxPriceOfCart))
+
+	if quantity == 0 {
+		return dropped(result, result.Reason)
+	}
+
+	line.Quantity += quantity
+	m.items[line.ItemID] = line
+	m.updatedItems[line.ItemID] = true
+	if m.itemVasItems[line.ItemID] == nil {
+		m.itemVasItems[line.ItemID] = make(map[uint]ItemVasItem)
+	}
+
+	result.Quantity = quantity
+	return result
+}
+
+// mergeVasItem attaches the vas-item to the item with its catalog price, the vas-item already attached to the item is kept.
+func (m *mergedCart) mergeVasItem(cartID uint, itemID uint, vasItem ItemVasItem) (MergeResult, *ItemVasItem) {
+	result := MergeResult{ItemID: itemID, VasItemID: vasItem.VasItemID, RequestedQuantity: vasItem.Quantity, Status: MERGE_STATUS_MERGED}
+	line := m.items[itemID]
+
+	if existingVasItem, ok := m.itemVasItems[itemID][vasItem.VasItemID]; ok {
+		result.Quantity = existingVasItem.Quantity
+		return result, nil
+	}
+
+	if _, ok := m.products[vasItem.VasItemID]; !ok {
+		return dropped(result, fmt.Sprintf("vas-item %d is not in the catalog", vasItem.VasItemID)), nil
+	}
+
+	if vasItem.CategoryID != VAS_ITEM_CATEGORY_ID {
+		return dropped(result, fmt.Sprintf("cannot add vas-item with category id %d", vasItem.CategoryID)), nil
+	}
+
+	if vasItem.SellerID != m.rules.VasItemSellerID {
+		return dropped(result, fmt.Sprintf("cannot add vas-item with seller id %d", vasItem.SellerID)), nil
+	}
+
+	if !line.isApplicableForVasItems(m.rules.VasItemEligibleCategoryIDs) {
+		return dropped(result, "item category is not suitable to add vas-items"), nil
+	}
+
+	if line.Price < vasItem.Price {
+		return dropped(result, "single vas-item's price cannot be more than single item's price"), nil
+	}
+
+	quantity := clamp(&result, vasItem.Quantity, remaining(m.rules.MaxVasItemOnSingleItem, m.vasItemCount(itemID)),
+		fmt.Sprintf("item %d cannot have more than %d vas-items", itemID, m.rules.MaxVasItemOnSingleItem))
+	quantity = clamp(&result, quantity, m.affordableQuantity(vasItem.Price),
+		fmt.Sprintf("total price of cart cannot be over %s", m.rules.MaxPriceOfCart))
+
+	if quantity == 0 {
+		return dropped(result, result.Reason), nil
+	}
+
+	newItemVasItem := ItemVasItem{
+		CartID:     cartID,
+		ItemID:     itemID,
+		VasItemID:  vasItem.VasItemID,
+		CategoryID: vasItem.CategoryID,
+		SellerID:   vasItem.SellerID,
+		Price:      vasItem.Price,
+		Quantity:   quantity,
+	}
+	m.itemVasItems[itemID][vasItem.VasItemID] = newItemVasItem
+
+	result.Quantity = quantity
+	return result, &newItemVasItem
+}
+
+func (m *mergedCart) totalItemCount() uint {
+	var count uint
+	for _, itm := range m.items {
+		count += itm.Quantity
+	}
+	return count
+}
+
+func (m *mergedCart) vasItemCount(itemID uint) uint {
+	var count uint
+	for _, vasItem := range m.itemVasItems[itemID] {
+		count += vasItem.Quantity
+	}
+	return count
+}
+
+// totalPrice is calculated like GetTotalPrice.
+func (m *mergedCart) totalPrice() money.Amount {
+	var totalPrice money.Amount
+	for itemID, itm := range m.items {
+		totalPrice += itm.OrderPrice()
+
+		for _, vasItem := range m.itemVasItems[itemID] {
+			totalPrice += vasItem.OrderPrice()
+		}
+	}
+	return totalPrice
+}
+
+// affordableQuantity is how many of the price fit in the cart before its total price is over the limit.
+func (m *mergedCart) affordableQuantity(price money.Amount) uint {
+	remainingPrice := m.rules.MaxPriceOfCart - m.totalPrice()
+	if remainingPrice <= 0 {
+		return 0
+	}
+
+	if price <= 0 {
+		return ^uint(0)
+	}
+	return uint(remainingPrice / price)
+}
+
+func remaining(limit uint, used uint) uint {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
+
+// clamp returns the quantity limited to the maximum, the result is marked as clamped with the reason when it is limited.
+func clamp(result *MergeResult, quantity uint, maximum uint, reason string) uint {
+	if quantity <= maximum {
+		return quantity
+	}
+
+	result.Status = MERGE_STATUS_CLAMPED
+	result.Reason = reason
+	return maximum
+}
+
+func dropped(result MergeResult, reason string) MergeResult {
+	result.Status = MERGE_STATUS_DROPPED
+	result.Reason = reason
+	result.Quantity = 0
+	return result
+}
@@ -0,0 +1,257 @@
+package item
+
+import (
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func newMergeItem(itemID uint, categoryID uint, price money.Amount, quantity uint) Item {
+	return Item{CartID: 2, ItemID: itemID, CategoryID: categoryID, SellerID: 100, Price: price, Quantity: quantity}
+}
+
+func newMergeVasItem(vasItemID uint, price money.Amount, quantity uint) ItemVasItem {
+	return ItemVasItem{CartID: 2, VasItemID: vasItemID, CategoryID: VAS_ITEM_CATEGORY_ID, SellerID: testRules.VasItemSellerID, Price: price, Quantity: quantity}
+}
+
+// newMergeCatalog returns the products of the lines of the carts with their prices, the first price of a product is kept.
+func newMergeCatalog(carts ...CartContent) map[uint]catalog.Product {
+	products := make(map[uint]catalog.Product)
+	add := func(productID uint, categoryID uint, sellerID uint, price money.Amount) {
+		if _, ok := products[productID]; !ok {
+			products[productID] = catalog.Product{ProductID: productID, CategoryID: categoryID, SellerID: sellerID, Price: price}
+		}
+	}
+
+	for _, cart := range carts {
+		for _, itm := range cart.Items {
+			add(itm.ItemID, itm.CategoryID, itm.SellerID, itm.Price)
+			for _, vasItem := range cart.VasItems[itm.ItemID] {
+				add(vasItem.VasItemID, vasItem.CategoryID, vasItem.SellerID, vasItem.Price)
+			}
+		}
+	}
+	return products
+}
+
+func TestPlanMerge(t *testing.T) {
+	Convey("TEST items with the same item id are merged into one line", t, func() {
+		cart := CartContent{Items: []Item{{CartID: 1, ItemID: 10, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit, Quantity: 2}}}
+		guestCart := CartContent{Items: []Item{
+			newMergeItem(10, 1001, 40*money.Unit, 3),
+			newMergeItem(11, 1001, 10*money.Unit, 1),
+		}}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.UpdatedItems, ShouldHaveLength, 1)
+		So(plan.UpdatedItems[0].Quantity, ShouldEqual, 5)
+		So(plan.UpdatedItems[0].Price, ShouldEqual, 50*money.Unit)
+
+		So(plan.NewItems, ShouldHaveLength, 1)
+		So(plan.NewItems[0].CartID, ShouldEqual, 1)
+		So(plan.NewItems[0].ItemID, ShouldEqual, 11)
+
+		So(plan.Results, ShouldResemble, []MergeResult{
+			{ItemID: 10, RequestedQuantity: 3, Quantity: 3, Status: MERGE_STATUS_MERGED},
+			{ItemID: 11, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+		})
+	})
+
+	Convey("TEST digital and default items are not mixed", t, func() {
+		cart := CartContent{Items: []Item{newMergeItem(10, 1001, 10*money.Unit, 1)}}
+		guestCart := CartContent{Items: []Item{newMergeItem(11, DIGITAL_ITEM_CATEGORY_ID, 10*money.Unit, 1)}}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.NewItems, ShouldBeEmpty)
+		So(plan.Results[0].Status, ShouldEqual, MERGE_STATUS_DROPPED)
+		So(plan.Results[0].Reason, ShouldEqual, "cannot add a digital item if default item exists in cart")
+
+		plan = PlanMerge(1, guestCart, cart, newMergeCatalog(guestCart, cart), testRules)
+		So(plan.NewItems, ShouldBeEmpty)
+		So(plan.Results[0].Reason, ShouldEqual, "cannot add a default item if digital item exists in cart")
+	})
+
+	Convey("TEST quantities are clamped to the item limits", t, func() {
+		cart := CartContent{Items: []Item{newMergeItem(10, DIGITAL_ITEM_CATEGORY_ID, 10*money.Unit, 4)}}
+		guestCart := CartContent{Items: []Item{newMergeItem(10, DIGITAL_ITEM_CATEGORY_ID, 10*money.Unit, 3)}}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.UpdatedItems[0].Quantity, ShouldEqual, testRules.MaxDigitalItems)
+		So(plan.Results[0], ShouldResemble, MergeResult{
+			ItemID: 10, RequestedQuantity: 3, Quantity: 1, Status: MERGE_STATUS_CLAMPED,
+			Reason: "total number of digital items cannot be over 5",
+		})
+
+		cart = CartContent{Items: []Item{newMergeItem(10, 1001, 10*money.Unit, 28)}}
+		guestCart = CartContent{Items: []Item{newMergeItem(11, 1001, 10*money.Unit, 5)}}
+
+		plan = PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.NewItems[0].Quantity, ShouldEqual, 2)
+		So(plan.Results[0].Status, ShouldEqual, MERGE_STATUS_CLAMPED)
+		So(plan.Results[0].Reason, ShouldEqual, "total number of items cannot be over 30")
+	})
+
+	Convey("TEST quantities are clamped to the max price of the cart", t, func() {
+		cart := CartContent{Items: []Item{newMergeItem(10, 1001, 400000*money.Unit, 1)}}
+		guestCart := CartContent{Items: []Item{newMergeItem(11, 1001, 40000*money.Unit, 4)}}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.NewItems[0].Quantity, ShouldEqual, 2)
+		So(plan.Results[0].Status, ShouldEqual, MERGE_STATUS_CLAMPED)
+		So(plan.Results[0].Reason, ShouldEqual, "total price of cart cannot be over 500000.00")
+	})
+
+	Convey("TEST new items over the unique item limit are dropped with their vas-items", t, func() {
+		var items []Item
+		for itemID := uint(1); itemID <= testRules.MaxUniqueItems; itemID++ {
+			items = append(items, newMergeItem(itemID, 1001, money.Unit, 1))
+		}
+		cart := CartContent{Items: items}
+		guestCart := CartContent{
+			Items:    []Item{newMergeItem(20, 1001, 10*money.Unit, 1), newMergeItem(1, 1001, 10*money.Unit, 1)},
+			VasItems: map[uint][]ItemVasItem{20: {newMergeVasItem(30, money.Unit, 1)}},
+		}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.NewItems, ShouldBeEmpty)
+		So(plan.NewItemVasItems, ShouldBeEmpty)
+		So(plan.UpdatedItems, ShouldHaveLength, 1)
+		So(plan.Results, ShouldResemble, []MergeResult{
+			{ItemID: 1, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+			{ItemID: 20, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "total number of unique items cannot be over 10"},
+			{ItemID: 20, VasItemID: 30, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "item 20 is not in the cart"},
+		})
+	})
+
+	Convey("TEST vas-items are unioned", t, func() {
+		cart := CartContent{
+			Items:    []Item{{CartID: 1, ItemID: 10, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit, Quantity: 1}},
+			VasItems: map[uint][]ItemVasItem{10: {{CartID: 1, VasItemID: 30, CategoryID: VAS_ITEM_CATEGORY_ID, SellerID: testRules.VasItemSellerID, Price: money.Unit, Quantity: 1}}},
+		}
+		guestCart := CartContent{
+			Items:    []Item{newMergeItem(10, 1001, 50*money.Unit, 1)},
+			VasItems: map[uint][]ItemVasItem{10: {newMergeVasItem(30, money.Unit, 1), newMergeVasItem(31, money.Unit, 1)}},
+		}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.NewItemVasItems, ShouldResemble, []ItemVasItem{{
+			CartID: 1, ItemID: 10, VasItemID: 31, CategoryID: VAS_ITEM_CATEGORY_ID, SellerID: testRules.VasItemSellerID,
+			Price: money.Unit, Quantity: 1,
+		}})
+		So(plan.Results[1:], ShouldResemble, []MergeResult{
+			{ItemID: 10, VasItemID: 30, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+			{ItemID: 10, VasItemID: 31, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+		})
+	})
+
+	Convey("TEST vas-items are checked like when they are added", t, func() {
+		wrongSeller := newMergeVasItem(31, money.Unit, 1)
+		wrongSeller.SellerID = 1
+
+		guestCart := CartContent{
+			Items: []Item{newMergeItem(10, 1001, 10*money.Unit, 1), newMergeItem(11, 2000, 10*money.Unit, 1)},
+			VasItems: map[uint][]ItemVasItem{
+				10: {newMergeVasItem(30, 20*money.Unit, 1), wrongSeller, newMergeVasItem(32, money.Unit, 5)},
+				11: {newMergeVasItem(33, money.Unit, 1)},
+			},
+		}
+
+		plan := PlanMerge(1, CartContent{}, guestCart, newMergeCatalog(guestCart), testRules)
+		So(plan.NewItemVasItems, ShouldHaveLength, 1)
+		So(plan.NewItemVasItems[0].Quantity, ShouldEqual, testRules.MaxVasItemOnSingleItem)
+		So(plan.Results, ShouldResemble, []MergeResult{
+			{ItemID: 10, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+			{ItemID: 10, VasItemID: 30, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "single vas-item's price cannot be more than single item's price"},
+			{ItemID: 10, VasItemID: 31, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "cannot add vas-item with seller id 1"},
+			{ItemID: 10, VasItemID: 32, RequestedQuantity: 5, Quantity: 3, Status: MERGE_STATUS_CLAMPED, Reason: "item 10 cannot have more than 3 vas-items"},
+			{ItemID: 11, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+			{ItemID: 11, VasItemID: 33, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "item category is not suitable to add vas-items"},
+		})
+	})
+
+	Convey("TEST the same vas-item keeps its own quantity on every item", t, func() {
+		cart := CartContent{
+			Items: []Item{
+				{CartID: 1, ItemID: 10, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit, Quantity: 1},
+				{CartID: 1, ItemID: 11, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit, Quantity: 1},
+			},
+			VasItems: map[uint][]ItemVasItem{
+				10: {{CartID: 1, ItemID: 10, VasItemID: 30, CategoryID: VAS_ITEM_CATEGORY_ID, SellerID: testRules.VasItemSellerID, Price: money.Unit, Quantity: 3}},
+			},
+		}
+		guestCart := CartContent{
+			Items:    []Item{newMergeItem(10, 1001, 50*money.Unit, 1), newMergeItem(11, 1001, 50*money.Unit, 1)},
+			VasItems: map[uint][]ItemVasItem{10: {newMergeVasItem(31, money.Unit, 1)}, 11: {newMergeVasItem(30, money.Unit, 2)}},
+		}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.NewItemVasItems, ShouldHaveLength, 1)
+		So(plan.NewItemVasItems[0].ItemID, ShouldEqual, 11)
+		So(plan.NewItemVasItems[0].Quantity, ShouldEqual, 2)
+		So(plan.Results[1].Status, ShouldEqual, MERGE_STATUS_DROPPED)
+		So(plan.Results[1].Reason, ShouldEqual, "item 10 cannot have more than 3 vas-items")
+	})
+
+	Convey("TEST merged lines have the catalog prices", t, func() {
+		cart := CartContent{Items: []Item{{CartID: 1, ItemID: 10, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit, Quantity: 1}}}
+		guestCart := CartContent{
+			Items:    []Item{newMergeItem(10, 1001, 50*money.Unit, 1), newMergeItem(11, 1001, 0, 1)},
+			VasItems: map[uint][]ItemVasItem{11: {newMergeVasItem(30, 0, 1)}},
+		}
+		products := map[uint]catalog.Product{
+			10: {ProductID: 10, CategoryID: 1001, SellerID: 100, Price: 60 * money.Unit},
+			11: {ProductID: 11, CategoryID: 1001, SellerID: 100, Price: 20 * money.Unit},
+			30: {ProductID: 30, CategoryID: VAS_ITEM_CATEGORY_ID, SellerID: testRules.VasItemSellerID, Price: 5 * money.Unit},
+		}
+
+		plan := PlanMerge(1, cart, guestCart, products, testRules)
+		So(plan.UpdatedItems[0].Price, ShouldEqual, 60*money.Unit)
+		So(plan.NewItems[0].Price, ShouldEqual, 20*money.Unit)
+		So(plan.NewItemVasItems[0].Price, ShouldEqual, 5*money.Unit)
+
+		// the max price of the cart is checked with the catalog prices, not with the prices the guest added the items at
+		products[11] = catalog.Product{ProductID: 11, CategoryID: 1001, SellerID: 100, Price: 499950 * money.Unit}
+
+		plan = PlanMerge(1, cart, guestCart, products, testRules)
+		So(plan.NewItems, ShouldBeEmpty)
+		So(plan.Results[1], ShouldResemble, MergeResult{
+			ItemID: 11, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED,
+			Reason: "total price of cart cannot be over 500000.00",
+		})
+	})
+
+	Convey("TEST lines that are not in the catalog are dropped", t, func() {
+		guestCart := CartContent{
+			Items:    []Item{newMergeItem(10, 1001, 50*money.Unit, 1), newMergeItem(11, 1001, 50*money.Unit, 1)},
+			VasItems: map[uint][]ItemVasItem{10: {newMergeVasItem(30, money.Unit, 1)}},
+		}
+		products := map[uint]catalog.Product{11: {ProductID: 11, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit}}
+
+		plan := PlanMerge(1, CartContent{}, guestCart, products, testRules)
+		So(plan.NewItems, ShouldHaveLength, 1)
+		So(plan.Results, ShouldResemble, []MergeResult{
+			{ItemID: 10, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "item 10 is not in the catalog"},
+			{ItemID: 10, VasItemID: 30, RequestedQuantity: 1, Status: MERGE_STATUS_DROPPED, Reason: "item 10 is not in the cart"},
+			{ItemID: 11, RequestedQuantity: 1, Quantity: 1, Status: MERGE_STATUS_MERGED},
+		})
+
+		products[10] = catalog.Product{ProductID: 10, CategoryID: 1001, SellerID: 100, Price: 50 * money.Unit}
+
+		plan = PlanMerge(1, CartContent{}, guestCart, products, testRules)
+		So(plan.NewItemVasItems, ShouldBeEmpty)
+		So(plan.Results[1].Reason, ShouldEqual, "vas-item 30 is not in the catalog")
+	})
+
+	Convey("TEST summed quantities are clamped to the max quantity of a line", t, func() {
+		cart := CartContent{Items: []Item{{CartID: 1, ItemID: 10, CategoryID: 1001, SellerID: 100, Price: 10 * money.Unit, Quantity: 8}}}
+		guestCart := CartContent{Items: []Item{newMergeItem(10, 1001, 10*money.Unit, 5)}}
+
+		plan := PlanMerge(1, cart, guestCart, newMergeCatalog(cart, guestCart), testRules)
+		So(plan.UpdatedItems[0].Quantity, ShouldEqual, MAX_ITEM_QUANTITY)
+		So(plan.Results[0], ShouldResemble, MergeResult{
+			ItemID: 10, RequestedQuantity: 5, Quantity: 2, Status: MERGE_STATUS_CLAMPED,
+			Reason: "quantity of item 10 cannot be over 10",
+		})
+	})
+}
@@ -1,21 +1,29 @@
 package item
 
-import "gorm.io/gorm"
+import (
+	"checkoutProject/pkg/common/money"
+	"context"
+	"gorm.io/gorm"
+)
 
 type mockItemManagerImpl struct {
-	MCreate                    func(item Item) (Item, error)
-	MWithTx                    func(tx *gorm.DB) ItemManager
-	MGet                       func(filter ItemFilter) (Item, error)
-	MFind                      func(filter ItemFilter) ([]Item, error)
-	MDelete                    func(filter ItemFilter) error
-	MIsExists                  func(filter ItemFilter) (bool, error)
-	MGetTotalItemCount         func(filter ItemFilter) (uint, error)
-	MGetUniqueItemCount        func() (int64, error)
-	MGetTotalPrice             func() (float64, error)
-	MGetTotalVasItemCount      func(filter ItemVasItemFilter) (uint, error)
-	MDeleteVasItemsOfItem      func(itemID uint) error
-	MAreAllItemsFromSameSeller func() (bool, error)
-	MDeleteAllItems            func() error
+	MCreate               func(item Item) (Item, error)
+	MWithTx               func(tx *gorm.DB) ItemManager
+	MWithContext          func(ctx context.Context) ItemManager
+	MGet                  func(filter ItemFilter) (Item, error)
+	MFind                 func(filter ItemFilter) ([]Item, error)
+	MDelete               func(filter ItemFilter) error
+	MUpdateQuantity       func(filter ItemFilter, quantity uint) error
+	MIsExists             func(filter ItemFilter) (bool, error)
+	MGetTotalItemCount    func(filter ItemFilter) (uint, error)
+	MGetUniqueItemCount   func(filter ItemFilter) (int64, error)
+	MGetTotalPrice        func(filter ItemFilter) (money.Amount, error)
+	MGetTotalVasItemCount func(filter ItemVasItemFilter) (uint, error)
+	MDeleteVasItemsOfItem func(filter ItemVasItemFilter) error
+	MDeleteAllItems       func(cartID uint) error
+	MLockCart             func(cartID uint) error
+	MClaimCart            func(cartID uint) (bool, error)
+	MFindCartContent      func(cartID uint) (CartContent, error)
 }
 
 func NewMockItemManager() mockItemManagerImpl {
@@ -30,6 +38,10 @@ func (m mockItemManagerImpl) WithTx(tx *gorm.DB) ItemManager {
 	return m.MWithTx(tx)
 }
 
+func (m mockItemManagerImpl) WithContext(ctx context.Context) ItemManager {
+	return m.MWithContext(ctx)
+}
+
 func (m mockItemManagerImpl) Get(filter ItemFilter) (Item, error) {
 	return m.MGet(filter)
 }
@@ -42,6 +54,10 @@ func (m mockItemManagerImpl) Delete(filter ItemFilter) error {
 	return m.MDelete(filter)
 }
 
+func (m mockItemManagerImpl) UpdateQuantity(filter ItemFilter, quantity uint) error {
+	return m.MUpdateQuantity(filter, quantity)
+}
+
 func (m mockItemManagerImpl) IsExists(filter ItemFilter) (bool, error) {
 	return m.MIsExists(filter)
 }
@@ -50,73 +66,71 @@ func (m mockItemManagerImpl) GetTotalItemCount(filter ItemFilter) (uint, error)
 	return m.MGetTotalItemCount(filter)
 }
 
-func (m mockItemManagerImpl) GetUniqueItemCount() (int64, error) {
-	return m.MGetUniqueItemCount()
+func (m mockItemManagerImpl) GetUniqueItemCount(filter ItemFilter) (int64, error) {
+	return m.MGetUniqueItemCount(filter)
 }
 
-func (m mockItemManagerImpl) GetTotalPrice() (float64, error) {
-	return m.MGetTotalPrice()
+func (m mockItemManagerImpl) GetTotalPrice(filter ItemFilter) (money.Amount, error) {
+	return m.MGetTotalPrice(filter)
 }
 
 func (m mockItemManagerImpl) GetTotalVasItemCount(filter ItemVasItemFilter) (uint, error) {
 	return m.MGetTotalVasItemCount(filter)
 }
 
-func (m mockItemManagerImpl) DeleteVasItemsOfItem(itemID uint) error {
-	return m.MDeleteVasItemsOfItem(itemID)
+func (m mockItemManagerImpl) DeleteVasItemsOfItem(filter ItemVasItemFilter) error {
+	return m.MDeleteVasItemsOfItem(filter)
 }
 
-func (m mockItemManagerImpl) AreAllItemsFromSameSeller() (bool, error) {
-	return m.MAreAllItemsFromSameSeller()
+func (m mockItemManagerImpl) DeleteAllItems(cartID uint) error {
+	return m.MDeleteAllItems(cartID)
 }
 
-func (m mockItemManagerImpl) DeleteAllItems() error {
-	return m.MDeleteAllItems()
+func (m mockItemManagerImpl) LockCart(cartID uint) error {
+	return m.MLockCart(cartID)
+}
+
+func (m mockItemManagerImpl) ClaimCart(cartID uint) (bool, error) {
+	return m.MClaimCart(cartID)
+}
+
+func (m mockItemManagerImpl) FindCartContent(cartID uint) (CartContent, error) {
+	return m.MFindCartContent(cartID)
 }
 
 type mockVasItemManagerImpl struct {
-	MCreateNewVasItem      func(vasItem VasItem) (VasItem, error)
 	MCreateItemVasItem     func(itemVasItem ItemVasItem) (ItemVasItem, error)
+	MDeleteItemVasItem     func(filter ItemVasItemFilter) error
 	MWithTx                func(tx *gorm.DB) VasItemManager
-	MIsExists              func(filter VasItemFilter) (bool, error)
+	MWithContext           func(ctx context.Context) VasItemManager
 	MIsExistsInItem        func(filter ItemVasItemFilter) (bool, error)
-	MGetVasItemsOfAnItem   func(filter ItemVasItemFilter) ([]VasItem, error)
-	MDeleteAllItemVasItems func() error
-	MDeleteAllVasItems     func() error
+	MDeleteAllItemVasItems func(cartID uint) error
 }
 
 func NewMockVasItemManager() mockVasItemManagerImpl {
 	return mockVasItemManagerImpl{}
 }
 
-func (m mockVasItemManagerImpl) CreateNewVasItem(vasItem VasItem) (VasItem, error) {
-	return m.MCreateNewVasItem(vasItem)
-}
-
 func (m mockVasItemManagerImpl) CreateItemVasItem(itemVasItem ItemVasItem) (ItemVasItem, error) {
 	return m.MCreateItemVasItem(itemVasItem)
 }
 
+func (m mockVasItemManagerImpl) DeleteItemVasItem(filter ItemVasItemFilter) error {
+	return m.MDeleteItemVasItem(filter)
+}
+
 func (m mockVasItemManagerImpl) WithTx(tx *gorm.DB) VasItemManager {
 	return m.MWithTx(tx)
 }
 
-func (m mockVasItemManagerImpl) IsExists(filter VasItemFilter) (bool, error) {
-	return m.MIsExists(filter)
+func (m mockVasItemManagerImpl) WithContext(ctx context.Context) VasItemManager {
+	return m.MWithContext(ctx)
 }
 
 func (m mockVasItemManagerImpl) IsExistsInItem(filter ItemVasItemFilter) (bool, error) {
 	return m.MIsExistsInItem(filter)
 }
 
-func (m mockVasItemManagerImpl) GetVasItemsOfAnItem(filter ItemVasItemFilter) ([]VasItem, error) {
-	return m.MGetVasItemsOfAnItem(filter)
-}
-
-func (m mockVasItemManagerImpl) DeleteAllItemVasItems() error {
-	return m.MDeleteAllItemVasItems()
-}
-
-func (m mockVasItemManagerImpl) DeleteAllVasItems() error {
-	return m.MDeleteAllVasItems()
+func (m mockVasItemManagerImpl) DeleteAllItemVasItems(cartID uint) error {
+	return m.MDeleteAllItemVasItems(cartID)
 }
@@ -1,13 +1,18 @@
 package item
 
-import "gorm.io/gorm"
+import (
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	"gorm.io/gorm"
+)
 
 type Item struct {
 	gorm.Model
+	CartID     uint
 	ItemID     uint
 	CategoryID uint
 	SellerID   uint
-	Price      float64
+	Price      money.Amount
 	Quantity   uint
 }
 
@@ -19,13 +24,11 @@ func (item Item) isDefaultItem() bool {
 	return item.CategoryID != DIGITAL_ITEM_CATEGORY_ID
 }
 
-func (item Item) OrderPrice() float64 {
-	return item.Price * float64(item.Quantity)
+func (item Item) OrderPrice() money.Amount {
+	return item.Price.Mul(item.Quantity)
 }
 
-func (item Item) isApplicableForVasItems() bool {
-	applicableCategoryIds := []uint{FURNITIRE_CATEGORY_ID, ELECTRONIC_CATEGORY_ID}
-
+func (item Item) isApplicableForVasItems(applicableCategoryIds []uint) bool {
 	for _, categoryID := range applicableCategoryIds {
 		if item.CategoryID == categoryID {
 			return true
@@ -34,17 +37,122 @@ func (item Item) isApplicableForVasItems() bool {
 	return false
 }
 
-type VasItem struct {
+// CartContent is the items of a cart and the vas-items attached to them, vas-items are keyed by the item ID.
+type CartContent struct {
+	Items    []Item
+	VasItems map[uint][]ItemVasItem
+}
+
+// TotalPrice is calculated like GetTotalPrice.
+func (cart CartContent) TotalPrice() money.Amount {
+	var totalPrice money.Amount
+	for _, item := range cart.Items {
+		totalPrice += item.OrderPrice()
+
+		for _, vasItem := range cart.VasItems[item.ItemID] {
+			totalPrice += vasItem.OrderPrice()
+		}
+	}
+	return totalPrice
+}
+
+// ProductIDs returns the catalog IDs of the items and vas-items of the cart, every ID once.
+func (cart CartContent) ProductIDs() []uint {
+	var productIDs []uint
+	seen := make(map[uint]bool)
+
+	add := func(productID uint) {
+		if !seen[productID] {
+			seen[productID] = true
+			productIDs = append(productIDs, productID)
+		}
+	}
+
+	for _, item := range cart.Items {
+		add(item.ItemID)
+		for _, vasItem := range cart.VasItems[item.ItemID] {
+			add(vasItem.VasItemID)
+		}
+	}
+
+	return productIDs
+}
+
+// FindItem returns the line of the item, or false when the item is not in the cart.
+func (cart CartContent) FindItem(itemID uint) (Item, bool) {
+	for _, item := range cart.Items {
+		if item.ItemID == itemID {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// WithCatalogPrices returns a copy of the cart with the current catalog prices on its lines, lines that are not in the
+// catalog anymore keep their price.
+func (cart CartContent) WithCatalogPrices(products map[uint]catalog.Product) CartContent {
+	priced := CartContent{VasItems: make(map[uint][]ItemVasItem)}
+	for _, item := range cart.Items {
+		if product, ok := products[item.ItemID]; ok {
+			item.Price = product.Price
+		}
+		priced.Items = append(priced.Items, item)
+
+		for _, vasItem := range cart.VasItems[item.ItemID] {
+			if product, ok := products[vasItem.VasItemID]; ok {
+				vasItem.Price = product.Price
+			}
+			priced.VasItems[item.ItemID] = append(priced.VasItems[item.ItemID], vasItem)
+		}
+	}
+	return priced
+}
+
+// AreAllItemsFromSameSeller returns false for an empty cart, the sellers of the vas-items are not checked.
+func (cart CartContent) AreAllItemsFromSameSeller() bool {
+	if len(cart.Items) == 0 {
+		return false
+	}
+
+	for _, item := range cart.Items {
+		if item.SellerID != cart.Items[0].SellerID {
+			return false
+		}
+	}
+	return true
+}
+
+func (cart CartContent) ItemsOfCategory(categoryID uint) []Item {
+	var items []Item
+	for _, item := range cart.Items {
+		if item.CategoryID == categoryID {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ItemVasItem is a vas-item added to an item, the same vas-item can be added to other items of the cart with a
+// different price and quantity.
+type ItemVasItem struct {
 	gorm.Model
+	CartID     uint
+	ItemID     uint
 	VasItemID  uint
 	CategoryID uint
 	SellerID   uint
-	Price      float64
+	Price      money.Amount
 	Quantity   uint
 }
 
-type ItemVasItem struct {
+func (vasItem ItemVasItem) OrderPrice() money.Amount {
+	return vasItem.Price.Mul(vasItem.Quantity)
+}
+
+// Cart keeps the owner of a cart, the ID is the cart ID. A cart is claimed by the user who adds its first item.
+// The carts of the guests keep the guest session that claimed them, see auth.GuestSession.
+type Cart struct {
 	gorm.Model
-	ItemID    uint
-	VasItemID uint
+	UserID       uint
+	GuestSession string
 }
@@ -0,0 +1,74 @@
+package item
+
+import (
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/handlers/catalog"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCartContent(t *testing.T) {
+	cart := CartContent{
+		Items: []Item{
+			{ItemID: 1, CategoryID: 1001, SellerID: 2, Price: 100 * money.Unit, Quantity: 2},
+			{ItemID: 2, CategoryID: 3004, SellerID: 2, Price: 50 * money.Unit, Quantity: 1},
+		},
+		VasItems: map[uint][]ItemVasItem{
+			1: {{VasItemID: 10, Price: 10 * money.Unit, Quantity: 2}},
+			2: {{VasItemID: 10, Price: 10 * money.Unit, Quantity: 2}, {VasItemID: 11, Price: 5 * money.Unit, Quantity: 1}},
+		},
+	}
+
+	Convey("TEST total price counts the vas-items of every item", t, func() {
+		So(cart.TotalPrice(), ShouldEqual, 295*money.Unit)
+		So(CartContent{}.TotalPrice(), ShouldEqual, 0)
+	})
+
+	Convey("TEST all items from the same seller", t, func() {
+		So(cart.AreAllItemsFromSameSeller(), ShouldBeTrue)
+		So(CartContent{}.AreAllItemsFromSameSeller(), ShouldBeFalse)
+
+		differentSellers := CartContent{Items: append([]Item{{ItemID: 3, SellerID: 3}}, cart.Items...)}
+		So(differentSellers.AreAllItemsFromSameSeller(), ShouldBeFalse)
+	})
+
+	Convey("TEST items of a category", t, func() {
+		items := cart.ItemsOfCategory(3004)
+		So(items, ShouldHaveLength, 1)
+		So(items[0].ItemID, ShouldEqual, 2)
+		So(cart.ItemsOfCategory(7889), ShouldBeEmpty)
+	})
+
+	Convey("TEST items and vas-items are listed once", t, func() {
+		So(cart.ProductIDs(), ShouldResemble, []uint{1, 10, 2, 11})
+		So(CartContent{}.ProductIDs(), ShouldBeEmpty)
+	})
+
+	Convey("TEST find an item", t, func() {
+		itm, ok := cart.FindItem(2)
+		So(ok, ShouldBeTrue)
+		So(itm.Price, ShouldEqual, 50*money.Unit)
+
+		_, ok = cart.FindItem(3)
+		So(ok, ShouldBeFalse)
+	})
+
+	Convey("TEST catalog prices replace the prices of the lines", t, func() {
+		products := map[uint]catalog.Product{
+			1:  {ProductID: 1, Price: 120 * money.Unit},
+			10: {ProductID: 10, Price: 0},
+		}
+
+		priced := cart.WithCatalogPrices(products)
+		So(priced.Items[0].Price, ShouldEqual, 120*money.Unit)
+		So(priced.VasItems[1][0].Price, ShouldEqual, 0)
+		So(priced.VasItems[2][0].Price, ShouldEqual, 0)
+
+		// lines that are not in the catalog anymore keep their price
+		So(priced.Items[1].Price, ShouldEqual, 50*money.Unit)
+		So(priced.VasItems[2][1].Price, ShouldEqual, 5*money.Unit)
+
+		So(priced.TotalPrice(), ShouldEqual, 295*money.Unit)
+		So(cart.Items[0].Price, ShouldEqual, 100*money.Unit)
+	})
+}
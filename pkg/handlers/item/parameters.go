@@ -1,26 +1,38 @@
 package item
 
+type CartUriParams struct {
+	CartID uint `uri:"cart_id" binding:"required"`
+}
+
 type ItemUriParams struct {
+	CartUriParams
 	ItemID uint `uri:"item_id" binding:"required"`
 }
 
+// AddItemParams do not have the price, the category and the seller of the item, they are read from the catalog.
 type AddItemParams struct {
-	ItemID     uint    `json:"item_id" binding:"required"`
-	CategoryID uint    `json:"category_id" binding:"required"`
-	SellerID   uint    `json:"seller_id" binding:"required"`
-	Price      float64 `json:"price" binding:"required,min=1,max=500000"`
-	Quantity   uint    `json:"quantity" binding:"required,min=1,max=10"`
+	CartUriParams
+	ItemID   uint `json:"item_id" binding:"required"`
+	Quantity uint `json:"quantity" binding:"required,min=1,max=10"`
 }
 
+// AddVasItemParams do not have the price, the category and the seller of the vas-item, they are read from the catalog.
 type AddVasItemParams struct {
 	ItemUriParams
-	VasItemID  uint    `json:"vas_item_id" binding:"required"`
-	CategoryID uint    `json:"category_id" binding:"required"`
-	SellerID   uint    `json:"seller_id" binding:"required"`
-	Price      float64 `json:"price" binding:"required,min=1,max=500000"`
-	Quantity   uint    `json:"quantity" binding:"required,min=1,max=3"`
+	VasItemID uint `json:"vas_item_id" binding:"required"`
+	Quantity  uint `json:"quantity" binding:"required,min=1,max=3"`
+}
+
+type UpdateItemParams struct {
+	ItemUriParams
+	Quantity uint `json:"quantity" binding:"required,min=1,max=10"`
 }
 
 type RemoveItemParams struct {
 	ItemUriParams
 }
+
+type RemoveVasItemParams struct {
+	ItemUriParams
+	VasItemID uint `uri:"vas_item_id" binding:"required"`
+}
@@ -28,6 +28,7 @@ func NewDefaultItemRouter() ItemRouter {
 func (itr itemRouter) Register(group *gin.RouterGroup) {
 	itemGroup := group.Group("items")
 	itemGroup.POST("", itr.AddItemRoute)
+	itemGroup.PATCH(":item_id", itr.UpdateItemRoute)
 	itemGroup.DELETE(":item_id", itr.RemoveItemRoute)
 }
 
@@ -36,10 +37,18 @@ func (itr itemRouter) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
 }
 
 func (itr itemRouter) AddItemRoute(c *gin.Context) {
-	log := itr.formattedLogger(logger.GetInstance()).WithField("location", "AddItemRoute")
+	ctx := c.Request.Context()
+	log := itr.formattedLogger(logger.FromContext(ctx)).WithField("location", "AddItemRoute")
 
 	var params AddItemParams
 
+	if err := c.ShouldBindUri(&params.CartUriParams); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
 	if err := c.ShouldBindJSON(&params); err != nil {
 		readableErr := validator.GetValidatorMessages(err)
 		log.WithError(readableErr).Error("Could not bind parameters")
@@ -47,7 +56,7 @@ func (itr itemRouter) AddItemRoute(c *gin.Context) {
 		return
 	}
 
-	responder, err := itr.itemController.AddItem(params)
+	responder, err := itr.itemController.AddItem(ctx, params)
 	if err != nil {
 		c.JSON(apiresponse.Failed(err))
 		return
@@ -56,8 +65,38 @@ func (itr itemRouter) AddItemRoute(c *gin.Context) {
 	c.JSON(apiresponse.Created(responder))
 }
 
+func (itr itemRouter) UpdateItemRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := itr.formattedLogger(logger.FromContext(ctx)).WithField("location", "UpdateItemRoute")
+
+	var params UpdateItemParams
+
+	if err := c.ShouldBindUri(&params.ItemUriParams); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := itr.itemController.UpdateItem(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+
+	c.JSON(apiresponse.OK(responder))
+}
+
 func (itr itemRouter) RemoveItemRoute(c *gin.Context) {
-	log := itr.formattedLogger(logger.GetInstance()).WithField("location", "RemoveItemRoute")
+	ctx := c.Request.Context()
+	log := itr.formattedLogger(logger.FromContext(ctx)).WithField("location", "RemoveItemRoute")
 
 	var params RemoveItemParams
 
@@ -67,7 +106,7 @@ func (itr itemRouter) RemoveItemRoute(c *gin.Context) {
 		return
 	}
 
-	responder, err := itr.itemController.RemoveItem(params)
+	responder, err := itr.itemController.RemoveItem(ctx, params)
 	if err != nil {
 		c.JSON(apiresponse.Failed(err))
 		return
@@ -95,6 +134,7 @@ func NewDefaultVasItemRouter() VasItemRouter {
 func (vitr vasItemRouter) Register(group *gin.RouterGroup) {
 	vasItemGroup := group.Group("items/:item_id/vas-items")
 	vasItemGroup.POST("", vitr.AddVasItemRoute)
+	vasItemGroup.DELETE(":vas_item_id", vitr.RemoveVasItemRoute)
 }
 
 func (vitr vasItemRouter) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
@@ -102,7 +142,8 @@ func (vitr vasItemRouter) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
 }
 
 func (vitr vasItemRouter) AddVasItemRoute(c *gin.Context) {
-	log := vitr.formattedLogger(logger.GetInstance()).WithField("location", "AddVasItemRoute")
+	ctx := c.Request.Context()
+	log := vitr.formattedLogger(logger.FromContext(ctx)).WithField("location", "AddVasItemRoute")
 
 	var params AddVasItemParams
 
@@ -120,7 +161,7 @@ func (vitr vasItemRouter) AddVasItemRoute(c *gin.Context) {
 		return
 	}
 
-	responder, err := vitr.vasItemController.AddVasItem(params)
+	responder, err := vitr.vasItemController.AddVasItem(ctx, params)
 	if err != nil {
 		c.JSON(apiresponse.Failed(err))
 		return
@@ -128,3 +169,24 @@ func (vitr vasItemRouter) AddVasItemRoute(c *gin.Context) {
 
 	c.JSON(apiresponse.Created(responder))
 }
+
+func (vitr vasItemRouter) RemoveVasItemRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := vitr.formattedLogger(logger.FromContext(ctx)).WithField("location", "RemoveVasItemRoute")
+
+	var params RemoveVasItemParams
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		log.WithError(err).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+
+	responder, err := vitr.vasItemController.RemoveVasItem(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+
+	c.JSON(apiresponse.OK(responder))
+}
@@ -1,17 +1,29 @@
 package item
 
+import "checkoutProject/pkg/common/money"
+
+// LineTotal is the price of the line before discounts, FinalLineTotal is LineTotal minus the Discount allocated to the line.
+// PreviousPrice and PriceChange are set when the price of the catalog changed since the line was added, the previous
+// price of a line added for free is 0.
 type ItemResponse struct {
-	ItemID     uint              `json:"item_id"`
-	CategoryID uint              `json:"category_id"`
-	SellerID   uint              `json:"seller_id"`
-	Price      float64           `json:"price"`
-	Quantity   uint              `json:"quantity"`
-	VasItems   []VasItemResponse `json:"vas_items"`
+	ItemID         uint              `json:"item_id"`
+	CategoryID     uint              `json:"category_id"`
+	SellerID       uint              `json:"seller_id"`
+	Price          money.Amount      `json:"price"`
+	PreviousPrice  *money.Amount     `json:"previous_price,omitempty"`
+	PriceChange    string            `json:"price_change,omitempty"`
+	Quantity       uint              `json:"quantity"`
+	LineTotal      money.Amount      `json:"line_total"`
+	Discount       money.Amount      `json:"discount"`
+	FinalLineTotal money.Amount      `json:"final_line_total"`
+	VasItems       []VasItemResponse `json:"vas_items"`
 }
 
 type ItemSerializer struct {
-	Item     Item
-	VasItems []VasItemSerializer
+	Item          Item
+	PreviousPrice *money.Amount
+	Discount      money.Amount
+	VasItems      []VasItemSerializer
 }
 
 func (s ItemSerializer) Response() interface{} {
@@ -20,33 +32,62 @@ func (s ItemSerializer) Response() interface{} {
 		vasItems = append(vasItems, item.Response().(VasItemResponse))
 	}
 	return ItemResponse{
-		ItemID:     s.Item.ItemID,
-		CategoryID: s.Item.CategoryID,
-		SellerID:   s.Item.SellerID,
-		Price:      s.Item.Price,
-		Quantity:   s.Item.Quantity,
-		VasItems:   vasItems,
+		ItemID:         s.Item.ItemID,
+		CategoryID:     s.Item.CategoryID,
+		SellerID:       s.Item.SellerID,
+		Price:          s.Item.Price,
+		PreviousPrice:  s.PreviousPrice,
+		PriceChange:    priceChange(s.PreviousPrice, s.Item.Price),
+		Quantity:       s.Item.Quantity,
+		LineTotal:      s.Item.OrderPrice(),
+		Discount:       s.Discount,
+		FinalLineTotal: s.Item.OrderPrice() - s.Discount,
+		VasItems:       vasItems,
 	}
 }
 
 type VasItemResponse struct {
-	VasItemID  uint    `json:"vas_item_id"`
-	CategoryID uint    `json:"category_id"`
-	SellerID   uint    `json:"seller_id"`
-	Price      float64 `json:"price"`
-	Quantity   uint    `json:"quantity"`
+	VasItemID      uint          `json:"vas_item_id"`
+	CategoryID     uint          `json:"category_id"`
+	SellerID       uint          `json:"seller_id"`
+	Price          money.Amount  `json:"price"`
+	PreviousPrice  *money.Amount `json:"previous_price,omitempty"`
+	PriceChange    string        `json:"price_change,omitempty"`
+	Quantity       uint          `json:"quantity"`
+	LineTotal      money.Amount  `json:"line_total"`
+	Discount       money.Amount  `json:"discount"`
+	FinalLineTotal money.Amount  `json:"final_line_total"`
 }
 
 type VasItemSerializer struct {
-	VasItem VasItem
+	VasItem       ItemVasItem
+	PreviousPrice *money.Amount
+	Discount      money.Amount
 }
 
 func (s VasItemSerializer) Response() interface{} {
 	return VasItemResponse{
-		VasItemID:  s.VasItem.VasItemID,
-		CategoryID: s.VasItem.CategoryID,
-		SellerID:   s.VasItem.SellerID,
-		Price:      s.VasItem.Price,
-		Quantity:   s.VasItem.Quantity,
+		VasItemID:      s.VasItem.VasItemID,
+		CategoryID:     s.VasItem.CategoryID,
+		SellerID:       s.VasItem.SellerID,
+		Price:          s.VasItem.Price,
+		PreviousPrice:  s.PreviousPrice,
+		PriceChange:    priceChange(s.PreviousPrice, s.VasItem.Price),
+		Quantity:       s.VasItem.Quantity,
+		LineTotal:      s.VasItem.OrderPrice(),
+		Discount:       s.Discount,
+		FinalLineTotal: s.VasItem.OrderPrice() - s.Discount,
+	}
+}
+
+// priceChange is empty when the previous price is not set, the line has the price it was added with.
+func priceChange(previousPrice *money.Amount, price money.Amount) string {
+	switch {
+	case previousPrice == nil || *previousPrice == price:
+		return ""
+	case price > *previousPrice:
+		return PRICE_INCREASED
+	default:
+		return PRICE_DECREASED
 	}
 }
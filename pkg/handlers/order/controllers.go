@@ -0,0 +1,52 @@
+package order
+
+import (
+	"checkoutProject/pkg/common/apiresponse"
+	errs "checkoutProject/pkg/common/errors"
+	"checkoutProject/pkg/common/logger"
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type OrderController interface {
+	GetOrder(ctx context.Context, params GetOrderParams) (apiresponse.Responder, error)
+}
+
+type orderController struct {
+	orderManager OrderManager
+}
+
+func NewOrderController(orderManager OrderManager) OrderController {
+	return orderController{
+		orderManager: orderManager,
+	}
+}
+
+func NewDefaultOrderController() OrderController {
+	return NewOrderController(NewDefaultOrderManager())
+}
+
+func (c orderController) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
+	return l.WithFields(logrus.Fields{"api_version": "1", "controller": "order"})
+}
+
+func (c orderController) GetOrder(ctx context.Context, params GetOrderParams) (apiresponse.Responder, error) {
+	log := c.formattedLogger(logger.FromContext(ctx)).WithFields(logrus.Fields{
+		"location": "Get Order",
+	})
+
+	order, err := c.orderManager.WithContext(ctx).Get(OrderFilter{ID: params.OrderID})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Error("record not found")
+		return nil, errs.RecordNotFoundErr
+	}
+
+	if err != nil {
+		log.WithError(err).Error("error while querying the order")
+		return nil, errs.InternalServerErr
+	}
+
+	return OrderSerializer{Result: true, Message: OrderMessageSerializer{Order: order}}, nil
+}
@@ -0,0 +1,23 @@
+package order
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"gorm.io/gorm"
+)
+
+type OrderFilter struct {
+	ID     uint
+	CartID uint
+}
+
+func (f OrderFilter) ToQuery(q *gorm.DB) *gorm.DB {
+	q = db.OwnedCarts(q, "orders").Where(Order{
+		Model: gorm.Model{ID: f.ID},
+	})
+
+	if f.CartID != 0 {
+		q = q.Where("orders.cart_id = ?", f.CartID)
+	}
+
+	return q
+}
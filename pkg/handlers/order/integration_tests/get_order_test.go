@@ -0,0 +1,174 @@
+package integration_tests
+
+import (
+	"checkoutProject/pkg/bootstrap"
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/money"
+	"checkoutProject/pkg/common/testhelper"
+	"checkoutProject/pkg/handlers/order"
+	"encoding/json"
+	"fmt"
+	"github.com/appleboy/gofight/v2"
+	. "github.com/smartystreets/goconvey/convey"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type getOrderTest struct {
+	Name                    string
+	OrderID                 uint
+	IsGuest                 bool
+	GuestToken              string
+	ExpectedOrder           order.OrderMessageResponse
+	ExpectedResponseMessage string
+	WantCode                int
+}
+
+func TestGetOrder(t *testing.T) {
+	tests := []getOrderTest{
+		{
+			Name:    "Server should return 200 and the order with the vas-items under their items",
+			OrderID: 1,
+			ExpectedOrder: order.OrderMessageResponse{
+				OrderID: 1,
+				CartID:  1,
+				Items: []order.OrderItemResponse{
+					{
+						ItemID:     1,
+						CategoryID: 3003,
+						SellerID:   1,
+						Price:      money.FromFloat(100),
+						Quantity:   2,
+						VasItems:   []order.OrderVasItemResponse{},
+					},
+					{
+						ItemID:     2,
+						CategoryID: 3004,
+						SellerID:   2,
+						Price:      money.FromFloat(20.5),
+						Quantity:   1,
+						VasItems: []order.OrderVasItemResponse{
+							{
+								VasItemID:  1,
+								CategoryID: 3242,
+								SellerID:   5003,
+								Price:      money.FromFloat(7),
+								Quantity:   1,
+							},
+						},
+					},
+				},
+				TotalPrice:        money.FromFloat(217.5),
+				AppliedPromotions: []order.OrderPromotionResponse{{PromotionID: 5676, Discount: money.FromFloat(10)}},
+				TotalDiscount:     money.FromFloat(10),
+			},
+			WantCode: http.StatusOK,
+		},
+		{
+			Name:                    "Server should return 404 if the order does not exist",
+			OrderID:                 2,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+		{
+			Name:                    "Server should return 404 if the order belongs to the cart of another user",
+			OrderID:                 3,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+		{
+			Name:       "Server should return 200 and the order of the guest cart to its guest",
+			OrderID:    4,
+			IsGuest:    true,
+			GuestToken: testhelper.TEST_GUEST_TOKEN,
+			ExpectedOrder: order.OrderMessageResponse{
+				OrderID: 4,
+				CartID:  30,
+				Items: []order.OrderItemResponse{
+					{
+						ItemID:     1,
+						CategoryID: 1001,
+						SellerID:   7,
+						Price:      money.FromFloat(10),
+						Quantity:   1,
+						VasItems:   []order.OrderVasItemResponse{},
+					},
+				},
+				TotalPrice:        money.FromFloat(10),
+				AppliedPromotions: []order.OrderPromotionResponse{},
+			},
+			WantCode: http.StatusOK,
+		},
+		{
+			Name:                    "Server should return 404 if the guest does not send the guest token of the guest cart",
+			OrderID:                 4,
+			IsGuest:                 true,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+		{
+			Name:                    "Server should return 404 if the order belongs to a guest cart",
+			OrderID:                 4,
+			ExpectedResponseMessage: "record not found",
+			WantCode:                http.StatusNotFound,
+		},
+	}
+
+	db, err := TestDB.DB()
+	if err != nil {
+		t.Fatalf("error while getting db instance: %v", err)
+		return
+	}
+
+	testhelper.LoadFixtures(testhelper.DefaultPath, t, db)
+
+	r := bootstrap.SetupRouter()
+
+	for _, tt := range tests {
+		var response gofight.HTTPResponse
+
+		header := testhelper.AuthorizationHeader(testhelper.TEST_USER_ID)
+		if tt.IsGuest {
+			header = testhelper.GuestTokenHeader(tt.GuestToken)
+		}
+
+		t.Run(tt.Name, func(t *testing.T) {
+			gofight.New().
+				GET(fmt.Sprintf("/api/orders/%d", tt.OrderID)).
+				SetHeader(header).
+				Run(r, func(r gofight.HTTPResponse, request gofight.HTTPRequest) {
+					response = r
+				})
+
+			Convey("When client sends a request to get the order", t, func() {
+				Convey(fmt.Sprintf("Then server should return %d code", tt.WantCode), func() {
+					So(response.Code, ShouldEqual, tt.WantCode)
+				})
+
+				if response.Code != http.StatusOK {
+					var res apiresponse.GenericResponse
+					err := json.Unmarshal(response.Body.Bytes(), &res)
+					So(err, ShouldBeNil)
+
+					Convey("Then response should have Message field equal to expected result message", func() {
+						So(res.Message, ShouldEqual, tt.ExpectedResponseMessage)
+					})
+					return
+				}
+
+				var res order.OrderResponse
+				err := json.Unmarshal(response.Body.Bytes(), &res)
+				So(err, ShouldBeNil)
+
+				Convey("Then server should return the order", func() {
+					So(res.Result, ShouldBeTrue)
+					So(res.Message.CreatedAt, ShouldNotEqual, time.Time{})
+
+					res.Message.CreatedAt = time.Time{}
+					So(res.Message, ShouldResemble, tt.ExpectedOrder)
+				})
+			})
+		})
+	}
+}
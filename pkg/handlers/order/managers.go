@@ -0,0 +1,69 @@
+package order
+
+import (
+	db "checkoutProject/pkg/common/database"
+	"context"
+	"gorm.io/gorm"
+)
+
+type OrderManager interface {
+	Create(order Order) (Order, error)
+	WithTx(tx *gorm.DB) OrderManager
+	WithContext(ctx context.Context) OrderManager
+	Get(filter OrderFilter) (Order, error)
+}
+
+type orderManager struct {
+	db.BaseManager
+}
+
+func NewDefaultOrderManager() OrderManager {
+	return NewOrderManager(db.GetInstance())
+}
+
+func NewOrderManager(withDB *gorm.DB) OrderManager {
+	return orderManager{
+		BaseManager: db.NewBaseManager(withDB),
+	}
+}
+
+func (m orderManager) WithTx(tx *gorm.DB) OrderManager {
+	return orderManager{
+		BaseManager: m.BaseManager.WithTx(tx),
+	}
+}
+
+func (m orderManager) WithContext(ctx context.Context) OrderManager {
+	return orderManager{
+		BaseManager: m.BaseManager.WithContext(ctx),
+	}
+}
+
+// Create creates the order together with its lines and promotions.
+func (m orderManager) Create(order Order) (Order, error) {
+
+	if err := m.DB.Create(&order).Error; err != nil {
+		return Order{}, err
+	}
+
+	return order, nil
+}
+
+func (m orderManager) Get(filter OrderFilter) (Order, error) {
+	var order Order
+	query := filter.ToQuery(m.DB)
+
+	query = query.Model(&Order{}).
+		Preload("Lines", func(q *gorm.DB) *gorm.DB {
+			return q.Order("order_lines.id")
+		}).
+		Preload("Promotions", func(q *gorm.DB) *gorm.DB {
+			return q.Order("order_promotions.id")
+		})
+
+	if err := query.First(&order).Error; err != nil {
+		return Order{}, err
+	}
+
+	return order, nil
+}
@@ -0,0 +1,33 @@
+package order
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type mockOrderManagerImpl struct {
+	MCreate      func(order Order) (Order, error)
+	MWithTx      func(tx *gorm.DB) OrderManager
+	MWithContext func(ctx context.Context) OrderManager
+	MGet         func(filter OrderFilter) (Order, error)
+}
+
+func NewMockOrderManager() mockOrderManagerImpl {
+	return mockOrderManagerImpl{}
+}
+
+func (m mockOrderManagerImpl) Create(order Order) (Order, error) {
+	return m.MCreate(order)
+}
+
+func (m mockOrderManagerImpl) WithTx(tx *gorm.DB) OrderManager {
+	return m.MWithTx(tx)
+}
+
+func (m mockOrderManagerImpl) WithContext(ctx context.Context) OrderManager {
+	return m.MWithContext(ctx)
+}
+
+func (m mockOrderManagerImpl) Get(filter OrderFilter) (Order, error) {
+	return m.MGet(filter)
+}
@@ -0,0 +1,41 @@
+package order
+
+import (
+	"checkoutProject/pkg/common/money"
+	"gorm.io/gorm"
+)
+
+// Order is the snapshot of a cart taken at checkout, it is never updated after it is created.
+type Order struct {
+	gorm.Model
+	CartID        uint
+	TotalPrice    money.Amount
+	TotalDiscount money.Amount
+	Lines         []OrderLine      `gorm:"foreignKey:OrderID"`
+	Promotions    []OrderPromotion `gorm:"foreignKey:OrderID"`
+}
+
+// OrderLine is an item of the order, lines of the vas-items have the VasItemID and the ItemID of the item they belong to.
+type OrderLine struct {
+	gorm.Model
+	OrderID    uint
+	ItemID     uint
+	VasItemID  uint
+	CategoryID uint
+	SellerID   uint
+	Price      money.Amount
+	Quantity   uint
+}
+
+// OrderPromotion is a promotion or a coupon applied at checkout with the discount it gave.
+type OrderPromotion struct {
+	gorm.Model
+	OrderID     uint
+	PromotionID uint
+	CouponCode  string
+	Discount    money.Amount
+}
+
+func (line OrderLine) isVasItemLine() bool {
+	return line.VasItemID != 0
+}
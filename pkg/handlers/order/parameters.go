@@ -0,0 +1,5 @@
+package order
+
+type GetOrderParams struct {
+	OrderID uint `uri:"order_id" binding:"required"`
+}
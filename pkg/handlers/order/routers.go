@@ -0,0 +1,56 @@
+package order
+
+import (
+	"checkoutProject/pkg/common/apiresponse"
+	"checkoutProject/pkg/common/logger"
+	"checkoutProject/pkg/common/routing"
+	"checkoutProject/pkg/common/validator"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderRouter interface {
+	routing.Registerer
+}
+
+type orderRouter struct {
+	orderController OrderController
+}
+
+func NewOrderRouter(orderController OrderController) OrderRouter {
+	return orderRouter{orderController: orderController}
+}
+
+func NewDefaultOrderRouter() OrderRouter {
+	return NewOrderRouter(NewDefaultOrderController())
+}
+
+func (otr orderRouter) Register(group *gin.RouterGroup) {
+	orderGroup := group.Group("orders")
+	orderGroup.GET(":order_id", otr.GetOrderRoute)
+}
+
+func (otr orderRouter) formattedLogger(l logrus.FieldLogger) *logrus.Entry {
+	return l.WithField("router", "order")
+}
+
+func (otr orderRouter) GetOrderRoute(c *gin.Context) {
+	ctx := c.Request.Context()
+	log := otr.formattedLogger(logger.FromContext(ctx)).WithField("location", "GetOrderRoute")
+
+	var params GetOrderParams
+
+	if err := c.ShouldBindUri(&params); err != nil {
+		readableErr := validator.GetValidatorMessages(err)
+		log.WithError(readableErr).Error("Could not bind parameters")
+		c.JSON(apiresponse.Failed(readableErr))
+		return
+	}
+
+	responder, err := otr.orderController.GetOrder(ctx, params)
+	if err != nil {
+		c.JSON(apiresponse.Failed(err))
+		return
+	}
+	c.JSON(apiresponse.OK(responder))
+}
@@ -0,0 +1,111 @@
+package order
+
+import (
+	"checkoutProject/pkg/common/money"
+	"time"
+)
+
+type OrderResponse struct {
+	Result  bool                 `json:"result"`
+	Message OrderMessageResponse `json:"message"`
+}
+
+type OrderMessageResponse struct {
+	OrderID           uint                     `json:"order_id"`
+	CartID            uint                     `json:"cart_id"`
+	Items             []OrderItemResponse      `json:"items"`
+	TotalPrice        money.Amount             `json:"total_price"`
+	AppliedPromotions []OrderPromotionResponse `json:"applied_promotions"`
+	TotalDiscount     money.Amount             `json:"total_discount"`
+	CreatedAt         time.Time                `json:"created_at"`
+}
+
+type OrderPromotionResponse struct {
+	PromotionID uint         `json:"promotion_id"`
+	CouponCode  string       `json:"coupon_code,omitempty"`
+	Discount    money.Amount `json:"discount"`
+}
+
+type OrderItemResponse struct {
+	ItemID     uint                   `json:"item_id"`
+	CategoryID uint                   `json:"category_id"`
+	SellerID   uint                   `json:"seller_id"`
+	Price      money.Amount           `json:"price"`
+	Quantity   uint                   `json:"quantity"`
+	VasItems   []OrderVasItemResponse `json:"vas_items"`
+}
+
+type OrderVasItemResponse struct {
+	VasItemID  uint         `json:"vas_item_id"`
+	CategoryID uint         `json:"category_id"`
+	SellerID   uint         `json:"seller_id"`
+	Price      money.Amount `json:"price"`
+	Quantity   uint         `json:"quantity"`
+}
+
+type OrderSerializer struct {
+	Result  bool
+	Message OrderMessageSerializer
+}
+
+func (s OrderSerializer) Response() interface{} {
+	return OrderResponse{
+		Result:  s.Result,
+		Message: s.Message.Response().(OrderMessageResponse),
+	}
+}
+
+type OrderMessageSerializer struct {
+	Order Order
+}
+
+// Response groups the vas-item lines under the item lines they belong to.
+func (s OrderMessageSerializer) Response() interface{} {
+	orderItems := []OrderItemResponse{}
+	for _, line := range s.Order.Lines {
+		if line.isVasItemLine() {
+			continue
+		}
+
+		vasItems := []OrderVasItemResponse{}
+		for _, vasLine := range s.Order.Lines {
+			if vasLine.isVasItemLine() && vasLine.ItemID == line.ItemID {
+				vasItems = append(vasItems, OrderVasItemResponse{
+					VasItemID:  vasLine.VasItemID,
+					CategoryID: vasLine.CategoryID,
+					SellerID:   vasLine.SellerID,
+					Price:      vasLine.Price,
+					Quantity:   vasLine.Quantity,
+				})
+			}
+		}
+
+		orderItems = append(orderItems, OrderItemResponse{
+			ItemID:     line.ItemID,
+			CategoryID: line.CategoryID,
+			SellerID:   line.SellerID,
+			Price:      line.Price,
+			Quantity:   line.Quantity,
+			VasItems:   vasItems,
+		})
+	}
+
+	appliedPromotions := []OrderPromotionResponse{}
+	for _, promotion := range s.Order.Promotions {
+		appliedPromotions = append(appliedPromotions, OrderPromotionResponse{
+			PromotionID: promotion.PromotionID,
+			CouponCode:  promotion.CouponCode,
+			Discount:    promotion.Discount,
+		})
+	}
+
+	return OrderMessageResponse{
+		OrderID:           s.Order.ID,
+		CartID:            s.Order.CartID,
+		Items:             orderItems,
+		TotalPrice:        s.Order.TotalPrice,
+		AppliedPromotions: appliedPromotions,
+		TotalDiscount:     s.Order.TotalDiscount,
+		CreatedAt:         s.Order.CreatedAt,
+	}
+}